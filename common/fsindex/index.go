@@ -55,14 +55,7 @@ func (idx *Index) BuildIndex(rootDir string) error {
 
 		// Add the node to the index
 		idx.Trie.Insert(art.Key(node.Path), node)
-
-		if strings.Contains(relPath, ".wh.") {
-			idx.withoutFiles[relPath] = struct{}{}
-		}
-
-		if strings.Contains(relPath, ".wh..wh.") {
-			idx.withoutDirs[relPath] = struct{}{}
-		}
+		idx.trackWhiteout(node.Path)
 
 		return nil
 	})
@@ -75,7 +68,28 @@ func (idx *Index) BuildIndex(rootDir string) error {
 }
 
 func (idx *Index) AddNode(node *Node) {
-	idx.Trie.Insert(art.Key(node.Path), node)
+	idx.Trie.Insert(art.Key(cleanPath(node.Path)), node)
+}
+
+// Clone copies the index and its nodes, the join and the inode numbering change the nodes of the indexes they merge
+// so a layer index shared by several images is only ever joined through a clone
+func (idx *Index) Clone() *Index {
+	clone := NewFSIndex()
+	clone.IsComplete = idx.IsComplete
+	idx.Trie.ForEach(func(node art.NodeKV) bool {
+		if fsNode, ok := node.Value().(*Node); ok {
+			copied := *fsNode
+			clone.Trie.Insert(node.Key(), &copied)
+		}
+		return true
+	})
+	for path := range idx.withoutFiles {
+		clone.withoutFiles[path] = struct{}{}
+	}
+	for path := range idx.withoutDirs {
+		clone.withoutDirs[path] = struct{}{}
+	}
+	return clone
 }
 
 func collectFileAttributes(info os.FileInfo) FileAttributes {
@@ -107,7 +121,7 @@ func collectFileAttributes(info os.FileInfo) FileAttributes {
 
 // LookupPath looks up a path in the index
 func (idx *Index) LookupPath(path string) (*Node, error) {
-	path = filepath.ToSlash(filepath.Clean(path))
+	path = cleanPath(path)
 
 	value, found := idx.Trie.Search(art.Key(path))
 	if !found {
@@ -125,7 +139,7 @@ func (idx *Index) LookupPath(path string) (*Node, error) {
 // LookupPrefixSearch performs a prefix search on the index
 // Only returns immediate children (depth 1) of the given prefix
 func (idx *Index) LookupPrefixSearch(prefix string) []*Node {
-	prefix = filepath.ToSlash(filepath.Clean(prefix))
+	prefix = cleanPath(prefix)
 
 	var results []*Node
 	prefixKey := art.Key(prefix)
@@ -177,6 +191,7 @@ func (idx *Index) GetStats() map[string]interface{} {
 	}
 }
 
+// cleanPath normalizes a path into the key of its node: slash separated, absolute and without trailing slash
 func cleanPath(path string) string {
 	path = filepath.ToSlash(filepath.Clean(path))
 	if path != "" && !strings.HasSuffix(path, "/") {
@@ -187,22 +202,21 @@ func cleanPath(path string) string {
 
 // addPath adds a relative path to the index (for testing purposes)
 func (idx *Index) addPath(relPath string, info os.FileInfo) {
-	if !strings.HasPrefix(relPath, "/") {
-		relPath = "/" + relPath
-	}
-
 	node := &Node{
-		Path:       relPath,
+		Path:       cleanPath(relPath),
 		Attributes: collectFileAttributes(info),
 	}
 
-	idx.Trie.Insert(art.Key(relPath), node)
-
-	if strings.Contains(relPath, ".wh.") {
-		idx.withoutFiles[relPath] = struct{}{}
-	}
+	idx.Trie.Insert(art.Key(node.Path), node)
+	idx.trackWhiteout(node.Path)
+}
 
-	if strings.Contains(relPath, ".wh..wh.") {
-		idx.withoutDirs[relPath] = struct{}{}
+// trackWhiteout records the path as a file or directory whiteout when its name is one, path is a node key
+func (idx *Index) trackWhiteout(path string) {
+	switch whiteoutKind(filepath.Base(path)) {
+	case whiteoutFile:
+		idx.withoutFiles[path] = struct{}{}
+	case whiteoutOpaque:
+		idx.withoutDirs[path] = struct{}{}
 	}
 }
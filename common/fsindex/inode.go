@@ -0,0 +1,80 @@
+package fsindex
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+
+	art "github.com/alexisvisco/go-adaptive-radix-tree/v2"
+)
+
+const (
+	// firstInode is the lowest inode number assigned to a node, the lower ones are reserved for the mount root
+	firstInode = 2
+	// maxInode bounds the inode numbers assigned to the nodes, the higher ones are left to the FUSE library
+	maxInode = 1<<63 - 1
+)
+
+// inodeKey identifies the file of a node in the image: the nodes of a layer hardlinked together are one file
+type inodeKey struct {
+	layerPosition uint32
+	path          string
+	// hardlink tells the path names a group, an entry replacing the first of its group in the layer is another file
+	hardlink bool
+}
+
+func (f *Node) inodeKey() inodeKey {
+	if f.HardlinkGroup != "" {
+		return inodeKey{layerPosition: f.LayerPosition, path: f.HardlinkGroup, hardlink: true}
+	}
+	return inodeKey{layerPosition: f.LayerPosition, path: f.Path}
+}
+
+// AssignInodes numbers the files of a joined index. The inode of a file only depends on its layer position and path,
+// so it is stable across the servers and as the lower layers are joined, the hardlinks of a layer share an inode and
+// count the links still visible in the image.
+func (idx *Index) AssignInodes() {
+	inodes := make(map[inodeKey]uint64)
+	used := make(map[uint64]struct{})
+	links := make(map[inodeKey]uint64)
+
+	// the nodes are visited in the order of their path, a hash collision is always resolved the same way
+	idx.Trie.ForEach(func(node art.NodeKV) bool {
+		fsNode, ok := node.Value().(*Node)
+		if !ok {
+			return true
+		}
+
+		key := fsNode.inodeKey()
+		links[key]++
+
+		inode, ok := inodes[key]
+		if !ok {
+			inode = key.hash()
+			for _, collides := used[inode]; collides; _, collides = used[inode] {
+				inode = max(firstInode, (inode+1)&maxInode)
+			}
+			inodes[key] = inode
+			used[inode] = struct{}{}
+		}
+		fsNode.Attributes.Inode = inode
+
+		return true
+	})
+
+	idx.Trie.ForEach(func(node art.NodeKV) bool {
+		if fsNode, ok := node.Value().(*Node); ok && fsNode.HardlinkGroup != "" {
+			fsNode.Attributes.Nlink = links[fsNode.inodeKey()]
+		}
+		return true
+	})
+}
+
+func (k inodeKey) hash() uint64 {
+	h := fnv.New64a()
+	binary.Write(h, binary.BigEndian, uint64(k.layerPosition))
+	h.Write([]byte(k.path))
+	if k.hardlink {
+		h.Write([]byte{0})
+	}
+	return max(firstInode, h.Sum64()&maxInode)
+}
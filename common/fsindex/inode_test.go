@@ -0,0 +1,129 @@
+package fsindex
+
+import (
+	"archive/tar"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func addTarEntry(idx *Index, typeflag byte, name, linkname string) {
+	idx.AddTarEntry(&tar.Header{Typeflag: typeflag, Name: name, Linkname: linkname, Mode: 0644}, createMockFileInfo(typeflag == tar.TypeDir))
+}
+
+func inodeOf(t *testing.T, idx *Index, path string) uint64 {
+	node, err := idx.LookupPath(path)
+	require.NoError(t, err, path)
+	return node.Attributes.Inode
+}
+
+func nlinkOf(t *testing.T, idx *Index, path string) uint64 {
+	node, err := idx.LookupPath(path)
+	require.NoError(t, err, path)
+	return node.Attributes.Nlink
+}
+
+func TestTarLinksFormHardlinkGroups(t *testing.T) {
+	layer := NewFSIndex()
+	addTarEntry(layer, tar.TypeDir, "bin/", "")
+	addTarEntry(layer, tar.TypeReg, "bin/busybox", "")
+	addTarEntry(layer, tar.TypeLink, "bin/sh", "bin/busybox")
+	addTarEntry(layer, tar.TypeLink, "bin/ls", "bin/sh")
+	addTarEntry(layer, tar.TypeReg, "bin/other", "")
+
+	for _, path := range []string{"/bin/busybox", "/bin/sh", "/bin/ls"} {
+		node, err := layer.LookupPath(path)
+		require.NoError(t, err)
+		assert.Equal(t, "/bin/busybox", node.HardlinkGroup, path)
+	}
+
+	other, err := layer.LookupPath("/bin/other")
+	require.NoError(t, err)
+	assert.Empty(t, other.HardlinkGroup)
+
+	// the groups are kept by the serialized index
+	data, err := layer.Serialize()
+	require.NoError(t, err)
+	layer, err = Deserialize(data, true)
+	require.NoError(t, err)
+	ls, err := layer.LookupPath("/bin/ls")
+	require.NoError(t, err)
+	assert.Equal(t, "/bin/busybox", ls.HardlinkGroup)
+}
+
+func TestAssignInodesSharesHardlinks(t *testing.T) {
+	lower := NewFSIndex()
+	addTarEntry(lower, tar.TypeDir, "bin/", "")
+	addTarEntry(lower, tar.TypeReg, "bin/busybox", "")
+	addTarEntry(lower, tar.TypeLink, "bin/sh", "bin/busybox")
+	addTarEntry(lower, tar.TypeLink, "bin/ls", "bin/busybox")
+	addTarEntry(lower, tar.TypeReg, "bin/other", "")
+
+	upper := NewFSIndex()
+	addTarEntry(upper, tar.TypeReg, "bin/.wh.ls", "")
+	addTarEntry(upper, tar.TypeReg, "bin/other", "")
+
+	image := joinLayers([]*Index{lower, upper})
+	image.AssignInodes()
+
+	busybox := inodeOf(t, image, "/bin/busybox")
+	assert.Equal(t, busybox, inodeOf(t, image, "/bin/sh"))
+	assert.Equal(t, uint64(2), nlinkOf(t, image, "/bin/busybox"))
+	assert.Equal(t, uint64(2), nlinkOf(t, image, "/bin/sh"))
+
+	assert.NotEqual(t, busybox, inodeOf(t, image, "/bin/other"))
+	assert.NotEqual(t, busybox, inodeOf(t, image, "/bin"))
+}
+
+func TestAssignInodesIsUniqueAcrossLayers(t *testing.T) {
+	layers := buildLayers([][]string{
+		{"/etc/", "/etc/hosts", "/usr/", "/usr/lib/", "/usr/lib/libc.so"},
+		{"/etc/", "/etc/hostname", "/usr/lib/libm.so"},
+		{"/etc/hosts", "/usr/lib/libc.so", "/opt/"},
+	})
+
+	// every mock file info has the same host inode
+	image := joinLayers(layers)
+	image.AssignInodes()
+
+	inodes := make(map[uint64]string)
+	for path, node := range snapshot(image) {
+		inode := node.Attributes.Inode
+		assert.GreaterOrEqual(t, inode, uint64(firstInode), path)
+		assert.LessOrEqual(t, inode, uint64(maxInode), path)
+		require.NotContains(t, inodes, inode, "%s and %s share an inode", path, inodes[inode])
+		inodes[inode] = path
+	}
+}
+
+func TestAssignInodesIsStable(t *testing.T) {
+	paths := [][]string{
+		{"/etc/", "/etc/hosts", "/usr/", "/usr/bin/"},
+		{"/usr/bin/app", "/etc/hosts"},
+	}
+
+	// the inodes of the upper layer do not change once the lower layer is joined
+	layers := buildLayers(paths)
+	upper := layers[1]
+	for path := range snapshot(upper) {
+		node, err := upper.LookupPath(path)
+		require.NoError(t, err)
+		node.LayerPosition = 1
+	}
+	upper.AssignInodes()
+	app := inodeOf(t, upper, "/usr/bin/app")
+	hosts := inodeOf(t, upper, "/etc/hosts")
+
+	image := joinLayers(layers)
+	image.AssignInodes()
+	assert.Equal(t, app, inodeOf(t, image, "/usr/bin/app"))
+	assert.Equal(t, hosts, inodeOf(t, image, "/etc/hosts"))
+
+	// another server indexing the same image numbers it the same
+	other := joinLayers(buildLayers(paths))
+	other.AssignInodes()
+	for path, node := range snapshot(image) {
+		assert.Equal(t, node.Attributes.Inode, inodeOf(t, other, path), path)
+	}
+}
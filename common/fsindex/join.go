@@ -11,6 +11,8 @@ import (
 // It takes the previous layer and adds the new layer on top of it, overriding existing nodes.
 // It will remove every directory and file marked as "without" in the old layer.
 // This implements the overlay filesystem semantics used in container images.
+// Both indexes are changed: the merged layer receives the nodes and the whiteouts of the new layer, which are renumbered
+// to their layer on the first join, so the indexes shared between images must be cloned before being joined.
 //
 // Example to merge 3 layers:
 // LAYER 0
@@ -19,7 +21,7 @@ import (
 //
 // JoinFSIndex(LAYER 1, LAYER 2, 1, true) = LAYER 1 -> the merged layer
 // JoinFSIndex(LAYER 0, LAYER 1, 0, false) = LAYER 0 -> the merged layer
-func JoinFSIndex(currentLayerFSIndex, applyLayerFSIndex *Index, currentLayerPosition uint8, firstJoin bool) {
+func JoinFSIndex(currentLayerFSIndex, applyLayerFSIndex *Index, currentLayerPosition uint32, firstJoin bool) {
 	currentLayerFSIndex.Trie.ForEach(func(node art.NodeKV) (cont bool) {
 		fsNode, ok := node.Value().(*Node)
 		if ok {
@@ -42,93 +44,40 @@ func JoinFSIndex(currentLayerFSIndex, applyLayerFSIndex *Index, currentLayerPosi
 
 	// Process file whiteouts (.wh. files)
 	for filePath := range applyLayerFSIndex.withoutFiles {
-		// For a path like "a/b/c/.wh.file.json", we need to:
-		// 1. Get the directory path: "a/b/c/"
+		// For a path like "/a/b/c/.wh.file.json", we need to:
+		// 1. Get the directory path: "/a/b/c"
 		// 2. Get the filename: ".wh.file.json"
 		// 3. Remove the ".wh." prefix from the filename: "file.json"
-		// 4. Combine them back: "a/b/c/file.json"
-
-		dirPath := filepath.Dir(filePath)
+		// 4. Combine them back: "/a/b/c/file.json"
 		fileName := filepath.Base(filePath)
-
-		// Make sure we're actually dealing with a whiteout file
-		if !strings.HasPrefix(fileName, ".wh.") {
+		if whiteoutKind(fileName) != whiteoutFile {
 			continue
 		}
 
-		// Remove the ".wh." prefix from the filename
-		realFileName := strings.TrimPrefix(fileName, ".wh.")
+		realPath := filepath.Join(filepath.Dir(filePath), strings.TrimPrefix(fileName, whiteoutPrefix))
 
-		// Construct the real path that should be removed
-		var realPath string
-		if dirPath == "." {
-			realPath = realFileName
-		} else {
-			realPath = dirPath + "/" + realFileName
-		}
-
-		// Remove the target file from the previous layer
+		// Remove the target file from the previous layer, and all its children if it was a directory
 		currentLayerFSIndex.Trie.Delete(art.Key(realPath))
-
-		// Find all children of this path if it was a directory and remove them
-		prefixKey := art.Key(realPath + "/")
-		var keysToDelete []art.Key
-
-		// Find all keys that need to be deleted
-		currentLayerFSIndex.Trie.ForEachPrefix(prefixKey, func(node art.NodeKV) bool {
-			keysToDelete = append(keysToDelete, node.Key())
-			return true
-		})
-
-		// Delete all identified keys
-		for _, key := range keysToDelete {
-			currentLayerFSIndex.Trie.Delete(key)
-		}
+		deleteChildren(currentLayerFSIndex, realPath)
 	}
 
-	// Process directory opaque whiteouts (.wh..wh.)
-	for dirPath := range applyLayerFSIndex.withoutDirs {
-		// For a path like "a/b/c/.wh..wh.opq", we need to:
-		// 1. Get the directory path: "a/b/c/"
-		// 2. This directory should have its contents removed
-
-		// Get the parent directory that needs to be made opaque
-		opaqueDir := filepath.Dir(dirPath)
-
-		if opaqueDir == "." {
-			opaqueDir = ""
+	// Process directory opaque whiteouts (.wh..wh..opq)
+	for markerPath := range applyLayerFSIndex.withoutDirs {
+		if whiteoutKind(filepath.Base(markerPath)) != whiteoutOpaque {
+			continue
 		}
 
-		// For opaque directories, we keep the directory itself but remove all its contents
-		if opaqueDir != "" {
-			prefixKey := art.Key(opaqueDir + "/")
-			var keysToDelete []art.Key
-
-			// Find all keys that need to be deleted
-			currentLayerFSIndex.Trie.ForEachPrefix(prefixKey, func(node art.NodeKV) bool {
-				nodePath := string(node.Key())
-				// Don't delete the directory itself
-				if nodePath != opaqueDir && strings.HasPrefix(nodePath, string(prefixKey)) {
-					keysToDelete = append(keysToDelete, node.Key())
-				}
-				return true
-			})
-
-			// Delete all identified keys
-			for _, key := range keysToDelete {
-				currentLayerFSIndex.Trie.Delete(key)
-			}
-		}
+		// For a path like "/a/b/c/.wh..wh..opq", the directory "/a/b/c" keeps itself but loses all its contents, the
+		// marker at the root of the layer hides every lower entry
+		deleteChildren(currentLayerFSIndex, filepath.Dir(markerPath))
 	}
 
 	// Add or override files from the new layer
 	applyLayerFSIndex.Trie.ForEach(func(node art.NodeKV) bool {
 		fsNode, ok := node.Value().(*Node)
 		if ok {
-			fileName := filepath.Base(fsNode.Path)
-
 			// Skip whiteout files and opaque dir markers as they've already been processed
-			if strings.HasPrefix(fileName, ".wh.") {
+			if whiteoutKind(filepath.Base(fsNode.Path)) != notWhiteout {
 				return true
 			}
 
@@ -137,4 +86,33 @@ func JoinFSIndex(currentLayerFSIndex, applyLayerFSIndex *Index, currentLayerPosi
 		}
 		return true
 	})
+
+	// The whiteouts of the upper layers hide the entries of every lower layer, the merged layer carries them to the
+	// next join
+	for filePath := range applyLayerFSIndex.withoutFiles {
+		currentLayerFSIndex.withoutFiles[filePath] = struct{}{}
+	}
+	for markerPath := range applyLayerFSIndex.withoutDirs {
+		currentLayerFSIndex.withoutDirs[markerPath] = struct{}{}
+	}
+}
+
+// deleteChildren removes every descendant of the directory from the index, the directory itself is kept
+func deleteChildren(idx *Index, dirPath string) {
+	prefix := dirPath
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	var keysToDelete []art.Key
+	idx.Trie.ForEachPrefix(art.Key(prefix), func(node art.NodeKV) bool {
+		if string(node.Key()) != dirPath {
+			keysToDelete = append(keysToDelete, node.Key())
+		}
+		return true
+	})
+
+	for _, key := range keysToDelete {
+		idx.Trie.Delete(key)
+	}
 }
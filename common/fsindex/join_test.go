@@ -1,6 +1,7 @@
 package fsindex
 
 import (
+	"fmt"
 	"os"
 	"syscall"
 	"testing"
@@ -79,7 +80,7 @@ func TestJoinFSIndex(t *testing.T) {
 	layer1.addPath("file3.txt", createMockFileInfo(false))
 	layer1.addPath("file1.txt", createMockFileInfo(false))
 	layer1.addPath("dir1/.wh.subfile1.txt", createMockFileInfo(false))
-	layer1.addPath("dir2/.wh..wh.opq", createMockFileInfo(false))
+	layer1.addPath("dir2/.wh..wh..opq", createMockFileInfo(false))
 	layer1.addPath("dir2/newfile.txt", createMockFileInfo(false))
 
 	layer2 := NewFSIndex() // layer 2
@@ -101,12 +102,12 @@ func TestJoinFSIndex(t *testing.T) {
 	// Check that new files are added
 	file3, err := result.LookupPath("file3.txt")
 	require.NoError(t, err, "Expected file3.txt to be added")
-	assert.Equal(t, uint8(1), file3.LayerPosition)
+	assert.Equal(t, uint32(1), file3.LayerPosition)
 
 	// Check that updated files are properly updated
 	file1, err := result.LookupPath("file1.txt")
 	require.NoError(t, err, "Expected file1.txt to exist")
-	assert.Equal(t, uint8(1), file1.LayerPosition)
+	assert.Equal(t, uint32(1), file1.LayerPosition)
 
 	// Check that whiteout files are removed
 	_, err = result.LookupPath("dir1/subfile1.txt")
@@ -115,7 +116,7 @@ func TestJoinFSIndex(t *testing.T) {
 	// Check that non-whiteout files in the same directory still exist
 	subfile2, err := result.LookupPath("dir1/subfile2.txt")
 	require.NoError(t, err, "Expected dir1/subfile2.txt to still exist")
-	assert.Equal(t, uint8(0), subfile2.LayerPosition)
+	assert.Equal(t, uint32(0), subfile2.LayerPosition)
 
 	// Check that opaque directory's old content is removed
 	_, err = result.LookupPath("dir2/subfile1.txt")
@@ -124,7 +125,7 @@ func TestJoinFSIndex(t *testing.T) {
 	// Check that new content in opaque directory is added
 	newfile, err := result.LookupPath("dir2/newfile.txt")
 	require.NoError(t, err, "Expected dir2/newfile.txt to be added")
-	assert.Equal(t, uint8(1), newfile.LayerPosition)
+	assert.Equal(t, uint32(1), newfile.LayerPosition)
 
 	// Check that the opaque directory itself still exists
 	_, err = result.LookupPath("dir2")
@@ -134,8 +135,8 @@ func TestJoinFSIndex(t *testing.T) {
 	_, err = result.LookupPath("dir1/.wh.subfile1.txt")
 	assert.Error(t, err, "Expected whiteout file dir1/.wh.subfile1.txt to not be in the result")
 
-	_, err = result.LookupPath("dir2/.wh..wh.opq")
-	assert.Error(t, err, "Expected opaque whiteout file dir2/.wh..wh.opq to not be in the result")
+	_, err = result.LookupPath("dir2/.wh..wh..opq")
+	assert.Error(t, err, "Expected opaque whiteout file dir2/.wh..wh..opq to not be in the result")
 
 	// Check that the file file4 is not in the result
 	_, err = result.LookupPath("file4.txt")
@@ -144,5 +145,45 @@ func TestJoinFSIndex(t *testing.T) {
 	// Check that the file file5 is in the result
 	file5, err := result.LookupPath("file5.txt")
 	require.NoError(t, err, "Expected file5.txt to exist")
-	assert.Equal(t, uint8(2), file5.LayerPosition)
+	assert.Equal(t, uint32(2), file5.LayerPosition)
+}
+
+func TestJoinFSIndexDeepImage(t *testing.T) {
+	const layerCount = 300
+
+	// every layer adds a file of its own and replaces the shared file, the bottom layer holds the directory
+	layers := make([][]string, layerCount)
+	layers[0] = []string{"/layers/", "/shared"}
+	for position := 1; position < layerCount; position++ {
+		layers[position] = []string{fmt.Sprintf("/layers/%d", position), "/shared"}
+	}
+	layers[layerCount-1] = append(layers[layerCount-1], "/layers/.wh.1")
+
+	image := joinLayers(buildLayers(layers))
+	image.AssignInodes()
+
+	data, err := image.Serialize()
+	require.NoError(t, err)
+	image, err = Deserialize(data, true)
+	require.NoError(t, err)
+
+	shared, err := image.LookupPath("/shared")
+	require.NoError(t, err)
+	assert.Equal(t, uint32(layerCount-1), shared.LayerPosition)
+
+	for position := 2; position < layerCount; position++ {
+		node, err := image.LookupPath(fmt.Sprintf("/layers/%d", position))
+		require.NoError(t, err)
+		assert.Equal(t, uint32(position), node.LayerPosition)
+	}
+
+	// the whiteout of the top layer reaches a file of the second layer
+	_, err = image.LookupPath("/layers/1")
+	assert.Error(t, err)
+
+	inodes := make(map[uint64]string)
+	for path, node := range snapshot(image) {
+		require.NotContains(t, inodes, node.Attributes.Inode, "%s and %s share an inode", path, inodes[node.Attributes.Inode])
+		inodes[node.Attributes.Inode] = path
+	}
 }
@@ -3,8 +3,11 @@ package fsindex
 import (
 	"bytes"
 	"compress/zlib"
+	"errors"
 	"fmt"
 	"io"
+	"maps"
+	"slices"
 
 	art "github.com/alexisvisco/go-adaptive-radix-tree/v2"
 	fspb "github.com/baepo-cloud/viscaufs/common/proto/gen/v1"
@@ -12,14 +15,26 @@ import (
 )
 
 const (
-	currentVersion = uint32(1)
+	// versionWithoutWhiteouts indexes have no whiteout sets, they are recovered from the names of the paths
+	versionWithoutWhiteouts = uint32(1)
+	// versionNarrowPositions indexes were written with 8 bits layer positions. The positions of a layer index are
+	// assigned when it is joined, and the image indexes of the images deeper than 256 layers, whose positions
+	// wrapped, are dropped by the server database migration, so they are read as is.
+	versionNarrowPositions = uint32(2)
+	currentVersion         = uint32(3)
 )
 
+// ErrStaleIndex is returned for the image indexes of the first version: they were joined from layer indexes that had
+// lost their whiteouts, so they show deleted files and must be rebuilt from the layers
+var ErrStaleIndex = errors.New("stale image index")
+
 // Serialize serializes the Index into a FlatBuffer byte array
 func (idx *Index) Serialize() ([]byte, error) {
 	proto := &fspb.FSIndex{
-		Version: currentVersion,
-		Paths:   make([]*fspb.FSIndexNode, 0),
+		Version:     currentVersion,
+		Paths:       make([]*fspb.FSIndexNode, 0),
+		WithoutDir:  sortedKeys(idx.withoutDirs),
+		WithoutFile: sortedKeys(idx.withoutFiles),
 	}
 
 	idx.Trie.ForEach(func(node art.NodeKV) bool {
@@ -54,7 +69,7 @@ func (idx *Index) Serialize() ([]byte, error) {
 	return b.Bytes(), nil
 }
 
-// Deserialize deserializes the byte array into a Index
+// Deserialize deserializes the byte array into a Index, isComplete is set for the indexes of whole images
 func Deserialize(data []byte, isComplete bool) (*Index, error) {
 	r, err := zlib.NewReader(bytes.NewReader(data))
 	if err != nil {
@@ -72,19 +87,37 @@ func Deserialize(data []byte, isComplete bool) (*Index, error) {
 		return nil, fmt.Errorf("failed to unmarshal Index: %w", err)
 	}
 
-	if proto.Version != currentVersion {
+	if proto.Version != currentVersion && proto.Version != versionNarrowPositions && proto.Version != versionWithoutWhiteouts {
 		return nil, fmt.Errorf("unsupported Index version: %d", proto.Version)
 	}
-
-	idx := &Index{
-		Trie:       art.New(),
-		IsComplete: isComplete,
+	if isComplete && proto.Version == versionWithoutWhiteouts {
+		return nil, ErrStaleIndex
 	}
 
+	idx := NewFSIndex()
+	idx.IsComplete = isComplete
+
 	for _, nodeProto := range proto.Paths {
 		node := FSNodeFromProto(nodeProto)
 		idx.Trie.Insert(art.Key(node.Path), node)
+
+		// the whiteout nodes of a layer are kept in its index, they tell the whiteouts it had
+		if proto.Version == versionWithoutWhiteouts {
+			idx.trackWhiteout(node.Path)
+		}
+	}
+
+	for _, path := range proto.WithoutDir {
+		idx.withoutDirs[path] = struct{}{}
+	}
+	for _, path := range proto.WithoutFile {
+		idx.withoutFiles[path] = struct{}{}
 	}
 
 	return idx, nil
 }
+
+// sortedKeys returns the paths of a whiteout set in order, so an index always serializes the same
+func sortedKeys(set map[string]struct{}) []string {
+	return slices.Sorted(maps.Keys(set))
+}
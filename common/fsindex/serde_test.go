@@ -0,0 +1,168 @@
+package fsindex
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"math/rand/v2"
+	"testing"
+
+	art "github.com/alexisvisco/go-adaptive-radix-tree/v2"
+	fspb "github.com/baepo-cloud/viscaufs/common/proto/gen/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+// randomLayers generates layers whose entries collide across the layers: files and directories hiding the lower
+// ones, whiteouts of lower entries, opaque markers and names only containing the whiteout prefix
+func randomLayers(r *rand.Rand) [][]string {
+	names := []string{"a", "b", "lib", "lib64", "a.wh.b"}
+
+	var dirs []string
+	randomDir := func() string {
+		if len(dirs) == 0 || r.IntN(4) == 0 {
+			return ""
+		}
+		return dirs[r.IntN(len(dirs))]
+	}
+
+	layers := make([][]string, 2+r.IntN(4))
+	for i := range layers {
+		for range 1 + r.IntN(12) {
+			dir, name := randomDir(), names[r.IntN(len(names))]
+			switch r.IntN(6) {
+			case 0, 1:
+				layers[i] = append(layers[i], dir+"/"+name)
+			case 2, 3:
+				layers[i] = append(layers[i], dir+"/"+name+"/")
+				dirs = append(dirs, dir+"/"+name)
+			case 4:
+				layers[i] = append(layers[i], dir+"/"+whiteoutPrefix+name)
+			case 5:
+				layers[i] = append(layers[i], dir+"/"+whiteoutOpaqueDir)
+			}
+		}
+	}
+	return layers
+}
+
+// snapshot returns the nodes of the index by path
+func snapshot(idx *Index) map[string]Node {
+	nodes := make(map[string]Node)
+	idx.Trie.ForEach(func(node art.NodeKV) bool {
+		if fsNode, ok := node.Value().(*Node); ok {
+			nodes[string(node.Key())] = *fsNode
+		}
+		return true
+	})
+	return nodes
+}
+
+func TestSerializationKeepsTheJoin(t *testing.T) {
+	for seed := range uint64(500) {
+		r := rand.New(rand.NewPCG(seed, seed))
+		layers := randomLayers(r)
+
+		t.Run(fmt.Sprint(seed), func(t *testing.T) {
+			indexes := buildLayers(layers)
+			roundTripped := make([]*Index, len(indexes))
+			for i, index := range indexes {
+				data, err := index.Serialize()
+				require.NoError(t, err)
+				roundTripped[i], err = Deserialize(data, false)
+				require.NoError(t, err)
+
+				assert.Equal(t, index.withoutFiles, roundTripped[i].withoutFiles)
+				assert.Equal(t, index.withoutDirs, roundTripped[i].withoutDirs)
+			}
+
+			require.Equal(t, snapshot(joinLayers(indexes)), snapshot(joinLayers(roundTripped)), "layers: %q", layers)
+		})
+	}
+}
+
+func TestSerializationKeepsTheOwner(t *testing.T) {
+	layer := NewFSIndex()
+	layer.AddTarEntry(&tar.Header{Typeflag: tar.TypeReg, Name: "etc/shadow", Mode: 0640, Uid: 0, Gid: 42}, createMockFileInfo(false))
+
+	data, err := layer.Serialize()
+	require.NoError(t, err)
+	layer, err = Deserialize(data, false)
+	require.NoError(t, err)
+
+	shadow, err := layer.LookupPath("/etc/shadow")
+	require.NoError(t, err)
+	assert.Equal(t, uint32(0), shadow.Attributes.Owner.Uid)
+	assert.Equal(t, uint32(42), shadow.Attributes.Owner.Gid)
+}
+
+// serializeVersion1 serializes the index the way the first version of the format did, without the whiteout sets
+func serializeVersion1(t *testing.T, idx *Index) []byte {
+	proto := &fspb.FSIndex{Version: versionWithoutWhiteouts}
+	for _, node := range snapshot(idx) {
+		proto.Paths = append(proto.Paths, node.ToProto())
+	}
+
+	data, err := protobuf.Marshal(proto)
+	require.NoError(t, err)
+
+	var b bytes.Buffer
+	w := zlib.NewWriter(&b)
+	_, err = w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return b.Bytes()
+}
+
+func TestDeserializeMigratesVersion1(t *testing.T) {
+	paths := [][]string{
+		{"/etc/", "/etc/passwd", "/opt/", "/opt/file", "/usr/", "/usr/a.wh.b"},
+		{"/etc/.wh.passwd", "/opt/.wh..wh..opq", "/usr/.wh..wh.plnk", "/usr/a.wh.b"},
+	}
+	expected := snapshot(joinLayers(buildLayers(paths)))
+
+	layers := buildLayers(paths)
+
+	for i, layer := range layers {
+		var err error
+		layers[i], err = Deserialize(serializeVersion1(t, layer), false)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, map[string]struct{}{"/etc/.wh.passwd": {}}, layers[1].withoutFiles)
+	assert.Equal(t, map[string]struct{}{"/opt/.wh..wh..opq": {}}, layers[1].withoutDirs)
+
+	joined := snapshot(joinLayers(layers))
+	assert.Equal(t, len(expected), len(joined))
+	for path := range expected {
+		assert.Contains(t, joined, path)
+	}
+	assert.NotContains(t, joined, "/etc/passwd")
+	assert.NotContains(t, joined, "/opt/file")
+}
+
+func TestDeserializeRejectsVersion1ImageIndexes(t *testing.T) {
+	image := joinLayers(buildLayers([][]string{
+		{"/etc/", "/etc/passwd"},
+		{"/etc/.wh.passwd"},
+	}))
+
+	_, err := Deserialize(serializeVersion1(t, image), true)
+	assert.ErrorIs(t, err, ErrStaleIndex)
+}
+
+func TestDeserializeRejectsUnknownVersions(t *testing.T) {
+	data, err := protobuf.Marshal(&fspb.FSIndex{Version: currentVersion + 1})
+	require.NoError(t, err)
+
+	var b bytes.Buffer
+	w := zlib.NewWriter(&b)
+	_, err = w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	_, err = Deserialize(b.Bytes(), false)
+	assert.ErrorContains(t, err, "unsupported Index version")
+}
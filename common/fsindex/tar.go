@@ -0,0 +1,133 @@
+package fsindex
+
+import (
+	"archive/tar"
+	"os"
+	"strings"
+	"syscall"
+
+	art "github.com/alexisvisco/go-adaptive-radix-tree/v2"
+)
+
+// XattrPAXPrefix prefixes the PAX records of a tar header holding the extended attributes of the entry
+const XattrPAXPrefix = "SCHILY.xattr."
+
+// AddTarEntry adds a layer tar entry to the index.
+// The metadata (mode, ownership, timestamps, device numbers) comes from the tar header since the extracted copy
+// may not be able to hold it (e.g. extraction without root privileges), info is the lstat of the extracted
+// entry and provides the inode, link count and block usage.
+func (idx *Index) AddTarEntry(hdr *tar.Header, info os.FileInfo) *Node {
+	path := cleanPath(hdr.Name)
+
+	attributes := collectFileAttributes(info)
+	attributes.Mode = tarHeaderMode(hdr)
+	attributes.Owner.Uid = uint32(hdr.Uid)
+	attributes.Owner.Gid = uint32(hdr.Gid)
+	attributes.Mtime = hdr.ModTime.Unix()
+	attributes.Mtimensec = int64(hdr.ModTime.Nanosecond())
+	attributes.Atime, attributes.Atimensec = attributes.Mtime, attributes.Mtimensec
+	attributes.Ctime, attributes.Ctimensec = attributes.Mtime, attributes.Mtimensec
+	if !hdr.AccessTime.IsZero() {
+		attributes.Atime = hdr.AccessTime.Unix()
+		attributes.Atimensec = int64(hdr.AccessTime.Nanosecond())
+	}
+	if !hdr.ChangeTime.IsZero() {
+		attributes.Ctime = hdr.ChangeTime.Unix()
+		attributes.Ctimensec = int64(hdr.ChangeTime.Nanosecond())
+	}
+	if hdr.Typeflag == tar.TypeChar || hdr.Typeflag == tar.TypeBlock {
+		attributes.Rdev = mkdev(hdr.Devmajor, hdr.Devminor)
+	}
+
+	node := &Node{
+		Path:       path,
+		Attributes: attributes,
+		Xattrs:     tarHeaderXattrs(hdr),
+	}
+
+	// a hard link shares the metadata of its target, only the on-disk statistics are taken from info, and joins
+	// the group of the target
+	if hdr.Typeflag == tar.TypeLink {
+		if target, err := idx.LookupPath(cleanPath(hdr.Linkname)); err == nil {
+			inode, nlink, blocks, blksize := attributes.Inode, attributes.Nlink, attributes.Blocks, attributes.Blksize
+			node.Attributes = target.Attributes
+			node.Xattrs = target.Xattrs
+			node.Attributes.Inode, node.Attributes.Nlink = inode, nlink
+			node.Attributes.Blocks, node.Attributes.Blksize = blocks, blksize
+
+			if target.HardlinkGroup == "" {
+				target.HardlinkGroup = target.Path
+			}
+			node.HardlinkGroup = target.HardlinkGroup
+		}
+	}
+
+	if hdr.Typeflag == tar.TypeSymlink {
+		target := cleanPath(hdr.Linkname)
+		node.SymlinkTarget = &target
+	}
+
+	idx.Trie.Insert(art.Key(node.Path), node)
+	idx.trackWhiteout(node.Path)
+
+	return node
+}
+
+// AddFileInfo adds a path that has no tar entry of its own (e.g. an implicit parent directory) using its on-disk metadata
+func (idx *Index) AddFileInfo(path string, info os.FileInfo) *Node {
+	node := &Node{
+		Path:       cleanPath(path),
+		Attributes: collectFileAttributes(info),
+	}
+
+	idx.Trie.Insert(art.Key(node.Path), node)
+	idx.trackWhiteout(node.Path)
+
+	return node
+}
+
+// tarHeaderXattrs returns the extended attributes of the PAX records of the header
+func tarHeaderXattrs(hdr *tar.Header) map[string][]byte {
+	var xattrs map[string][]byte
+	for key, value := range hdr.PAXRecords {
+		name, ok := strings.CutPrefix(key, XattrPAXPrefix)
+		if !ok || name == "" {
+			continue
+		}
+
+		if xattrs == nil {
+			xattrs = make(map[string][]byte)
+		}
+		xattrs[name] = []byte(value)
+	}
+
+	return xattrs
+}
+
+// tarHeaderMode converts the tar header type and permission bits into a unix st_mode
+func tarHeaderMode(hdr *tar.Header) uint32 {
+	mode := uint32(hdr.Mode) & 07777
+
+	switch hdr.Typeflag {
+	case tar.TypeDir:
+		mode |= syscall.S_IFDIR
+	case tar.TypeSymlink:
+		mode |= syscall.S_IFLNK
+	case tar.TypeChar:
+		mode |= syscall.S_IFCHR
+	case tar.TypeBlock:
+		mode |= syscall.S_IFBLK
+	case tar.TypeFifo:
+		mode |= syscall.S_IFIFO
+	default:
+		mode |= syscall.S_IFREG
+	}
+
+	return mode
+}
+
+// mkdev encodes a device number the same way glibc makedev does
+func mkdev(major, minor int64) uint64 {
+	ma, mi := uint64(major), uint64(minor)
+	return (ma&0x00000fff)<<8 | (ma&0xfffff000)<<32 | (mi & 0x000000ff) | (mi&0xffffff00)<<12
+}
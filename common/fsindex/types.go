@@ -11,8 +11,12 @@ import (
 type Node struct {
 	Path          string
 	Attributes    FileAttributes
-	LayerPosition uint8
+	LayerPosition uint32
 	SymlinkTarget *string
+	// HardlinkGroup is the path of the first entry of the layer linked to the same file, empty when not hardlinked
+	HardlinkGroup string
+	// Xattrs are the extended attributes of the entry by name, nil when it has none
+	Xattrs map[string][]byte
 }
 
 type FileAttributes struct {
@@ -58,8 +62,10 @@ func (f *Node) ToProto() *fspb.FSIndexNode {
 	return &fspb.FSIndexNode{
 		Path:          f.Path,
 		Attributes:    f.FileAttributesToProto(),
-		LayerPosition: uint32(f.LayerPosition),
+		LayerPosition: f.LayerPosition,
 		SymlinkTarget: f.SymlinkTarget,
+		HardlinkGroup: f.HardlinkGroup,
+		Xattrs:        f.Xattrs,
 	}
 }
 
@@ -96,8 +102,15 @@ func FSFileAttrFromProto(attr *fspb.FileAttributes) FileAttributes {
 		Ctimensec: attr.Ctimensec,
 		Mode:      attr.Mode,
 		Nlink:     attr.Nlink,
-		Rdev:      attr.Rdev,
-		Blksize:   attr.Blksize,
+		Owner: struct {
+			Uid uint32
+			Gid uint32
+		}{
+			Uid: attr.Uid,
+			Gid: attr.Gid,
+		},
+		Rdev:    attr.Rdev,
+		Blksize: attr.Blksize,
 	}
 }
 
@@ -105,7 +118,9 @@ func FSNodeFromProto(node *fspb.FSIndexNode) *Node {
 	return &Node{
 		Path:          node.Path,
 		Attributes:    FSFileAttrFromProto(node.Attributes),
-		LayerPosition: uint8(node.LayerPosition),
+		LayerPosition: node.LayerPosition,
 		SymlinkTarget: node.SymlinkTarget,
+		HardlinkGroup: node.HardlinkGroup,
+		Xattrs:        node.Xattrs,
 	}
 }
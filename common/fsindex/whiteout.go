@@ -0,0 +1,41 @@
+package fsindex
+
+import "strings"
+
+// The whiteouts of the OCI image layers, only the name of an entry tells if it is one: a parent directory named
+// like a whiteout does not make its children whiteouts
+const (
+	// whiteoutPrefix prefixes the name of an entry hiding the entry of the same name without it in the lower layers
+	whiteoutPrefix = ".wh."
+	// whiteoutMetaPrefix prefixes the names reserved for the metadata of the layer, they hide nothing
+	whiteoutMetaPrefix = whiteoutPrefix + whiteoutPrefix
+	// whiteoutOpaqueDir is the name of the entry hiding all the children of its directory in the lower layers
+	whiteoutOpaqueDir = whiteoutMetaPrefix + ".opq"
+)
+
+type whiteout int
+
+const (
+	// notWhiteout is a regular entry of the layer
+	notWhiteout whiteout = iota
+	// whiteoutFile hides a file or a directory of the lower layers
+	whiteoutFile
+	// whiteoutOpaque hides the children of its directory in the lower layers
+	whiteoutOpaque
+	// whiteoutMeta is reserved, or names no entry, and hides nothing
+	whiteoutMeta
+)
+
+// whiteoutKind classifies an entry by its name, the base of its path
+func whiteoutKind(name string) whiteout {
+	switch {
+	case name == whiteoutOpaqueDir:
+		return whiteoutOpaque
+	case strings.HasPrefix(name, whiteoutMetaPrefix) || name == whiteoutPrefix:
+		return whiteoutMeta
+	case strings.HasPrefix(name, whiteoutPrefix):
+		return whiteoutFile
+	default:
+		return notWhiteout
+	}
+}
@@ -0,0 +1,266 @@
+package fsindex
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildLayers builds the index of each layer, bottom first, the paths ending with a slash are directories
+func buildLayers(layers [][]string) []*Index {
+	indexes := make([]*Index, len(layers))
+	for i, paths := range layers {
+		indexes[i] = NewFSIndex()
+		for _, path := range paths {
+			indexes[i].addPath(path, createMockFileInfo(strings.HasSuffix(path, "/")))
+		}
+	}
+	return indexes
+}
+
+// joinLayers joins the indexes of the layers, bottom first, the way the images are indexed: from the top layer down
+// to the bottom one
+func joinLayers(indexes []*Index) *Index {
+	image := indexes[len(indexes)-1]
+	for position := len(indexes) - 2; position >= 0; position-- {
+		JoinFSIndex(indexes[position], image, uint32(position), position == len(indexes)-2)
+		image = indexes[position]
+	}
+	return image
+}
+
+func TestWhiteoutKind(t *testing.T) {
+	tests := []struct {
+		name string
+		kind whiteout
+	}{
+		{name: "file", kind: notWhiteout},
+		{name: "a.wh.b", kind: notWhiteout},
+		{name: "file.wh.", kind: notWhiteout},
+		{name: ".wh.file", kind: whiteoutFile},
+		{name: ".wh..hidden", kind: whiteoutFile},
+		{name: ".wh..wh..opq", kind: whiteoutOpaque},
+		{name: ".wh..wh.plnk", kind: whiteoutMeta},
+		{name: ".wh..wh.opq", kind: whiteoutMeta},
+		{name: ".wh.", kind: whiteoutMeta},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.kind, whiteoutKind(tt.name))
+		})
+	}
+}
+
+func TestJoinWhiteouts(t *testing.T) {
+	tests := []struct {
+		name   string
+		layers [][]string
+		// present maps the paths of the joined index to the layer they come from
+		present map[string]uint32
+		absent  []string
+	}{
+		{
+			name: "file whiteout hides the lower file",
+			layers: [][]string{
+				{"/etc/", "/etc/passwd", "/etc/group"},
+				{"/etc/.wh.passwd"},
+			},
+			present: map[string]uint32{"/etc": 0, "/etc/group": 0},
+			absent:  []string{"/etc/passwd", "/etc/.wh.passwd"},
+		},
+		{
+			name: "file whiteout hides the lower directory and its children",
+			layers: [][]string{
+				{"/var/", "/var/cache/", "/var/cache/apt/", "/var/cache/apt/pkgcache.bin", "/var/lib/"},
+				{"/var/.wh.cache"},
+			},
+			present: map[string]uint32{"/var": 0, "/var/lib": 0},
+			absent:  []string{"/var/cache", "/var/cache/apt", "/var/cache/apt/pkgcache.bin"},
+		},
+		{
+			name: "file whiteout at the root of the layer",
+			layers: [][]string{
+				{"/tmp/", "/tmp/file", "/root/"},
+				{"/.wh.tmp"},
+			},
+			present: map[string]uint32{"/root": 0},
+			absent:  []string{"/tmp", "/tmp/file", "/.wh.tmp"},
+		},
+		{
+			name: "file whiteout does not hide the siblings sharing its prefix",
+			layers: [][]string{
+				{"/lib/", "/lib/file", "/lib64/", "/lib64/file", "/library"},
+				{"/.wh.lib"},
+			},
+			present: map[string]uint32{"/lib64": 0, "/lib64/file": 0, "/library": 0},
+			absent:  []string{"/lib", "/lib/file"},
+		},
+		{
+			name: "file whiteout hides only the lower layers",
+			layers: [][]string{
+				{"/app/", "/app/config"},
+				{"/app/.wh.config"},
+				{"/app/config"},
+			},
+			present: map[string]uint32{"/app": 0, "/app/config": 2},
+		},
+		{
+			name: "names containing the whiteout prefix are regular entries",
+			layers: [][]string{
+				{"/usr/", "/usr/share/", "/usr/share/b/", "/usr/share/b/file"},
+				{"/usr/share/a.wh.b/", "/usr/share/a.wh.b/file", "/usr/share/file.wh."},
+			},
+			present: map[string]uint32{
+				"/usr/share/b":           0,
+				"/usr/share/b/file":      0,
+				"/usr/share/a.wh.b":      1,
+				"/usr/share/a.wh.b/file": 1,
+				"/usr/share/file.wh.":    1,
+			},
+		},
+		{
+			name: "opaque directory hides the lower children and keeps the upper ones",
+			layers: [][]string{
+				{"/opt/", "/opt/app/", "/opt/app/old/", "/opt/app/old/file", "/opt/app/file", "/opt/other"},
+				{"/opt/app/", "/opt/app/.wh..wh..opq", "/opt/app/new"},
+			},
+			present: map[string]uint32{"/opt": 0, "/opt/other": 0, "/opt/app": 1, "/opt/app/new": 1},
+			absent:  []string{"/opt/app/old", "/opt/app/old/file", "/opt/app/file", "/opt/app/.wh..wh..opq"},
+		},
+		{
+			name: "opaque directory does not hide the siblings sharing its prefix",
+			layers: [][]string{
+				{"/data/", "/data/file", "/data2/", "/data2/file"},
+				{"/data/.wh..wh..opq"},
+			},
+			present: map[string]uint32{"/data": 0, "/data2": 0, "/data2/file": 0},
+			absent:  []string{"/data/file"},
+		},
+		{
+			name: "opaque marker at the root of the layer hides every lower entry",
+			layers: [][]string{
+				{"/bin/", "/bin/sh", "/etc/", "/etc/hosts"},
+				{"/.wh..wh..opq", "/etc/", "/etc/hostname"},
+			},
+			present: map[string]uint32{"/etc": 1, "/etc/hostname": 1},
+			absent:  []string{"/bin", "/bin/sh", "/etc/hosts", "/.wh..wh..opq"},
+		},
+		{
+			name: "reserved names hide nothing and are not added",
+			layers: [][]string{
+				{"/srv/", "/srv/file", "/srv/plnk"},
+				{"/srv/.wh..wh.plnk", "/srv/.wh..wh.opq"},
+			},
+			present: map[string]uint32{"/srv": 0, "/srv/file": 0, "/srv/plnk": 0},
+			absent:  []string{"/srv/.wh..wh.plnk", "/srv/.wh..wh.opq"},
+		},
+		{
+			name: "whiteouts of the middle layer apply to the bottom one",
+			layers: [][]string{
+				{"/home/", "/home/user/", "/home/user/file", "/home/other"},
+				{"/home/.wh.user", "/home/.wh.other"},
+				{"/home/other"},
+			},
+			present: map[string]uint32{"/home": 0, "/home/other": 2},
+			absent:  []string{"/home/user", "/home/user/file"},
+		},
+		{
+			name: "whiteouts of the top layer apply to every lower layer",
+			layers: [][]string{
+				{"/srv/", "/srv/old", "/srv/data/", "/srv/data/file"},
+				{"/srv/kept"},
+				{"/srv/middle"},
+				{"/srv/.wh.old", "/srv/data/.wh..wh..opq"},
+			},
+			present: map[string]uint32{"/srv": 0, "/srv/data": 0, "/srv/kept": 1, "/srv/middle": 2},
+			absent:  []string{"/srv/old", "/srv/data/file"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			index := joinLayers(buildLayers(tt.layers))
+
+			for path, position := range tt.present {
+				node, err := index.LookupPath(path)
+				require.NoError(t, err, "expected %s to be present", path)
+				assert.Equal(t, position, node.LayerPosition, "layer of %s", path)
+			}
+
+			for _, path := range tt.absent {
+				_, err := index.LookupPath(path)
+				assert.Error(t, err, "expected %s to be absent", path)
+			}
+		})
+	}
+}
+
+func TestLookupPathNormalizesKeys(t *testing.T) {
+	index := NewFSIndex()
+	index.addPath("dir/", createMockFileInfo(true))
+	index.addPath("dir/file", createMockFileInfo(false))
+
+	for _, path := range []string{"/dir/file", "dir/file", "/dir//file", "/dir/./file", "dir/sub/../file"} {
+		node, err := index.LookupPath(path)
+		require.NoError(t, err, path)
+		assert.Equal(t, "/dir/file", node.Path)
+	}
+
+	children := index.LookupPrefixSearch("dir/")
+	require.Len(t, children, 1)
+	assert.Equal(t, "/dir/file", children[0].Path)
+}
+
+func TestJoinCarriesTheWhiteoutsOnTheMergedIndex(t *testing.T) {
+	layers := buildLayers([][]string{
+		{"/srv/", "/srv/old", "/srv/data/", "/srv/data/file"},
+		{"/srv/kept"},
+		{"/srv/.wh.old", "/srv/data/.wh..wh..opq"},
+	})
+	middle := snapshot(layers[1])
+
+	// the merged index carries the whiteouts of the top layer down to the bottom one
+	merged := layers[2].Clone()
+	shared := layers[1].Clone()
+	JoinFSIndex(shared, merged, 1, true)
+	assert.Contains(t, shared.withoutFiles, "/srv/.wh.old")
+	assert.Contains(t, shared.withoutDirs, "/srv/data/.wh..wh..opq")
+
+	bottom := layers[0].Clone()
+	JoinFSIndex(bottom, shared, 0, false)
+	for _, path := range []string{"/srv/old", "/srv/data/file"} {
+		_, err := bottom.LookupPath(path)
+		assert.Error(t, err, "expected %s to be hidden", path)
+	}
+
+	// the layer indexes the clones were joined from are unchanged, another image sharing them sees its own files
+	assert.Empty(t, layers[1].withoutFiles)
+	assert.Empty(t, layers[1].withoutDirs)
+	assert.Equal(t, middle, snapshot(layers[1]))
+
+	other := joinLayers([]*Index{layers[0].Clone(), layers[1].Clone()})
+	for path, position := range map[string]uint32{"/srv/old": 0, "/srv/data/file": 0, "/srv/kept": 1} {
+		node, err := other.LookupPath(path)
+		require.NoError(t, err, "expected %s to be present", path)
+		assert.Equal(t, position, node.LayerPosition, "layer of %s", path)
+	}
+}
+
+func TestCloneCopiesTheNodes(t *testing.T) {
+	index := buildLayers([][]string{{"/etc/", "/etc/passwd", "/etc/.wh.group"}})[0]
+	clone := index.Clone()
+
+	node, err := clone.LookupPath("/etc/passwd")
+	require.NoError(t, err)
+	node.LayerPosition = 7
+	node.Attributes.Inode = 42
+
+	original, err := index.LookupPath("/etc/passwd")
+	require.NoError(t, err)
+	assert.Zero(t, original.LayerPosition)
+	assert.NotEqual(t, uint64(42), original.Attributes.Inode)
+	assert.Equal(t, index.withoutFiles, clone.withoutFiles)
+}
@@ -0,0 +1,54 @@
+package fsindex
+
+import (
+	"archive/tar"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTarEntriesKeepTheirXattrs(t *testing.T) {
+	capability := "\x01\x00\x00\x02\x00\x20\x00\x00"
+
+	layer := NewFSIndex()
+	layer.AddTarEntry(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     "usr/bin/ping",
+		Mode:     0755,
+		PAXRecords: map[string]string{
+			XattrPAXPrefix + "security.capability": capability,
+			XattrPAXPrefix + "user.empty":          "",
+			"mtime":                                "1700000000",
+		},
+	}, createMockFileInfo(false))
+	addTarEntry(layer, tar.TypeLink, "usr/bin/ping6", "usr/bin/ping")
+	addTarEntry(layer, tar.TypeReg, "usr/bin/plain", "")
+
+	expected := map[string][]byte{"security.capability": []byte(capability), "user.empty": {}}
+
+	ping, err := layer.LookupPath("/usr/bin/ping")
+	require.NoError(t, err)
+	assert.Equal(t, expected, ping.Xattrs)
+
+	// a hard link is the same file, it has the attributes of its target
+	ping6, err := layer.LookupPath("/usr/bin/ping6")
+	require.NoError(t, err)
+	assert.Equal(t, expected, ping6.Xattrs)
+
+	plain, err := layer.LookupPath("/usr/bin/plain")
+	require.NoError(t, err)
+	assert.Nil(t, plain.Xattrs)
+
+	// the attributes go through the serialization and the join of the layers
+	data, err := layer.Serialize()
+	require.NoError(t, err)
+	lower, err := Deserialize(data, false)
+	require.NoError(t, err)
+
+	image := joinLayers([]*Index{lower, buildLayers([][]string{{"/etc/hosts"}})[0]})
+	ping, err = image.LookupPath("/usr/bin/ping")
+	require.NoError(t, err)
+	assert.Equal(t, []byte(capability), ping.Xattrs["security.capability"])
+	assert.Contains(t, ping.Xattrs, "user.empty")
+}
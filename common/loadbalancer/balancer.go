@@ -0,0 +1,195 @@
+package loadbalancer
+
+import (
+	"cmp"
+	"context"
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	fspb "github.com/baepo-cloud/viscaufs/common/proto/gen/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/base"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Name is the name of the balancer routing the requests of an image digest to the same backend
+const Name = "image_digest_hash"
+
+// ringReplicas is the number of points of each backend on the hash ring, the more points the more even the images
+// are spread across the backends
+const ringReplicas = 128
+
+// fallbackBackends is the number of next backends of the ring a request carrying an image digest is retried on when
+// the backend of the image is unavailable or exhausted
+const fallbackBackends = 2
+
+func init() {
+	balancer.Register(base.NewBalancerBuilder(Name, &pickerBuilder{}, base.Config{HealthCheck: true}))
+}
+
+type (
+	imageDigestKey struct{}
+	fallbackKey    struct{}
+	backendKey     struct{}
+	pickedKey      struct{}
+)
+
+// handleBackends holds the address of the backend that opened each handle, by handle uid, only that backend knows
+// the handle
+var handleBackends sync.Map
+
+// WithImageDigest routes the requests made with the returned context to the backend of the image, for the requests
+// that do not carry the image digest themselves, like Read and Release
+func WithImageDigest(ctx context.Context, imageDigest string) context.Context {
+	return context.WithValue(ctx, imageDigestKey{}, imageDigest)
+}
+
+func imageDigestFromContext(ctx context.Context) string {
+	imageDigest, _ := ctx.Value(imageDigestKey{}).(string)
+	return imageDigest
+}
+
+// UnaryClientInterceptor routes the requests carrying an image digest to the backend of the image, unless the context
+// already names one. They are retried on the next backends of the ring when the backend of the image is unavailable
+// or exhausted. The requests on a handle, like Read and Release, go to the backend that opened it.
+func UnaryClientInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if request, ok := req.(interface{ GetUid() string }); ok {
+		if backend, ok := handleBackends.Load(request.GetUid()); ok {
+			if _, ok := req.(*fspb.ReleaseRequest); ok {
+				defer handleBackends.Delete(request.GetUid())
+			}
+			return invoker(context.WithValue(ctx, backendKey{}, backend), method, req, reply, cc, opts...)
+		}
+	}
+
+	if _, ok := ctx.Value(imageDigestKey{}).(string); !ok {
+		if request, ok := req.(interface{ GetImageDigest() string }); ok {
+			ctx = WithImageDigest(ctx, request.GetImageDigest())
+		}
+	}
+
+	// the requests without an image digest all go to the same backend, they have no other, and the opens stay on
+	// the backend of their session
+	fallbacks := fallbackBackends
+	if imageDigestFromContext(ctx) == "" {
+		fallbacks = 0
+	}
+	if request, ok := req.(interface{ GetSessionId() string }); ok && request.GetSessionId() != "" {
+		fallbacks = 0
+	}
+
+	var err error
+	for fallback := 0; fallback <= fallbacks; fallback++ {
+		picked := &atomic.Value{}
+		err = invoker(context.WithValue(context.WithValue(ctx, fallbackKey{}, fallback), pickedKey{}, picked), method, req, reply, cc, opts...)
+
+		if response, ok := reply.(*fspb.OpenResponse); ok && err == nil {
+			if backend, ok := picked.Load().(string); ok {
+				handleBackends.Store(response.GetUid(), backend)
+			}
+		}
+
+		if code := status.Code(err); code != codes.Unavailable && code != codes.ResourceExhausted || ctx.Err() != nil {
+			return err
+		}
+	}
+
+	return err
+}
+
+type pickerBuilder struct{}
+
+// Build places the ready backends on a consistent hash ring, when a backend fails only its images move to the next
+// backends of the ring, and come back once it is ready again
+func (b *pickerBuilder) Build(info base.PickerBuildInfo) balancer.Picker {
+	if len(info.ReadySCs) == 0 {
+		return base.NewErrPicker(balancer.ErrNoSubConnAvailable)
+	}
+
+	ring := make([]ringPoint, 0, len(info.ReadySCs)*ringReplicas)
+	for subConn, subConnInfo := range info.ReadySCs {
+		for replica := range ringReplicas {
+			ring = append(ring, ringPoint{
+				hash:    hashKey(subConnInfo.Address.Addr + "-" + strconv.Itoa(replica)),
+				addr:    subConnInfo.Address.Addr,
+				subConn: subConn,
+			})
+		}
+	}
+
+	// the address breaks the ties so the ring does not depend on the iteration order of the map
+	slices.SortFunc(ring, func(a, b ringPoint) int {
+		return cmp.Or(cmp.Compare(a.hash, b.hash), cmp.Compare(a.addr, b.addr))
+	})
+
+	backends := make(map[string]balancer.SubConn, len(info.ReadySCs))
+	for subConn, subConnInfo := range info.ReadySCs {
+		backends[subConnInfo.Address.Addr] = subConn
+	}
+
+	return &picker{ring: ring, backends: backends}
+}
+
+type ringPoint struct {
+	hash    uint64
+	addr    string
+	subConn balancer.SubConn
+}
+
+type picker struct {
+	ring     []ringPoint
+	backends map[string]balancer.SubConn // by address
+}
+
+// Pick routes the request to the first backend of the ring after the hash of its image digest, or to the following
+// distinct backends for its fallbacks, the requests without an image digest all go to the same backend. A request
+// naming its backend goes to it while it is ready.
+func (p *picker) Pick(info balancer.PickInfo) (balancer.PickResult, error) {
+	if backend, ok := info.Ctx.Value(backendKey{}).(string); ok {
+		if subConn, ok := p.backends[backend]; ok {
+			return balancer.PickResult{SubConn: subConn}, nil
+		}
+	}
+
+	hash := hashKey(imageDigestFromContext(info.Ctx))
+
+	index, _ := slices.BinarySearchFunc(p.ring, hash, func(point ringPoint, hash uint64) int {
+		return cmp.Compare(point.hash, hash)
+	})
+
+	// the fallbacks go to the next distinct backends of the ring, and wrap around when it has fewer
+	fallback, _ := info.Ctx.Value(fallbackKey{}).(int)
+	fallback %= len(p.backends)
+
+	var point ringPoint
+	seen := make(map[string]struct{}, fallback+1)
+	for i := index; len(seen) <= fallback; i++ {
+		point = p.ring[i%len(p.ring)]
+		seen[point.addr] = struct{}{}
+	}
+
+	if picked, ok := info.Ctx.Value(pickedKey{}).(*atomic.Value); ok {
+		picked.Store(point.addr)
+	}
+
+	return balancer.PickResult{SubConn: point.subConn}, nil
+}
+
+// hashKey hashes key with FNV-1a, mixed with the finalizer of SplitMix64 to spread the close keys of the ring
+func hashKey(key string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+
+	hash := h.Sum64()
+	hash ^= hash >> 30
+	hash *= 0xbf58476d1ce4e5b9
+	hash ^= hash >> 27
+	hash *= 0x94d049bb133111eb
+	hash ^= hash >> 31
+	return hash
+}
@@ -0,0 +1,265 @@
+package loadbalancer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	fspb "github.com/baepo-cloud/viscaufs/common/proto/gen/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// namedServer answers the GetAttr requests with its name as the file path, telling which backend served them, an
+// exhausted server refuses the GetAttr and Open requests
+type namedServer struct {
+	fspb.UnimplementedFuseServiceServer
+	name      string
+	exhausted atomic.Bool
+	handles   atomic.Int64
+}
+
+func (s *namedServer) GetAttr(context.Context, *fspb.GetAttrRequest) (*fspb.GetAttrResponse, error) {
+	if s.exhausted.Load() {
+		return nil, status.Error(codes.ResourceExhausted, "exhausted")
+	}
+	return &fspb.GetAttrResponse{File: &fspb.File{Path: s.name}}, nil
+}
+
+func (s *namedServer) Open(context.Context, *fspb.OpenRequest) (*fspb.OpenResponse, error) {
+	if s.exhausted.Load() {
+		return nil, status.Error(codes.ResourceExhausted, "exhausted")
+	}
+	return &fspb.OpenResponse{Uid: fmt.Sprintf("%s-%d", s.name, s.handles.Add(1))}, nil
+}
+
+func (s *namedServer) Read(context.Context, *fspb.ReadRequest) (*fspb.ReadResponse, error) {
+	return &fspb.ReadResponse{Data: []byte(s.name)}, nil
+}
+
+func (s *namedServer) Release(context.Context, *fspb.ReleaseRequest) (*fspb.ReleaseResponse, error) {
+	return &fspb.ReleaseResponse{}, nil
+}
+
+func startServers(t *testing.T, count int) ([]*grpc.Server, []int) {
+	servers, ports, _ := startNamedServers(t, count)
+	return servers, ports
+}
+
+func startNamedServers(t *testing.T, count int) ([]*grpc.Server, []int, map[string]*namedServer) {
+	servers := make([]*grpc.Server, count)
+	ports := make([]int, count)
+	named := make(map[string]*namedServer, count)
+	for i := range count {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+
+		server := &namedServer{name: ln.Addr().String()}
+		named[server.name] = server
+
+		servers[i] = grpc.NewServer()
+		fspb.RegisterFuseServiceServer(servers[i], server)
+		go servers[i].Serve(ln)
+		t.Cleanup(servers[i].Stop)
+
+		ports[i] = ln.Addr().(*net.TCPAddr).Port
+	}
+	return servers, ports, named
+}
+
+func newBalancedClient(t *testing.T, stub *dnsStub, name string) fspb.FuseServiceClient {
+	conn, err := grpc.NewClient(
+		"srv://"+stub.addr+"/"+name,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return fspb.NewFuseServiceClient(conn)
+}
+
+// backendOf returns the backend serving the requests of the image
+func backendOf(client fspb.FuseServiceClient, imageDigest string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := client.GetAttr(ctx, &fspb.GetAttrRequest{ImageDigest: imageDigest, Path: "/"}, grpc.WaitForReady(true))
+	if err != nil {
+		return "", err
+	}
+	return resp.File.Path, nil
+}
+
+// waitBackends waits for the client to be connected to count backends, and returns the backend of each digest
+func waitBackends(t *testing.T, client fspb.FuseServiceClient, digests []string, count int) map[string]string {
+	backendsOf := func() (map[string]string, error) {
+		backends := make(map[string]string)
+		for _, digest := range digests {
+			backend, err := backendOf(client, digest)
+			if err != nil {
+				return nil, err
+			}
+			backends[digest] = backend
+		}
+		return backends, nil
+	}
+
+	require.Eventually(t, func() bool {
+		backends, err := backendsOf()
+		unique := make(map[string]struct{})
+		for _, backend := range backends {
+			unique[backend] = struct{}{}
+		}
+		return err == nil && len(unique) == count
+	}, 5*time.Second, 10*time.Millisecond)
+
+	backends, err := backendsOf()
+	require.NoError(t, err)
+	return backends
+}
+
+func testDigests() []string {
+	digests := make([]string, 100)
+	for i := range digests {
+		digests[i] = fmt.Sprintf("sha256:%064x", i)
+	}
+	return digests
+}
+
+func TestImageDigestsStickToTheirBackend(t *testing.T) {
+	const service = "_viscaufs._tcp.viscaufs.test"
+
+	_, ports := startServers(t, 3)
+	stub := newDNSStub(t)
+	stub.setSRV(service, ports...)
+
+	client := newBalancedClient(t, stub, service)
+
+	digests := testDigests()
+	backends := waitBackends(t, client, digests, 3)
+
+	for _, digest := range digests {
+		backend, err := backendOf(client, digest)
+		require.NoError(t, err)
+		assert.Equal(t, backends[digest], backend)
+	}
+
+	// the requests without an image digest in their message follow the digest of their context
+	for _, digest := range digests[:10] {
+		resp, err := client.Read(WithImageDigest(context.Background(), digest), &fspb.ReadRequest{Uid: "uid"})
+		require.NoError(t, err)
+		assert.Equal(t, backends[digest], string(resp.Data))
+	}
+}
+
+func TestImageDigestsFallBackWhenTheirBackendFails(t *testing.T) {
+	const service = "_viscaufs._tcp.viscaufs.test"
+
+	servers, ports := startServers(t, 3)
+	stub := newDNSStub(t)
+	stub.setSRV(service, ports...)
+
+	client := newBalancedClient(t, stub, service)
+
+	digests := testDigests()
+	backends := waitBackends(t, client, digests, 3)
+
+	failed := fmt.Sprintf("127.0.0.1:%d", ports[0])
+	servers[0].Stop()
+
+	// the images of the failed backend move to the others, the images of the others stay in place
+	require.Eventually(t, func() bool {
+		for _, digest := range digests {
+			backend, err := backendOf(client, digest)
+			if err != nil || backend == failed || backends[digest] != failed && backend != backends[digest] {
+				return false
+			}
+		}
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestImageDigestsFallBackWhenTheirBackendIsExhausted(t *testing.T) {
+	const service = "_viscaufs._tcp.viscaufs.test"
+
+	_, ports, named := startNamedServers(t, 3)
+	stub := newDNSStub(t)
+	stub.setSRV(service, ports...)
+
+	client := newBalancedClient(t, stub, service)
+
+	digests := testDigests()
+	backends := waitBackends(t, client, digests, 3)
+
+	exhausted := backends[digests[0]]
+	named[exhausted].exhausted.Store(true)
+
+	// the images of the exhausted backend are served by the next backend of the ring, always the same one
+	fallbacks := make(map[string]string)
+	for _, digest := range digests {
+		backend, err := backendOf(client, digest)
+		require.NoError(t, err)
+		if backends[digest] != exhausted {
+			assert.Equal(t, backends[digest], backend)
+			continue
+		}
+		assert.NotEqual(t, exhausted, backend)
+		fallbacks[digest] = backend
+	}
+	for digest, fallback := range fallbacks {
+		backend, err := backendOf(client, digest)
+		require.NoError(t, err)
+		assert.Equal(t, fallback, backend)
+	}
+
+	// the requests fail once every backend is exhausted
+	for _, server := range named {
+		server.exhausted.Store(true)
+	}
+	_, err := backendOf(client, digests[0])
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestHandlesStickToTheBackendThatOpenedThem(t *testing.T) {
+	const service = "_viscaufs._tcp.viscaufs.test"
+
+	_, ports, named := startNamedServers(t, 3)
+	stub := newDNSStub(t)
+	stub.setSRV(service, ports...)
+
+	client := newBalancedClient(t, stub, service)
+
+	digests := testDigests()
+	backends := waitBackends(t, client, digests, 3)
+
+	// the handle is opened on the fallback backend of the image
+	named[backends[digests[0]]].exhausted.Store(true)
+	resp, err := client.Open(context.Background(), &fspb.OpenRequest{ImageDigest: digests[0], Path: "/file"})
+	require.NoError(t, err)
+	opener, ok := handleBackends.Load(resp.Uid)
+	require.True(t, ok)
+	assert.NotEqual(t, backends[digests[0]], opener)
+
+	// the reads on the handle go to its backend, whatever the image digest of their context
+	for _, digest := range digests[:10] {
+		read, err := client.Read(WithImageDigest(context.Background(), digest), &fspb.ReadRequest{Uid: resp.Uid})
+		require.NoError(t, err)
+		assert.Equal(t, opener, string(read.Data))
+	}
+
+	_, err = client.Release(WithImageDigest(context.Background(), digests[0]), &fspb.ReleaseRequest{Uid: resp.Uid})
+	require.NoError(t, err)
+	_, ok = handleBackends.Load(resp.Uid)
+	assert.False(t, ok, "released handles are forgotten")
+
+	// the opens in a session stay on the backend of the session
+	_, err = client.Open(context.Background(), &fspb.OpenRequest{ImageDigest: digests[0], Path: "/file", SessionId: "session"})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
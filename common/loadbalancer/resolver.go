@@ -0,0 +1,168 @@
+package loadbalancer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/resolver"
+)
+
+// Scheme is the scheme of the targets resolved from DNS SRV records: "srv:///_viscaufs._tcp.example.com", or
+// "srv://10.0.0.2:53/_viscaufs._tcp.example.com" to query a given DNS server
+const Scheme = "srv"
+
+const (
+	// refreshInterval is the delay between two lookups of the records
+	refreshInterval = 30 * time.Second
+	// minResolveInterval bounds the lookups asked by the channel when its connections fail
+	minResolveInterval = time.Second
+	lookupTimeout      = 10 * time.Second
+)
+
+// serviceConfig makes the channels of the resolved targets balance with the image digest picker
+var serviceConfig = fmt.Sprintf(`{"loadBalancingConfig": [{%q: {}}]}`, Name)
+
+func init() {
+	resolver.Register(&resolverBuilder{})
+}
+
+type resolverBuilder struct{}
+
+func (b *resolverBuilder) Scheme() string {
+	return Scheme
+}
+
+func (b *resolverBuilder) Build(target resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	name := target.Endpoint()
+	if name == "" {
+		return nil, fmt.Errorf("missing SRV record name in target %q", target.String())
+	}
+
+	dnsResolver := net.DefaultResolver
+	if authority := target.URL.Host; authority != "" {
+		if _, _, err := net.SplitHostPort(authority); err != nil {
+			authority = net.JoinHostPort(authority, "53")
+		}
+		dnsResolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, network, authority)
+			},
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &srvResolver{
+		name:       name,
+		resolver:   dnsResolver,
+		cc:         cc,
+		ctx:        ctx,
+		cancel:     cancel,
+		resolveNow: make(chan struct{}, 1),
+	}
+
+	r.wg.Add(1)
+	go r.watch()
+
+	return r, nil
+}
+
+// srvResolver looks up the SRV records of name and the addresses of their targets, each target port is a backend.
+// The priority and weight of the records are not used, every backend gets the same share of the images.
+type srvResolver struct {
+	name     string
+	resolver *net.Resolver
+	cc       resolver.ClientConn
+
+	ctx        context.Context
+	cancel     context.CancelFunc
+	resolveNow chan struct{}
+	wg         sync.WaitGroup
+}
+
+func (r *srvResolver) ResolveNow(resolver.ResolveNowOptions) {
+	select {
+	case r.resolveNow <- struct{}{}:
+	default:
+	}
+}
+
+func (r *srvResolver) Close() {
+	r.cancel()
+	r.wg.Wait()
+}
+
+func (r *srvResolver) watch() {
+	defer r.wg.Done()
+
+	for {
+		addresses, err := r.lookup()
+		if err != nil {
+			r.cc.ReportError(err)
+		} else {
+			// an error means the balancer could not use the addresses, they are looked up again on the next refresh
+			_ = r.cc.UpdateState(resolver.State{
+				Addresses:     addresses,
+				ServiceConfig: r.cc.ParseServiceConfig(serviceConfig),
+			})
+		}
+
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-time.After(minResolveInterval):
+		}
+
+		timer := time.NewTimer(refreshInterval - minResolveInterval)
+		select {
+		case <-r.ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		case <-r.resolveNow:
+			timer.Stop()
+		}
+	}
+}
+
+func (r *srvResolver) lookup() ([]resolver.Address, error) {
+	ctx, cancel := context.WithTimeout(r.ctx, lookupTimeout)
+	defer cancel()
+
+	_, records, err := r.resolver.LookupSRV(ctx, "", "", r.name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lookup SRV records of %s: %w", r.name, err)
+	}
+
+	var (
+		addresses []resolver.Address
+		lookupErr error
+	)
+	for _, record := range records {
+		hosts, err := r.resolver.LookupHost(ctx, record.Target)
+		if err != nil {
+			lookupErr = fmt.Errorf("failed to lookup SRV target %s: %w", record.Target, err)
+			continue
+		}
+
+		for _, host := range hosts {
+			addresses = append(addresses, resolver.Address{
+				Addr: net.JoinHostPort(host, strconv.Itoa(int(record.Port))),
+			})
+		}
+	}
+
+	if len(addresses) == 0 {
+		if lookupErr != nil {
+			return nil, lookupErr
+		}
+		return nil, fmt.Errorf("no SRV target found for %s", r.name)
+	}
+
+	return addresses, nil
+}
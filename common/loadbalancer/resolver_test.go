@@ -0,0 +1,209 @@
+package loadbalancer
+
+import (
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/dns/dnsmessage"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/serviceconfig"
+)
+
+// dnsStub is an in-process DNS server answering the SRV records of a service, and A records of 127.0.0.1 for their
+// targets
+type dnsStub struct {
+	addr string
+
+	mutex   sync.Mutex
+	records map[string][]dnsmessage.SRVResource // by lowercase fully qualified name
+}
+
+func newDNSStub(t *testing.T) *dnsStub {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	stub := &dnsStub{addr: conn.LocalAddr().String(), records: make(map[string][]dnsmessage.SRVResource)}
+	go stub.serve(conn)
+	return stub
+}
+
+// setSRV replaces the SRV records of name with a record for each port, targets are "<index>.<name>"
+func (s *dnsStub) setSRV(name string, ports ...int) {
+	records := make([]dnsmessage.SRVResource, 0, len(ports))
+	for i, port := range ports {
+		records = append(records, dnsmessage.SRVResource{
+			Priority: 10,
+			Weight:   10,
+			Port:     uint16(port),
+			Target:   dnsmessage.MustNewName(fqdn(string(rune('a'+i)) + "." + name)),
+		})
+	}
+
+	s.mutex.Lock()
+	s.records[fqdn(name)] = records
+	s.mutex.Unlock()
+}
+
+func (s *dnsStub) serve(conn net.PacketConn) {
+	buffer := make([]byte, 512)
+	for {
+		n, addr, err := conn.ReadFrom(buffer)
+		if err != nil {
+			return
+		}
+
+		if response, err := s.answer(buffer[:n]); err == nil {
+			conn.WriteTo(response, addr)
+		}
+	}
+}
+
+func (s *dnsStub) answer(query []byte) ([]byte, error) {
+	var parser dnsmessage.Parser
+	header, err := parser.Start(query)
+	if err != nil {
+		return nil, err
+	}
+	question, err := parser.Question()
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.ToLower(question.Name.String())
+
+	s.mutex.Lock()
+	records := s.records[name]
+	var isTarget bool
+	for _, serviceRecords := range s.records {
+		for _, record := range serviceRecords {
+			isTarget = isTarget || strings.ToLower(record.Target.String()) == name
+		}
+	}
+	s.mutex.Unlock()
+
+	responseHeader := dnsmessage.Header{ID: header.ID, Response: true, Authoritative: true, RecursionDesired: header.RecursionDesired}
+	if len(records) == 0 && !isTarget {
+		responseHeader.RCode = dnsmessage.RCodeNameError
+	}
+
+	builder := dnsmessage.NewBuilder(nil, responseHeader)
+	builder.EnableCompression()
+	if err := builder.StartQuestions(); err != nil {
+		return nil, err
+	}
+	if err := builder.Question(question); err != nil {
+		return nil, err
+	}
+	if err := builder.StartAnswers(); err != nil {
+		return nil, err
+	}
+
+	resourceHeader := dnsmessage.ResourceHeader{Name: question.Name, Type: question.Type, Class: dnsmessage.ClassINET, TTL: 1}
+	switch {
+	case question.Type == dnsmessage.TypeSRV:
+		for _, record := range records {
+			if err := builder.SRVResource(resourceHeader, record); err != nil {
+				return nil, err
+			}
+		}
+	case question.Type == dnsmessage.TypeA && isTarget:
+		if err := builder.AResource(resourceHeader, dnsmessage.AResource{A: [4]byte{127, 0, 0, 1}}); err != nil {
+			return nil, err
+		}
+	}
+
+	return builder.Finish()
+}
+
+func fqdn(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, ".")) + "."
+}
+
+// stateRecorder is a resolver.ClientConn keeping the states the resolver updates
+type stateRecorder struct {
+	resolver.ClientConn
+
+	states chan resolver.State
+	errors chan error
+}
+
+func (r *stateRecorder) UpdateState(state resolver.State) error {
+	r.states <- state
+	return nil
+}
+
+func (r *stateRecorder) ReportError(err error) {
+	r.errors <- err
+}
+
+func (r *stateRecorder) ParseServiceConfig(string) *serviceconfig.ParseResult {
+	return &serviceconfig.ParseResult{}
+}
+
+func buildResolver(t *testing.T, target string) (resolver.Resolver, *stateRecorder) {
+	targetURL, err := url.Parse(target)
+	require.NoError(t, err)
+
+	recorder := &stateRecorder{states: make(chan resolver.State, 10), errors: make(chan error, 10)}
+	r, err := resolver.Get(Scheme).Build(resolver.Target{URL: *targetURL}, recorder, resolver.BuildOptions{})
+	require.NoError(t, err)
+	t.Cleanup(r.Close)
+
+	return r, recorder
+}
+
+func TestResolverLooksUpSRVRecords(t *testing.T) {
+	stub := newDNSStub(t)
+	stub.setSRV("_viscaufs._tcp.viscaufs.test", 9001, 9002)
+
+	r, recorder := buildResolver(t, "srv://"+stub.addr+"/_viscaufs._tcp.viscaufs.test")
+
+	select {
+	case state := <-recorder.states:
+		var addrs []string
+		for _, address := range state.Addresses {
+			addrs = append(addrs, address.Addr)
+		}
+		assert.ElementsMatch(t, []string{"127.0.0.1:9001", "127.0.0.1:9002"}, addrs)
+		assert.NotNil(t, state.ServiceConfig)
+	case err := <-recorder.errors:
+		t.Fatalf("unexpected resolver error: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("resolver did not update the state")
+	}
+
+	// the records are looked up again when the channel asks for it
+	stub.setSRV("_viscaufs._tcp.viscaufs.test", 9003)
+	r.ResolveNow(resolver.ResolveNowOptions{})
+	select {
+	case state := <-recorder.states:
+		require.Len(t, state.Addresses, 1)
+		assert.Equal(t, "127.0.0.1:9003", state.Addresses[0].Addr)
+	case err := <-recorder.errors:
+		t.Fatalf("unexpected resolver error: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("resolver did not update the state")
+	}
+}
+
+func TestResolverReportsMissingRecords(t *testing.T) {
+	stub := newDNSStub(t)
+
+	_, recorder := buildResolver(t, "srv://"+stub.addr+"/_viscaufs._tcp.missing.test")
+
+	select {
+	case state := <-recorder.states:
+		t.Fatalf("unexpected resolver state: %v", state)
+	case err := <-recorder.errors:
+		assert.ErrorContains(t, err, "_viscaufs._tcp.missing.test")
+	case <-time.After(5 * time.Second):
+		t.Fatal("resolver did not report an error")
+	}
+}
@@ -20,6 +20,77 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type ImageEventKind int32
+
+const (
+	ImageEventKind_IMAGE_EVENT_KIND_UNSPECIFIED ImageEventKind = 0
+	// the layer is part of the image and will be prepared
+	ImageEventKind_IMAGE_EVENT_KIND_LAYER_RESOLVED ImageEventKind = 1
+	// the layer is being downloaded, bytes_downloaded out of bytes_total (compressed sizes)
+	ImageEventKind_IMAGE_EVENT_KIND_LAYER_DOWNLOADING ImageEventKind = 2
+	// the layer content is extracted on the server
+	ImageEventKind_IMAGE_EVENT_KIND_LAYER_EXTRACTED ImageEventKind = 3
+	// the layer filesystem index is built and stored
+	ImageEventKind_IMAGE_EVENT_KIND_LAYER_INDEXED ImageEventKind = 4
+	// the layer index is joined into the image index, the image can be mounted from the first joined layer
+	ImageEventKind_IMAGE_EVENT_KIND_LAYER_JOINED ImageEventKind = 5
+	// the image index is complete, terminal event
+	ImageEventKind_IMAGE_EVENT_KIND_READY ImageEventKind = 6
+	// the preparation failed with error, terminal event
+	ImageEventKind_IMAGE_EVENT_KIND_FAILED ImageEventKind = 7
+)
+
+// Enum value maps for ImageEventKind.
+var (
+	ImageEventKind_name = map[int32]string{
+		0: "IMAGE_EVENT_KIND_UNSPECIFIED",
+		1: "IMAGE_EVENT_KIND_LAYER_RESOLVED",
+		2: "IMAGE_EVENT_KIND_LAYER_DOWNLOADING",
+		3: "IMAGE_EVENT_KIND_LAYER_EXTRACTED",
+		4: "IMAGE_EVENT_KIND_LAYER_INDEXED",
+		5: "IMAGE_EVENT_KIND_LAYER_JOINED",
+		6: "IMAGE_EVENT_KIND_READY",
+		7: "IMAGE_EVENT_KIND_FAILED",
+	}
+	ImageEventKind_value = map[string]int32{
+		"IMAGE_EVENT_KIND_UNSPECIFIED":       0,
+		"IMAGE_EVENT_KIND_LAYER_RESOLVED":    1,
+		"IMAGE_EVENT_KIND_LAYER_DOWNLOADING": 2,
+		"IMAGE_EVENT_KIND_LAYER_EXTRACTED":   3,
+		"IMAGE_EVENT_KIND_LAYER_INDEXED":     4,
+		"IMAGE_EVENT_KIND_LAYER_JOINED":      5,
+		"IMAGE_EVENT_KIND_READY":             6,
+		"IMAGE_EVENT_KIND_FAILED":            7,
+	}
+)
+
+func (x ImageEventKind) Enum() *ImageEventKind {
+	p := new(ImageEventKind)
+	*p = x
+	return p
+}
+
+func (x ImageEventKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ImageEventKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_v1_rpc_proto_enumTypes[0].Descriptor()
+}
+
+func (ImageEventKind) Type() protoreflect.EnumType {
+	return &file_v1_rpc_proto_enumTypes[0]
+}
+
+func (x ImageEventKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ImageEventKind.Descriptor instead.
+func (ImageEventKind) EnumDescriptor() ([]byte, []int) {
+	return file_v1_rpc_proto_rawDescGZIP(), []int{0}
+}
+
 type File struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -89,6 +160,10 @@ type PrepareImageRequest struct {
 	unknownFields protoimpl.UnknownFields
 
 	ImageRef string `protobuf:"bytes,1,opt,name=image_ref,json=imageRef,proto3" json:"image_ref,omitempty"`
+	// registry_token is an optional bearer token used to pull the image from its registry
+	RegistryToken string `protobuf:"bytes,2,opt,name=registry_token,json=registryToken,proto3" json:"registry_token,omitempty"`
+	// platform selects the image of a multi-arch image index, the server default is used when unset
+	Platform *Platform `protobuf:"bytes,3,opt,name=platform,proto3" json:"platform,omitempty"`
 }
 
 func (x *PrepareImageRequest) Reset() {
@@ -130,16 +205,33 @@ func (x *PrepareImageRequest) GetImageRef() string {
 	return ""
 }
 
-type PrepareImageResponse struct {
+func (x *PrepareImageRequest) GetRegistryToken() string {
+	if x != nil {
+		return x.RegistryToken
+	}
+	return ""
+}
+
+func (x *PrepareImageRequest) GetPlatform() *Platform {
+	if x != nil {
+		return x.Platform
+	}
+	return nil
+}
+
+// Platform identifies the platform of an image, os and architecture are both required
+type Platform struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ImageDigest string `protobuf:"bytes,1,opt,name=image_digest,json=imageDigest,proto3" json:"image_digest,omitempty"`
+	Os           string `protobuf:"bytes,1,opt,name=os,proto3" json:"os,omitempty"`
+	Architecture string `protobuf:"bytes,2,opt,name=architecture,proto3" json:"architecture,omitempty"`
+	Variant      string `protobuf:"bytes,3,opt,name=variant,proto3" json:"variant,omitempty"`
 }
 
-func (x *PrepareImageResponse) Reset() {
-	*x = PrepareImageResponse{}
+func (x *Platform) Reset() {
+	*x = Platform{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_v1_rpc_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -147,13 +239,13 @@ func (x *PrepareImageResponse) Reset() {
 	}
 }
 
-func (x *PrepareImageResponse) String() string {
+func (x *Platform) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PrepareImageResponse) ProtoMessage() {}
+func (*Platform) ProtoMessage() {}
 
-func (x *PrepareImageResponse) ProtoReflect() protoreflect.Message {
+func (x *Platform) ProtoReflect() protoreflect.Message {
 	mi := &file_v1_rpc_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -165,19 +257,33 @@ func (x *PrepareImageResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PrepareImageResponse.ProtoReflect.Descriptor instead.
-func (*PrepareImageResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use Platform.ProtoReflect.Descriptor instead.
+func (*Platform) Descriptor() ([]byte, []int) {
 	return file_v1_rpc_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *PrepareImageResponse) GetImageDigest() string {
+func (x *Platform) GetOs() string {
 	if x != nil {
-		return x.ImageDigest
+		return x.Os
 	}
 	return ""
 }
 
-type ImageReadyRequest struct {
+func (x *Platform) GetArchitecture() string {
+	if x != nil {
+		return x.Architecture
+	}
+	return ""
+}
+
+func (x *Platform) GetVariant() string {
+	if x != nil {
+		return x.Variant
+	}
+	return ""
+}
+
+type PrepareImageResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
@@ -185,8 +291,8 @@ type ImageReadyRequest struct {
 	ImageDigest string `protobuf:"bytes,1,opt,name=image_digest,json=imageDigest,proto3" json:"image_digest,omitempty"`
 }
 
-func (x *ImageReadyRequest) Reset() {
-	*x = ImageReadyRequest{}
+func (x *PrepareImageResponse) Reset() {
+	*x = PrepareImageResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_v1_rpc_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -194,13 +300,13 @@ func (x *ImageReadyRequest) Reset() {
 	}
 }
 
-func (x *ImageReadyRequest) String() string {
+func (x *PrepareImageResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ImageReadyRequest) ProtoMessage() {}
+func (*PrepareImageResponse) ProtoMessage() {}
 
-func (x *ImageReadyRequest) ProtoReflect() protoreflect.Message {
+func (x *PrepareImageResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_v1_rpc_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -212,26 +318,33 @@ func (x *ImageReadyRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ImageReadyRequest.ProtoReflect.Descriptor instead.
-func (*ImageReadyRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use PrepareImageResponse.ProtoReflect.Descriptor instead.
+func (*PrepareImageResponse) Descriptor() ([]byte, []int) {
 	return file_v1_rpc_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *ImageReadyRequest) GetImageDigest() string {
+func (x *PrepareImageResponse) GetImageDigest() string {
 	if x != nil {
 		return x.ImageDigest
 	}
 	return ""
 }
 
-type ImageReadyResponse struct {
+type ImportImageRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	// path is an OCI image layout directory or a docker save archive, relative to the import directory of the server
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// tag selects the image when the layout or archive holds several, and names the imported image
+	Tag string `protobuf:"bytes,2,opt,name=tag,proto3" json:"tag,omitempty"`
+	// platform selects the image of a multi-arch image index, the server default is used when unset
+	Platform *Platform `protobuf:"bytes,3,opt,name=platform,proto3" json:"platform,omitempty"`
 }
 
-func (x *ImageReadyResponse) Reset() {
-	*x = ImageReadyResponse{}
+func (x *ImportImageRequest) Reset() {
+	*x = ImportImageRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_v1_rpc_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -239,13 +352,13 @@ func (x *ImageReadyResponse) Reset() {
 	}
 }
 
-func (x *ImageReadyResponse) String() string {
+func (x *ImportImageRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ImageReadyResponse) ProtoMessage() {}
+func (*ImportImageRequest) ProtoMessage() {}
 
-func (x *ImageReadyResponse) ProtoReflect() protoreflect.Message {
+func (x *ImportImageRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_v1_rpc_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -257,22 +370,42 @@ func (x *ImageReadyResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ImageReadyResponse.ProtoReflect.Descriptor instead.
-func (*ImageReadyResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ImportImageRequest.ProtoReflect.Descriptor instead.
+func (*ImportImageRequest) Descriptor() ([]byte, []int) {
 	return file_v1_rpc_proto_rawDescGZIP(), []int{4}
 }
 
-type GetAttrRequest struct {
+func (x *ImportImageRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ImportImageRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *ImportImageRequest) GetPlatform() *Platform {
+	if x != nil {
+		return x.Platform
+	}
+	return nil
+}
+
+type ImportImageResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Path        string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
-	ImageDigest string `protobuf:"bytes,2,opt,name=image_digest,json=imageDigest,proto3" json:"image_digest,omitempty"`
+	ImageDigest string `protobuf:"bytes,1,opt,name=image_digest,json=imageDigest,proto3" json:"image_digest,omitempty"`
 }
 
-func (x *GetAttrRequest) Reset() {
-	*x = GetAttrRequest{}
+func (x *ImportImageResponse) Reset() {
+	*x = ImportImageResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_v1_rpc_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -280,13 +413,13 @@ func (x *GetAttrRequest) Reset() {
 	}
 }
 
-func (x *GetAttrRequest) String() string {
+func (x *ImportImageResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetAttrRequest) ProtoMessage() {}
+func (*ImportImageResponse) ProtoMessage() {}
 
-func (x *GetAttrRequest) ProtoReflect() protoreflect.Message {
+func (x *ImportImageResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_v1_rpc_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -298,35 +431,28 @@ func (x *GetAttrRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetAttrRequest.ProtoReflect.Descriptor instead.
-func (*GetAttrRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ImportImageResponse.ProtoReflect.Descriptor instead.
+func (*ImportImageResponse) Descriptor() ([]byte, []int) {
 	return file_v1_rpc_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *GetAttrRequest) GetPath() string {
-	if x != nil {
-		return x.Path
-	}
-	return ""
-}
-
-func (x *GetAttrRequest) GetImageDigest() string {
+func (x *ImportImageResponse) GetImageDigest() string {
 	if x != nil {
 		return x.ImageDigest
 	}
 	return ""
 }
 
-type GetAttrResponse struct {
+type ImageReadyRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	File *File `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
+	ImageDigest string `protobuf:"bytes,1,opt,name=image_digest,json=imageDigest,proto3" json:"image_digest,omitempty"`
 }
 
-func (x *GetAttrResponse) Reset() {
-	*x = GetAttrResponse{}
+func (x *ImageReadyRequest) Reset() {
+	*x = ImageReadyRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_v1_rpc_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -334,13 +460,13 @@ func (x *GetAttrResponse) Reset() {
 	}
 }
 
-func (x *GetAttrResponse) String() string {
+func (x *ImageReadyRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetAttrResponse) ProtoMessage() {}
+func (*ImageReadyRequest) ProtoMessage() {}
 
-func (x *GetAttrResponse) ProtoReflect() protoreflect.Message {
+func (x *ImageReadyRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_v1_rpc_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -352,29 +478,26 @@ func (x *GetAttrResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetAttrResponse.ProtoReflect.Descriptor instead.
-func (*GetAttrResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ImageReadyRequest.ProtoReflect.Descriptor instead.
+func (*ImageReadyRequest) Descriptor() ([]byte, []int) {
 	return file_v1_rpc_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *GetAttrResponse) GetFile() *File {
+func (x *ImageReadyRequest) GetImageDigest() string {
 	if x != nil {
-		return x.File
+		return x.ImageDigest
 	}
-	return nil
+	return ""
 }
 
-type ReadDirRequest struct {
+type ImageReadyResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	Path        string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
-	ImageDigest string `protobuf:"bytes,2,opt,name=image_digest,json=imageDigest,proto3" json:"image_digest,omitempty"`
 }
 
-func (x *ReadDirRequest) Reset() {
-	*x = ReadDirRequest{}
+func (x *ImageReadyResponse) Reset() {
+	*x = ImageReadyResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_v1_rpc_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -382,13 +505,13 @@ func (x *ReadDirRequest) Reset() {
 	}
 }
 
-func (x *ReadDirRequest) String() string {
+func (x *ImageReadyResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReadDirRequest) ProtoMessage() {}
+func (*ImageReadyResponse) ProtoMessage() {}
 
-func (x *ReadDirRequest) ProtoReflect() protoreflect.Message {
+func (x *ImageReadyResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_v1_rpc_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -400,35 +523,21 @@ func (x *ReadDirRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReadDirRequest.ProtoReflect.Descriptor instead.
-func (*ReadDirRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ImageReadyResponse.ProtoReflect.Descriptor instead.
+func (*ImageReadyResponse) Descriptor() ([]byte, []int) {
 	return file_v1_rpc_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *ReadDirRequest) GetPath() string {
-	if x != nil {
-		return x.Path
-	}
-	return ""
-}
-
-func (x *ReadDirRequest) GetImageDigest() string {
-	if x != nil {
-		return x.ImageDigest
-	}
-	return ""
-}
-
-type ReadDirResponse struct {
+type WatchImageRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Entries []*File `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	ImageDigest string `protobuf:"bytes,1,opt,name=image_digest,json=imageDigest,proto3" json:"image_digest,omitempty"`
 }
 
-func (x *ReadDirResponse) Reset() {
-	*x = ReadDirResponse{}
+func (x *WatchImageRequest) Reset() {
+	*x = WatchImageRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_v1_rpc_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -436,13 +545,13 @@ func (x *ReadDirResponse) Reset() {
 	}
 }
 
-func (x *ReadDirResponse) String() string {
+func (x *WatchImageRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReadDirResponse) ProtoMessage() {}
+func (*WatchImageRequest) ProtoMessage() {}
 
-func (x *ReadDirResponse) ProtoReflect() protoreflect.Message {
+func (x *WatchImageRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_v1_rpc_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -454,30 +563,33 @@ func (x *ReadDirResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReadDirResponse.ProtoReflect.Descriptor instead.
-func (*ReadDirResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use WatchImageRequest.ProtoReflect.Descriptor instead.
+func (*WatchImageRequest) Descriptor() ([]byte, []int) {
 	return file_v1_rpc_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *ReadDirResponse) GetEntries() []*File {
+func (x *WatchImageRequest) GetImageDigest() string {
 	if x != nil {
-		return x.Entries
+		return x.ImageDigest
 	}
-	return nil
+	return ""
 }
 
-type OpenRequest struct {
+type WatchImageResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Path        string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
-	Flags       uint32 `protobuf:"varint,2,opt,name=flags,proto3" json:"flags,omitempty"`
-	ImageDigest string `protobuf:"bytes,3,opt,name=image_digest,json=imageDigest,proto3" json:"image_digest,omitempty"`
+	Kind            ImageEventKind `protobuf:"varint,1,opt,name=kind,proto3,enum=baepo.viscaufs.fs.v1.ImageEventKind" json:"kind,omitempty"`
+	LayerDigest     string         `protobuf:"bytes,2,opt,name=layer_digest,json=layerDigest,proto3" json:"layer_digest,omitempty"`
+	LayerPosition   uint32         `protobuf:"varint,3,opt,name=layer_position,json=layerPosition,proto3" json:"layer_position,omitempty"`
+	BytesDownloaded int64          `protobuf:"varint,4,opt,name=bytes_downloaded,json=bytesDownloaded,proto3" json:"bytes_downloaded,omitempty"`
+	BytesTotal      int64          `protobuf:"varint,5,opt,name=bytes_total,json=bytesTotal,proto3" json:"bytes_total,omitempty"`
+	Error           string         `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
 }
 
-func (x *OpenRequest) Reset() {
-	*x = OpenRequest{}
+func (x *WatchImageResponse) Reset() {
+	*x = WatchImageResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_v1_rpc_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -485,13 +597,13 @@ func (x *OpenRequest) Reset() {
 	}
 }
 
-func (x *OpenRequest) String() string {
+func (x *WatchImageResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OpenRequest) ProtoMessage() {}
+func (*WatchImageResponse) ProtoMessage() {}
 
-func (x *OpenRequest) ProtoReflect() protoreflect.Message {
+func (x *WatchImageResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_v1_rpc_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -503,42 +615,65 @@ func (x *OpenRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OpenRequest.ProtoReflect.Descriptor instead.
-func (*OpenRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use WatchImageResponse.ProtoReflect.Descriptor instead.
+func (*WatchImageResponse) Descriptor() ([]byte, []int) {
 	return file_v1_rpc_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *OpenRequest) GetPath() string {
+func (x *WatchImageResponse) GetKind() ImageEventKind {
 	if x != nil {
-		return x.Path
+		return x.Kind
+	}
+	return ImageEventKind_IMAGE_EVENT_KIND_UNSPECIFIED
+}
+
+func (x *WatchImageResponse) GetLayerDigest() string {
+	if x != nil {
+		return x.LayerDigest
 	}
 	return ""
 }
 
-func (x *OpenRequest) GetFlags() uint32 {
+func (x *WatchImageResponse) GetLayerPosition() uint32 {
 	if x != nil {
-		return x.Flags
+		return x.LayerPosition
 	}
 	return 0
 }
 
-func (x *OpenRequest) GetImageDigest() string {
+func (x *WatchImageResponse) GetBytesDownloaded() int64 {
 	if x != nil {
-		return x.ImageDigest
+		return x.BytesDownloaded
+	}
+	return 0
+}
+
+func (x *WatchImageResponse) GetBytesTotal() int64 {
+	if x != nil {
+		return x.BytesTotal
+	}
+	return 0
+}
+
+func (x *WatchImageResponse) GetError() string {
+	if x != nil {
+		return x.Error
 	}
 	return ""
 }
 
-type OpenResponse struct {
+type GetImageStatsRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Uid string `protobuf:"bytes,1,opt,name=uid,proto3" json:"uid,omitempty"`
+	ImageDigest string `protobuf:"bytes,1,opt,name=image_digest,json=imageDigest,proto3" json:"image_digest,omitempty"`
+	// limit is the number of files returned in each list, the server default is used when zero
+	Limit uint32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
 }
 
-func (x *OpenResponse) Reset() {
-	*x = OpenResponse{}
+func (x *GetImageStatsRequest) Reset() {
+	*x = GetImageStatsRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_v1_rpc_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -546,13 +681,13 @@ func (x *OpenResponse) Reset() {
 	}
 }
 
-func (x *OpenResponse) String() string {
+func (x *GetImageStatsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OpenResponse) ProtoMessage() {}
+func (*GetImageStatsRequest) ProtoMessage() {}
 
-func (x *OpenResponse) ProtoReflect() protoreflect.Message {
+func (x *GetImageStatsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_v1_rpc_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -564,30 +699,41 @@ func (x *OpenResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OpenResponse.ProtoReflect.Descriptor instead.
-func (*OpenResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetImageStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetImageStatsRequest) Descriptor() ([]byte, []int) {
 	return file_v1_rpc_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *OpenResponse) GetUid() string {
+func (x *GetImageStatsRequest) GetImageDigest() string {
 	if x != nil {
-		return x.Uid
+		return x.ImageDigest
 	}
 	return ""
 }
 
-type ReadRequest struct {
+func (x *GetImageStatsRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type FileStats struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Uid    string `protobuf:"bytes,1,opt,name=uid,proto3" json:"uid,omitempty"`
-	Offset int64  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
-	Size   uint32 `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// layer_digest is the layer the file is read from
+	LayerDigest string `protobuf:"bytes,2,opt,name=layer_digest,json=layerDigest,proto3" json:"layer_digest,omitempty"`
+	OpenCount   uint64 `protobuf:"varint,3,opt,name=open_count,json=openCount,proto3" json:"open_count,omitempty"`
+	BytesRead   uint64 `protobuf:"varint,4,opt,name=bytes_read,json=bytesRead,proto3" json:"bytes_read,omitempty"`
+	// first_accessed_at is the time of the first open or read, in unix nanoseconds
+	FirstAccessedAt int64 `protobuf:"varint,5,opt,name=first_accessed_at,json=firstAccessedAt,proto3" json:"first_accessed_at,omitempty"`
 }
 
-func (x *ReadRequest) Reset() {
-	*x = ReadRequest{}
+func (x *FileStats) Reset() {
+	*x = FileStats{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_v1_rpc_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -595,13 +741,13 @@ func (x *ReadRequest) Reset() {
 	}
 }
 
-func (x *ReadRequest) String() string {
+func (x *FileStats) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReadRequest) ProtoMessage() {}
+func (*FileStats) ProtoMessage() {}
 
-func (x *ReadRequest) ProtoReflect() protoreflect.Message {
+func (x *FileStats) ProtoReflect() protoreflect.Message {
 	mi := &file_v1_rpc_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -613,42 +759,61 @@ func (x *ReadRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReadRequest.ProtoReflect.Descriptor instead.
-func (*ReadRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use FileStats.ProtoReflect.Descriptor instead.
+func (*FileStats) Descriptor() ([]byte, []int) {
 	return file_v1_rpc_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *ReadRequest) GetUid() string {
+func (x *FileStats) GetPath() string {
 	if x != nil {
-		return x.Uid
+		return x.Path
 	}
 	return ""
 }
 
-func (x *ReadRequest) GetOffset() int64 {
+func (x *FileStats) GetLayerDigest() string {
 	if x != nil {
-		return x.Offset
+		return x.LayerDigest
 	}
-	return 0
+	return ""
 }
 
-func (x *ReadRequest) GetSize() uint32 {
+func (x *FileStats) GetOpenCount() uint64 {
 	if x != nil {
-		return x.Size
+		return x.OpenCount
 	}
 	return 0
 }
 
-type ReadResponse struct {
+func (x *FileStats) GetBytesRead() uint64 {
+	if x != nil {
+		return x.BytesRead
+	}
+	return 0
+}
+
+func (x *FileStats) GetFirstAccessedAt() int64 {
+	if x != nil {
+		return x.FirstAccessedAt
+	}
+	return 0
+}
+
+type GetImageStatsResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Data []byte `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	// hot_files are the files of the image that were read the most, most read first
+	HotFiles []*FileStats `protobuf:"bytes,1,rep,name=hot_files,json=hotFiles,proto3" json:"hot_files,omitempty"`
+	// access_order are the files of the image in the order they were first accessed
+	AccessOrder    []*FileStats `protobuf:"bytes,2,rep,name=access_order,json=accessOrder,proto3" json:"access_order,omitempty"`
+	TotalBytesRead uint64       `protobuf:"varint,3,opt,name=total_bytes_read,json=totalBytesRead,proto3" json:"total_bytes_read,omitempty"`
+	TotalOpenCount uint64       `protobuf:"varint,4,opt,name=total_open_count,json=totalOpenCount,proto3" json:"total_open_count,omitempty"`
 }
 
-func (x *ReadResponse) Reset() {
-	*x = ReadResponse{}
+func (x *GetImageStatsResponse) Reset() {
+	*x = GetImageStatsResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_v1_rpc_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -656,13 +821,13 @@ func (x *ReadResponse) Reset() {
 	}
 }
 
-func (x *ReadResponse) String() string {
+func (x *GetImageStatsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReadResponse) ProtoMessage() {}
+func (*GetImageStatsResponse) ProtoMessage() {}
 
-func (x *ReadResponse) ProtoReflect() protoreflect.Message {
+func (x *GetImageStatsResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_v1_rpc_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -674,28 +839,49 @@ func (x *ReadResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReadResponse.ProtoReflect.Descriptor instead.
-func (*ReadResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetImageStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetImageStatsResponse) Descriptor() ([]byte, []int) {
 	return file_v1_rpc_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *ReadResponse) GetData() []byte {
+func (x *GetImageStatsResponse) GetHotFiles() []*FileStats {
 	if x != nil {
-		return x.Data
+		return x.HotFiles
 	}
 	return nil
 }
 
-type ReleaseRequest struct {
+func (x *GetImageStatsResponse) GetAccessOrder() []*FileStats {
+	if x != nil {
+		return x.AccessOrder
+	}
+	return nil
+}
+
+func (x *GetImageStatsResponse) GetTotalBytesRead() uint64 {
+	if x != nil {
+		return x.TotalBytesRead
+	}
+	return 0
+}
+
+func (x *GetImageStatsResponse) GetTotalOpenCount() uint64 {
+	if x != nil {
+		return x.TotalOpenCount
+	}
+	return 0
+}
+
+type GetPrefetchProfileRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Uid string `protobuf:"bytes,1,opt,name=uid,proto3" json:"uid,omitempty"`
+	ImageDigest string `protobuf:"bytes,1,opt,name=image_digest,json=imageDigest,proto3" json:"image_digest,omitempty"`
 }
 
-func (x *ReleaseRequest) Reset() {
-	*x = ReleaseRequest{}
+func (x *GetPrefetchProfileRequest) Reset() {
+	*x = GetPrefetchProfileRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_v1_rpc_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -703,13 +889,13 @@ func (x *ReleaseRequest) Reset() {
 	}
 }
 
-func (x *ReleaseRequest) String() string {
+func (x *GetPrefetchProfileRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReleaseRequest) ProtoMessage() {}
+func (*GetPrefetchProfileRequest) ProtoMessage() {}
 
-func (x *ReleaseRequest) ProtoReflect() protoreflect.Message {
+func (x *GetPrefetchProfileRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_v1_rpc_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -721,26 +907,29 @@ func (x *ReleaseRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReleaseRequest.ProtoReflect.Descriptor instead.
-func (*ReleaseRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetPrefetchProfileRequest.ProtoReflect.Descriptor instead.
+func (*GetPrefetchProfileRequest) Descriptor() ([]byte, []int) {
 	return file_v1_rpc_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *ReleaseRequest) GetUid() string {
+func (x *GetPrefetchProfileRequest) GetImageDigest() string {
 	if x != nil {
-		return x.Uid
+		return x.ImageDigest
 	}
 	return ""
 }
 
-type ReleaseResponse struct {
+type PrefetchRange struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	Offset int64 `protobuf:"varint,1,opt,name=offset,proto3" json:"offset,omitempty"`
+	Length int64 `protobuf:"varint,2,opt,name=length,proto3" json:"length,omitempty"`
 }
 
-func (x *ReleaseResponse) Reset() {
-	*x = ReleaseResponse{}
+func (x *PrefetchRange) Reset() {
+	*x = PrefetchRange{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_v1_rpc_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -748,206 +937,2307 @@ func (x *ReleaseResponse) Reset() {
 	}
 }
 
-func (x *ReleaseResponse) String() string {
+func (x *PrefetchRange) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReleaseResponse) ProtoMessage() {}
+func (*PrefetchRange) ProtoMessage() {}
 
-func (x *ReleaseResponse) ProtoReflect() protoreflect.Message {
+func (x *PrefetchRange) ProtoReflect() protoreflect.Message {
 	mi := &file_v1_rpc_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
 		}
-		return ms
-	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use ReleaseResponse.ProtoReflect.Descriptor instead.
-func (*ReleaseResponse) Descriptor() ([]byte, []int) {
-	return file_v1_rpc_proto_rawDescGZIP(), []int{14}
-}
-
-var File_v1_rpc_proto protoreflect.FileDescriptor
-
-var file_v1_rpc_proto_rawDesc = []byte{
-	0x0a, 0x0c, 0x76, 0x31, 0x2f, 0x72, 0x70, 0x63, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x14,
-	0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66,
-	0x73, 0x2e, 0x76, 0x31, 0x1a, 0x0e, 0x76, 0x31, 0x2f, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x22, 0x9f, 0x01, 0x0a, 0x04, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x12, 0x0a,
-	0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74,
-	0x68, 0x12, 0x44, 0x0a, 0x0a, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69,
-	0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c,
-	0x65, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x52, 0x0a, 0x61, 0x74, 0x74,
-	0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x12, 0x2a, 0x0a, 0x0e, 0x73, 0x79, 0x6d, 0x6c, 0x69,
-	0x6e, 0x6b, 0x5f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48,
-	0x00, 0x52, 0x0d, 0x73, 0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74,
-	0x88, 0x01, 0x01, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x73, 0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b, 0x5f,
-	0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x22, 0x32, 0x0a, 0x13, 0x50, 0x72, 0x65, 0x70, 0x61, 0x72,
-	0x65, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a,
-	0x09, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x5f, 0x72, 0x65, 0x66, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x08, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x66, 0x22, 0x39, 0x0a, 0x14, 0x50, 0x72,
-	0x65, 0x70, 0x61, 0x72, 0x65, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x5f, 0x64, 0x69, 0x67, 0x65,
-	0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x44,
-	0x69, 0x67, 0x65, 0x73, 0x74, 0x22, 0x36, 0x0a, 0x11, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65,
-	0x61, 0x64, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x6d,
-	0x61, 0x67, 0x65, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x0b, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x22, 0x14, 0x0a,
-	0x12, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x61, 0x64, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x22, 0x47, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x41, 0x74, 0x74, 0x72, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x6d, 0x61,
-	0x67, 0x65, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x0b, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x22, 0x41, 0x0a, 0x0f,
-	0x47, 0x65, 0x74, 0x41, 0x74, 0x74, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x2e, 0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
-	0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66,
-	0x73, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x22,
-	0x47, 0x0a, 0x0e, 0x52, 0x65, 0x61, 0x64, 0x44, 0x69, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x5f, 0x64,
-	0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x69, 0x6d, 0x61,
-	0x67, 0x65, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x22, 0x47, 0x0a, 0x0f, 0x52, 0x65, 0x61, 0x64,
-	0x44, 0x69, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x34, 0x0a, 0x07, 0x65,
-	0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x62,
-	0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66, 0x73,
-	0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65,
-	0x73, 0x22, 0x5a, 0x0a, 0x0b, 0x4f, 0x70, 0x65, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
-	0x70, 0x61, 0x74, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x6c, 0x61, 0x67, 0x73, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x0d, 0x52, 0x05, 0x66, 0x6c, 0x61, 0x67, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x6d,
-	0x61, 0x67, 0x65, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x0b, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x22, 0x20, 0x0a,
-	0x0c, 0x4f, 0x70, 0x65, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x10, 0x0a,
-	0x03, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x69, 0x64, 0x22,
-	0x4b, 0x0a, 0x0b, 0x52, 0x65, 0x61, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10,
-	0x0a, 0x03, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x69, 0x64,
-	0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03,
-	0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x22, 0x22, 0x0a, 0x0c,
-	0x52, 0x65, 0x61, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04,
-	0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61,
-	0x22, 0x22, 0x0a, 0x0e, 0x52, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x03, 0x75, 0x69, 0x64, 0x22, 0x11, 0x0a, 0x0f, 0x52, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0x89, 0x05, 0x0a, 0x0b, 0x46, 0x75, 0x73, 0x65,
-	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x67, 0x0a, 0x0c, 0x50, 0x72, 0x65, 0x70, 0x61,
-	0x72, 0x65, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x29, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e,
-	0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x50,
-	0x72, 0x65, 0x70, 0x61, 0x72, 0x65, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x2a, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73, 0x63, 0x61,
-	0x75, 0x66, 0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x65, 0x70, 0x61, 0x72,
-	0x65, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
-	0x12, 0x61, 0x0a, 0x0a, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x61, 0x64, 0x79, 0x12, 0x27,
-	0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e,
-	0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x61, 0x64, 0x79,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e,
-	0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x49,
-	0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x61, 0x64, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x22, 0x00, 0x12, 0x58, 0x0a, 0x07, 0x47, 0x65, 0x74, 0x41, 0x74, 0x74, 0x72, 0x12, 0x24,
-	0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e,
-	0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x41, 0x74, 0x74, 0x72, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73,
-	0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x41,
-	0x74, 0x74, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x58, 0x0a,
-	0x07, 0x52, 0x65, 0x61, 0x64, 0x44, 0x69, 0x72, 0x12, 0x24, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f,
-	0x2e, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e,
-	0x52, 0x65, 0x61, 0x64, 0x44, 0x69, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x25,
-	0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e,
-	0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x44, 0x69, 0x72, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4f, 0x0a, 0x04, 0x4f, 0x70, 0x65, 0x6e, 0x12,
-	0x21, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73,
-	0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x70, 0x65, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x22, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73, 0x63, 0x61,
-	0x75, 0x66, 0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x70, 0x65, 0x6e, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4f, 0x0a, 0x04, 0x52, 0x65, 0x61, 0x64,
-	0x12, 0x21, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66,
-	0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73, 0x63,
-	0x61, 0x75, 0x66, 0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x58, 0x0a, 0x07, 0x52, 0x65, 0x6c,
-	0x65, 0x61, 0x73, 0x65, 0x12, 0x24, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73,
-	0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x6c, 0x65,
-	0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e, 0x62, 0x61, 0x65,
-	0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76,
-	0x31, 0x2e, 0x52, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x22, 0x00, 0x42, 0x35, 0x5a, 0x33, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
-	0x6d, 0x2f, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2d, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2f, 0x76, 0x69,
-	0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2f, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2f, 0x66, 0x73,
-	0x70, 0x62, 0x2f, 0x76, 0x31, 0x3b, 0x66, 0x73, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x33,
-}
-
-var (
-	file_v1_rpc_proto_rawDescOnce sync.Once
-	file_v1_rpc_proto_rawDescData = file_v1_rpc_proto_rawDesc
-)
-
-func file_v1_rpc_proto_rawDescGZIP() []byte {
-	file_v1_rpc_proto_rawDescOnce.Do(func() {
-		file_v1_rpc_proto_rawDescData = protoimpl.X.CompressGZIP(file_v1_rpc_proto_rawDescData)
-	})
-	return file_v1_rpc_proto_rawDescData
-}
-
-var file_v1_rpc_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
-var file_v1_rpc_proto_goTypes = []interface{}{
-	(*File)(nil),                 // 0: baepo.viscaufs.fs.v1.File
-	(*PrepareImageRequest)(nil),  // 1: baepo.viscaufs.fs.v1.PrepareImageRequest
-	(*PrepareImageResponse)(nil), // 2: baepo.viscaufs.fs.v1.PrepareImageResponse
-	(*ImageReadyRequest)(nil),    // 3: baepo.viscaufs.fs.v1.ImageReadyRequest
-	(*ImageReadyResponse)(nil),   // 4: baepo.viscaufs.fs.v1.ImageReadyResponse
-	(*GetAttrRequest)(nil),       // 5: baepo.viscaufs.fs.v1.GetAttrRequest
-	(*GetAttrResponse)(nil),      // 6: baepo.viscaufs.fs.v1.GetAttrResponse
-	(*ReadDirRequest)(nil),       // 7: baepo.viscaufs.fs.v1.ReadDirRequest
-	(*ReadDirResponse)(nil),      // 8: baepo.viscaufs.fs.v1.ReadDirResponse
-	(*OpenRequest)(nil),          // 9: baepo.viscaufs.fs.v1.OpenRequest
-	(*OpenResponse)(nil),         // 10: baepo.viscaufs.fs.v1.OpenResponse
-	(*ReadRequest)(nil),          // 11: baepo.viscaufs.fs.v1.ReadRequest
-	(*ReadResponse)(nil),         // 12: baepo.viscaufs.fs.v1.ReadResponse
-	(*ReleaseRequest)(nil),       // 13: baepo.viscaufs.fs.v1.ReleaseRequest
-	(*ReleaseResponse)(nil),      // 14: baepo.viscaufs.fs.v1.ReleaseResponse
-	(*FileAttributes)(nil),       // 15: baepo.viscaufs.fs.v1.FileAttributes
-}
-var file_v1_rpc_proto_depIdxs = []int32{
-	15, // 0: baepo.viscaufs.fs.v1.File.attributes:type_name -> baepo.viscaufs.fs.v1.FileAttributes
-	0,  // 1: baepo.viscaufs.fs.v1.GetAttrResponse.file:type_name -> baepo.viscaufs.fs.v1.File
-	0,  // 2: baepo.viscaufs.fs.v1.ReadDirResponse.entries:type_name -> baepo.viscaufs.fs.v1.File
-	1,  // 3: baepo.viscaufs.fs.v1.FuseService.PrepareImage:input_type -> baepo.viscaufs.fs.v1.PrepareImageRequest
-	3,  // 4: baepo.viscaufs.fs.v1.FuseService.ImageReady:input_type -> baepo.viscaufs.fs.v1.ImageReadyRequest
-	5,  // 5: baepo.viscaufs.fs.v1.FuseService.GetAttr:input_type -> baepo.viscaufs.fs.v1.GetAttrRequest
-	7,  // 6: baepo.viscaufs.fs.v1.FuseService.ReadDir:input_type -> baepo.viscaufs.fs.v1.ReadDirRequest
-	9,  // 7: baepo.viscaufs.fs.v1.FuseService.Open:input_type -> baepo.viscaufs.fs.v1.OpenRequest
-	11, // 8: baepo.viscaufs.fs.v1.FuseService.Read:input_type -> baepo.viscaufs.fs.v1.ReadRequest
-	13, // 9: baepo.viscaufs.fs.v1.FuseService.Release:input_type -> baepo.viscaufs.fs.v1.ReleaseRequest
-	2,  // 10: baepo.viscaufs.fs.v1.FuseService.PrepareImage:output_type -> baepo.viscaufs.fs.v1.PrepareImageResponse
-	4,  // 11: baepo.viscaufs.fs.v1.FuseService.ImageReady:output_type -> baepo.viscaufs.fs.v1.ImageReadyResponse
-	6,  // 12: baepo.viscaufs.fs.v1.FuseService.GetAttr:output_type -> baepo.viscaufs.fs.v1.GetAttrResponse
-	8,  // 13: baepo.viscaufs.fs.v1.FuseService.ReadDir:output_type -> baepo.viscaufs.fs.v1.ReadDirResponse
-	10, // 14: baepo.viscaufs.fs.v1.FuseService.Open:output_type -> baepo.viscaufs.fs.v1.OpenResponse
-	12, // 15: baepo.viscaufs.fs.v1.FuseService.Read:output_type -> baepo.viscaufs.fs.v1.ReadResponse
-	14, // 16: baepo.viscaufs.fs.v1.FuseService.Release:output_type -> baepo.viscaufs.fs.v1.ReleaseResponse
-	10, // [10:17] is the sub-list for method output_type
-	3,  // [3:10] is the sub-list for method input_type
-	3,  // [3:3] is the sub-list for extension type_name
-	3,  // [3:3] is the sub-list for extension extendee
-	0,  // [0:3] is the sub-list for field type_name
-}
-
-func init() { file_v1_rpc_proto_init() }
-func file_v1_rpc_proto_init() {
-	if File_v1_rpc_proto != nil {
-		return
-	}
-	file_v1_types_proto_init()
-	if !protoimpl.UnsafeEnabled {
-		file_v1_rpc_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*File); i {
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PrefetchRange.ProtoReflect.Descriptor instead.
+func (*PrefetchRange) Descriptor() ([]byte, []int) {
+	return file_v1_rpc_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *PrefetchRange) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *PrefetchRange) GetLength() int64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+type PrefetchFile struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// ranges are the parts of the file read during the startup, none when the file was only opened
+	Ranges []*PrefetchRange `protobuf:"bytes,2,rep,name=ranges,proto3" json:"ranges,omitempty"`
+}
+
+func (x *PrefetchFile) Reset() {
+	*x = PrefetchFile{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_rpc_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PrefetchFile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PrefetchFile) ProtoMessage() {}
+
+func (x *PrefetchFile) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_rpc_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PrefetchFile.ProtoReflect.Descriptor instead.
+func (*PrefetchFile) Descriptor() ([]byte, []int) {
+	return file_v1_rpc_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *PrefetchFile) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *PrefetchFile) GetRanges() []*PrefetchRange {
+	if x != nil {
+		return x.Ranges
+	}
+	return nil
+}
+
+type GetPrefetchProfileResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// files are in the order the previous runs accessed them first
+	Files []*PrefetchFile `protobuf:"bytes,1,rep,name=files,proto3" json:"files,omitempty"`
+	// block_size is the size the ranges are aligned to
+	BlockSize int64 `protobuf:"varint,2,opt,name=block_size,json=blockSize,proto3" json:"block_size,omitempty"`
+}
+
+func (x *GetPrefetchProfileResponse) Reset() {
+	*x = GetPrefetchProfileResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_rpc_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetPrefetchProfileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPrefetchProfileResponse) ProtoMessage() {}
+
+func (x *GetPrefetchProfileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_rpc_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPrefetchProfileResponse.ProtoReflect.Descriptor instead.
+func (*GetPrefetchProfileResponse) Descriptor() ([]byte, []int) {
+	return file_v1_rpc_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetPrefetchProfileResponse) GetFiles() []*PrefetchFile {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *GetPrefetchProfileResponse) GetBlockSize() int64 {
+	if x != nil {
+		return x.BlockSize
+	}
+	return 0
+}
+
+type SessionRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// session_id resumes a session in the first message of the stream, a new session is opened when it is empty or
+	// expired
+	SessionId string `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	// image_digest is the image mounted by the client, a new session records the startup of the image for its prefetch
+	// profile
+	ImageDigest string `protobuf:"bytes,2,opt,name=image_digest,json=imageDigest,proto3" json:"image_digest,omitempty"`
+}
+
+func (x *SessionRequest) Reset() {
+	*x = SessionRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_rpc_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionRequest) ProtoMessage() {}
+
+func (x *SessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_rpc_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionRequest.ProtoReflect.Descriptor instead.
+func (*SessionRequest) Descriptor() ([]byte, []int) {
+	return file_v1_rpc_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *SessionRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *SessionRequest) GetImageDigest() string {
+	if x != nil {
+		return x.ImageDigest
+	}
+	return ""
+}
+
+type SessionResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SessionId string `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	// heartbeat_interval_ms is the delay between two heartbeats of the client, the session expires after missing a
+	// few of them
+	HeartbeatIntervalMs int64 `protobuf:"varint,2,opt,name=heartbeat_interval_ms,json=heartbeatIntervalMs,proto3" json:"heartbeat_interval_ms,omitempty"`
+}
+
+func (x *SessionResponse) Reset() {
+	*x = SessionResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_rpc_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionResponse) ProtoMessage() {}
+
+func (x *SessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_rpc_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionResponse.ProtoReflect.Descriptor instead.
+func (*SessionResponse) Descriptor() ([]byte, []int) {
+	return file_v1_rpc_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *SessionResponse) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *SessionResponse) GetHeartbeatIntervalMs() int64 {
+	if x != nil {
+		return x.HeartbeatIntervalMs
+	}
+	return 0
+}
+
+type ListSessionsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// session_id only lists the handles of the session when set
+	SessionId string `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+}
+
+func (x *ListSessionsRequest) Reset() {
+	*x = ListSessionsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_rpc_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListSessionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionsRequest) ProtoMessage() {}
+
+func (x *ListSessionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_rpc_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionsRequest.ProtoReflect.Descriptor instead.
+func (*ListSessionsRequest) Descriptor() ([]byte, []int) {
+	return file_v1_rpc_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ListSessionsRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+type OpenHandle struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Uid         string `protobuf:"bytes,1,opt,name=uid,proto3" json:"uid,omitempty"`
+	ImageDigest string `protobuf:"bytes,2,opt,name=image_digest,json=imageDigest,proto3" json:"image_digest,omitempty"`
+	Path        string `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`
+	// opened_at is the time the handle was opened, in unix nanoseconds
+	OpenedAt int64 `protobuf:"varint,4,opt,name=opened_at,json=openedAt,proto3" json:"opened_at,omitempty"`
+}
+
+func (x *OpenHandle) Reset() {
+	*x = OpenHandle{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_rpc_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OpenHandle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OpenHandle) ProtoMessage() {}
+
+func (x *OpenHandle) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_rpc_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OpenHandle.ProtoReflect.Descriptor instead.
+func (*OpenHandle) Descriptor() ([]byte, []int) {
+	return file_v1_rpc_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *OpenHandle) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+func (x *OpenHandle) GetImageDigest() string {
+	if x != nil {
+		return x.ImageDigest
+	}
+	return ""
+}
+
+func (x *OpenHandle) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *OpenHandle) GetOpenedAt() int64 {
+	if x != nil {
+		return x.OpenedAt
+	}
+	return 0
+}
+
+type SessionInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// session_id is empty for the handles opened without a session
+	SessionId string `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	// last_heartbeat_at is the time of the last heartbeat of the session, in unix nanoseconds
+	LastHeartbeatAt int64         `protobuf:"varint,2,opt,name=last_heartbeat_at,json=lastHeartbeatAt,proto3" json:"last_heartbeat_at,omitempty"`
+	Handles         []*OpenHandle `protobuf:"bytes,3,rep,name=handles,proto3" json:"handles,omitempty"`
+}
+
+func (x *SessionInfo) Reset() {
+	*x = SessionInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_rpc_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SessionInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionInfo) ProtoMessage() {}
+
+func (x *SessionInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_rpc_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionInfo.ProtoReflect.Descriptor instead.
+func (*SessionInfo) Descriptor() ([]byte, []int) {
+	return file_v1_rpc_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *SessionInfo) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *SessionInfo) GetLastHeartbeatAt() int64 {
+	if x != nil {
+		return x.LastHeartbeatAt
+	}
+	return 0
+}
+
+func (x *SessionInfo) GetHandles() []*OpenHandle {
+	if x != nil {
+		return x.Handles
+	}
+	return nil
+}
+
+type ListSessionsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Sessions []*SessionInfo `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"`
+}
+
+func (x *ListSessionsResponse) Reset() {
+	*x = ListSessionsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_rpc_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListSessionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionsResponse) ProtoMessage() {}
+
+func (x *ListSessionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_rpc_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionsResponse.ProtoReflect.Descriptor instead.
+func (*ListSessionsResponse) Descriptor() ([]byte, []int) {
+	return file_v1_rpc_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ListSessionsResponse) GetSessions() []*SessionInfo {
+	if x != nil {
+		return x.Sessions
+	}
+	return nil
+}
+
+type GetAttrRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Path        string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	ImageDigest string `protobuf:"bytes,2,opt,name=image_digest,json=imageDigest,proto3" json:"image_digest,omitempty"`
+}
+
+func (x *GetAttrRequest) Reset() {
+	*x = GetAttrRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_rpc_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetAttrRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAttrRequest) ProtoMessage() {}
+
+func (x *GetAttrRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_rpc_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAttrRequest.ProtoReflect.Descriptor instead.
+func (*GetAttrRequest) Descriptor() ([]byte, []int) {
+	return file_v1_rpc_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GetAttrRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *GetAttrRequest) GetImageDigest() string {
+	if x != nil {
+		return x.ImageDigest
+	}
+	return ""
+}
+
+type GetAttrResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	File *File `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
+}
+
+func (x *GetAttrResponse) Reset() {
+	*x = GetAttrResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_rpc_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetAttrResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAttrResponse) ProtoMessage() {}
+
+func (x *GetAttrResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_rpc_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAttrResponse.ProtoReflect.Descriptor instead.
+func (*GetAttrResponse) Descriptor() ([]byte, []int) {
+	return file_v1_rpc_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetAttrResponse) GetFile() *File {
+	if x != nil {
+		return x.File
+	}
+	return nil
+}
+
+type ReadDirRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Path        string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	ImageDigest string `protobuf:"bytes,2,opt,name=image_digest,json=imageDigest,proto3" json:"image_digest,omitempty"`
+}
+
+func (x *ReadDirRequest) Reset() {
+	*x = ReadDirRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_rpc_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReadDirRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadDirRequest) ProtoMessage() {}
+
+func (x *ReadDirRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_rpc_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadDirRequest.ProtoReflect.Descriptor instead.
+func (*ReadDirRequest) Descriptor() ([]byte, []int) {
+	return file_v1_rpc_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ReadDirRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ReadDirRequest) GetImageDigest() string {
+	if x != nil {
+		return x.ImageDigest
+	}
+	return ""
+}
+
+type ReadDirResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Entries []*File `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+}
+
+func (x *ReadDirResponse) Reset() {
+	*x = ReadDirResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_rpc_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReadDirResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadDirResponse) ProtoMessage() {}
+
+func (x *ReadDirResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_rpc_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadDirResponse.ProtoReflect.Descriptor instead.
+func (*ReadDirResponse) Descriptor() ([]byte, []int) {
+	return file_v1_rpc_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ReadDirResponse) GetEntries() []*File {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type GetXattrRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Path        string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	ImageDigest string `protobuf:"bytes,2,opt,name=image_digest,json=imageDigest,proto3" json:"image_digest,omitempty"`
+	Name        string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *GetXattrRequest) Reset() {
+	*x = GetXattrRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_rpc_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetXattrRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetXattrRequest) ProtoMessage() {}
+
+func (x *GetXattrRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_rpc_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetXattrRequest.ProtoReflect.Descriptor instead.
+func (*GetXattrRequest) Descriptor() ([]byte, []int) {
+	return file_v1_rpc_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *GetXattrRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *GetXattrRequest) GetImageDigest() string {
+	if x != nil {
+		return x.ImageDigest
+	}
+	return ""
+}
+
+func (x *GetXattrRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type GetXattrResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// value is not set when the file has no attribute of the name
+	Value []byte `protobuf:"bytes,1,opt,name=value,proto3,oneof" json:"value,omitempty"`
+}
+
+func (x *GetXattrResponse) Reset() {
+	*x = GetXattrResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_rpc_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetXattrResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetXattrResponse) ProtoMessage() {}
+
+func (x *GetXattrResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_rpc_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetXattrResponse.ProtoReflect.Descriptor instead.
+func (*GetXattrResponse) Descriptor() ([]byte, []int) {
+	return file_v1_rpc_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *GetXattrResponse) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+type ListXattrRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Path        string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	ImageDigest string `protobuf:"bytes,2,opt,name=image_digest,json=imageDigest,proto3" json:"image_digest,omitempty"`
+}
+
+func (x *ListXattrRequest) Reset() {
+	*x = ListXattrRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_rpc_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListXattrRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListXattrRequest) ProtoMessage() {}
+
+func (x *ListXattrRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_rpc_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListXattrRequest.ProtoReflect.Descriptor instead.
+func (*ListXattrRequest) Descriptor() ([]byte, []int) {
+	return file_v1_rpc_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ListXattrRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ListXattrRequest) GetImageDigest() string {
+	if x != nil {
+		return x.ImageDigest
+	}
+	return ""
+}
+
+type ListXattrResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Names []string `protobuf:"bytes,1,rep,name=names,proto3" json:"names,omitempty"`
+}
+
+func (x *ListXattrResponse) Reset() {
+	*x = ListXattrResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_rpc_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListXattrResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListXattrResponse) ProtoMessage() {}
+
+func (x *ListXattrResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_rpc_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListXattrResponse.ProtoReflect.Descriptor instead.
+func (*ListXattrResponse) Descriptor() ([]byte, []int) {
+	return file_v1_rpc_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ListXattrResponse) GetNames() []string {
+	if x != nil {
+		return x.Names
+	}
+	return nil
+}
+
+type OpenRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Path        string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Flags       uint32 `protobuf:"varint,2,opt,name=flags,proto3" json:"flags,omitempty"`
+	ImageDigest string `protobuf:"bytes,3,opt,name=image_digest,json=imageDigest,proto3" json:"image_digest,omitempty"`
+	// prefetch opens the file to warm the client caches, its accesses are not recorded in the statistics
+	Prefetch bool `protobuf:"varint,4,opt,name=prefetch,proto3" json:"prefetch,omitempty"`
+	// session_id ties the handle to the session of the client, it is released when the session expires
+	SessionId string `protobuf:"bytes,5,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+}
+
+func (x *OpenRequest) Reset() {
+	*x = OpenRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_rpc_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OpenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OpenRequest) ProtoMessage() {}
+
+func (x *OpenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_rpc_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OpenRequest.ProtoReflect.Descriptor instead.
+func (*OpenRequest) Descriptor() ([]byte, []int) {
+	return file_v1_rpc_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *OpenRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *OpenRequest) GetFlags() uint32 {
+	if x != nil {
+		return x.Flags
+	}
+	return 0
+}
+
+func (x *OpenRequest) GetImageDigest() string {
+	if x != nil {
+		return x.ImageDigest
+	}
+	return ""
+}
+
+func (x *OpenRequest) GetPrefetch() bool {
+	if x != nil {
+		return x.Prefetch
+	}
+	return false
+}
+
+func (x *OpenRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+type OpenResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Uid string `protobuf:"bytes,1,opt,name=uid,proto3" json:"uid,omitempty"`
+}
+
+func (x *OpenResponse) Reset() {
+	*x = OpenResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_rpc_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OpenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OpenResponse) ProtoMessage() {}
+
+func (x *OpenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_rpc_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OpenResponse.ProtoReflect.Descriptor instead.
+func (*OpenResponse) Descriptor() ([]byte, []int) {
+	return file_v1_rpc_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *OpenResponse) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+type ReadRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Uid    string `protobuf:"bytes,1,opt,name=uid,proto3" json:"uid,omitempty"`
+	Offset int64  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Size   uint32 `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+}
+
+func (x *ReadRequest) Reset() {
+	*x = ReadRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_rpc_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadRequest) ProtoMessage() {}
+
+func (x *ReadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_rpc_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadRequest.ProtoReflect.Descriptor instead.
+func (*ReadRequest) Descriptor() ([]byte, []int) {
+	return file_v1_rpc_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ReadRequest) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+func (x *ReadRequest) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ReadRequest) GetSize() uint32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+type ReadResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Data []byte `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (x *ReadResponse) Reset() {
+	*x = ReadResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_rpc_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReadResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadResponse) ProtoMessage() {}
+
+func (x *ReadResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_rpc_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadResponse.ProtoReflect.Descriptor instead.
+func (*ReadResponse) Descriptor() ([]byte, []int) {
+	return file_v1_rpc_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ReadResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type ReadAtRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ImageDigest string `protobuf:"bytes,1,opt,name=image_digest,json=imageDigest,proto3" json:"image_digest,omitempty"`
+	Path        string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Offset      int64  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	Size        uint32 `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
+	// prefetch reads the file to warm the client caches, the read is not recorded in the statistics
+	Prefetch bool `protobuf:"varint,5,opt,name=prefetch,proto3" json:"prefetch,omitempty"`
+}
+
+func (x *ReadAtRequest) Reset() {
+	*x = ReadAtRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_rpc_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReadAtRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadAtRequest) ProtoMessage() {}
+
+func (x *ReadAtRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_rpc_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadAtRequest.ProtoReflect.Descriptor instead.
+func (*ReadAtRequest) Descriptor() ([]byte, []int) {
+	return file_v1_rpc_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ReadAtRequest) GetImageDigest() string {
+	if x != nil {
+		return x.ImageDigest
+	}
+	return ""
+}
+
+func (x *ReadAtRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ReadAtRequest) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ReadAtRequest) GetSize() uint32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *ReadAtRequest) GetPrefetch() bool {
+	if x != nil {
+		return x.Prefetch
+	}
+	return false
+}
+
+type ReadAtResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Data []byte `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (x *ReadAtResponse) Reset() {
+	*x = ReadAtResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_rpc_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReadAtResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadAtResponse) ProtoMessage() {}
+
+func (x *ReadAtResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_rpc_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadAtResponse.ProtoReflect.Descriptor instead.
+func (*ReadAtResponse) Descriptor() ([]byte, []int) {
+	return file_v1_rpc_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ReadAtResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type ReadStreamRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// uid reads through an open handle, image_digest and path read the file without one
+	Uid         string `protobuf:"bytes,1,opt,name=uid,proto3" json:"uid,omitempty"`
+	ImageDigest string `protobuf:"bytes,2,opt,name=image_digest,json=imageDigest,proto3" json:"image_digest,omitempty"`
+	Path        string `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`
+	Offset      int64  `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
+	// length is the number of bytes to stream, the stream ends early at the end of the file
+	Length int64 `protobuf:"varint,5,opt,name=length,proto3" json:"length,omitempty"`
+	// prefetch reads the file to warm the client caches, the reads are not recorded in the statistics
+	Prefetch bool `protobuf:"varint,6,opt,name=prefetch,proto3" json:"prefetch,omitempty"`
+}
+
+func (x *ReadStreamRequest) Reset() {
+	*x = ReadStreamRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_rpc_proto_msgTypes[37]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReadStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadStreamRequest) ProtoMessage() {}
+
+func (x *ReadStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_rpc_proto_msgTypes[37]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadStreamRequest.ProtoReflect.Descriptor instead.
+func (*ReadStreamRequest) Descriptor() ([]byte, []int) {
+	return file_v1_rpc_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *ReadStreamRequest) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+func (x *ReadStreamRequest) GetImageDigest() string {
+	if x != nil {
+		return x.ImageDigest
+	}
+	return ""
+}
+
+func (x *ReadStreamRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ReadStreamRequest) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ReadStreamRequest) GetLength() int64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *ReadStreamRequest) GetPrefetch() bool {
+	if x != nil {
+		return x.Prefetch
+	}
+	return false
+}
+
+type ReadStreamResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// offset is the position of data in the file, the chunks are consecutive
+	Offset int64  `protobuf:"varint,1,opt,name=offset,proto3" json:"offset,omitempty"`
+	Data   []byte `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (x *ReadStreamResponse) Reset() {
+	*x = ReadStreamResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_rpc_proto_msgTypes[38]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReadStreamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadStreamResponse) ProtoMessage() {}
+
+func (x *ReadStreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_rpc_proto_msgTypes[38]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadStreamResponse.ProtoReflect.Descriptor instead.
+func (*ReadStreamResponse) Descriptor() ([]byte, []int) {
+	return file_v1_rpc_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *ReadStreamResponse) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ReadStreamResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type RecordReadRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ImageDigest string `protobuf:"bytes,1,opt,name=image_digest,json=imageDigest,proto3" json:"image_digest,omitempty"`
+	Path        string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Offset      int64  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	Length      int64  `protobuf:"varint,4,opt,name=length,proto3" json:"length,omitempty"`
+}
+
+func (x *RecordReadRequest) Reset() {
+	*x = RecordReadRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_rpc_proto_msgTypes[39]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RecordReadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordReadRequest) ProtoMessage() {}
+
+func (x *RecordReadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_rpc_proto_msgTypes[39]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordReadRequest.ProtoReflect.Descriptor instead.
+func (*RecordReadRequest) Descriptor() ([]byte, []int) {
+	return file_v1_rpc_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *RecordReadRequest) GetImageDigest() string {
+	if x != nil {
+		return x.ImageDigest
+	}
+	return ""
+}
+
+func (x *RecordReadRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *RecordReadRequest) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *RecordReadRequest) GetLength() int64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+type RecordReadResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *RecordReadResponse) Reset() {
+	*x = RecordReadResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_rpc_proto_msgTypes[40]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RecordReadResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordReadResponse) ProtoMessage() {}
+
+func (x *RecordReadResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_rpc_proto_msgTypes[40]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordReadResponse.ProtoReflect.Descriptor instead.
+func (*RecordReadResponse) Descriptor() ([]byte, []int) {
+	return file_v1_rpc_proto_rawDescGZIP(), []int{40}
+}
+
+type ReleaseRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Uid string `protobuf:"bytes,1,opt,name=uid,proto3" json:"uid,omitempty"`
+}
+
+func (x *ReleaseRequest) Reset() {
+	*x = ReleaseRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_rpc_proto_msgTypes[41]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReleaseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseRequest) ProtoMessage() {}
+
+func (x *ReleaseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_rpc_proto_msgTypes[41]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseRequest.ProtoReflect.Descriptor instead.
+func (*ReleaseRequest) Descriptor() ([]byte, []int) {
+	return file_v1_rpc_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *ReleaseRequest) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+type ReleaseResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ReleaseResponse) Reset() {
+	*x = ReleaseResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v1_rpc_proto_msgTypes[42]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReleaseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseResponse) ProtoMessage() {}
+
+func (x *ReleaseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_rpc_proto_msgTypes[42]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseResponse.ProtoReflect.Descriptor instead.
+func (*ReleaseResponse) Descriptor() ([]byte, []int) {
+	return file_v1_rpc_proto_rawDescGZIP(), []int{42}
+}
+
+var File_v1_rpc_proto protoreflect.FileDescriptor
+
+var file_v1_rpc_proto_rawDesc = []byte{
+	0x0a, 0x0c, 0x76, 0x31, 0x2f, 0x72, 0x70, 0x63, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x14,
+	0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66,
+	0x73, 0x2e, 0x76, 0x31, 0x1a, 0x0e, 0x76, 0x31, 0x2f, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x22, 0x9f, 0x01, 0x0a, 0x04, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x12, 0x0a,
+	0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74,
+	0x68, 0x12, 0x44, 0x0a, 0x0a, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69,
+	0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c,
+	0x65, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x52, 0x0a, 0x61, 0x74, 0x74,
+	0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x12, 0x2a, 0x0a, 0x0e, 0x73, 0x79, 0x6d, 0x6c, 0x69,
+	0x6e, 0x6b, 0x5f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48,
+	0x00, 0x52, 0x0d, 0x73, 0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74,
+	0x88, 0x01, 0x01, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x73, 0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b, 0x5f,
+	0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x22, 0x95, 0x01, 0x0a, 0x13, 0x50, 0x72, 0x65, 0x70, 0x61,
+	0x72, 0x65, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b,
+	0x0a, 0x09, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x5f, 0x72, 0x65, 0x66, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x66, 0x12, 0x25, 0x0a, 0x0e, 0x72,
+	0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0d, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x54, 0x6f, 0x6b,
+	0x65, 0x6e, 0x12, 0x3a, 0x0a, 0x08, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73,
+	0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6c, 0x61, 0x74,
+	0x66, 0x6f, 0x72, 0x6d, 0x52, 0x08, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x22, 0x58,
+	0x0a, 0x08, 0x50, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x6f, 0x73,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x6f, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x61, 0x72,
+	0x63, 0x68, 0x69, 0x74, 0x65, 0x63, 0x74, 0x75, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0c, 0x61, 0x72, 0x63, 0x68, 0x69, 0x74, 0x65, 0x63, 0x74, 0x75, 0x72, 0x65, 0x12, 0x18,
+	0x0a, 0x07, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x22, 0x39, 0x0a, 0x14, 0x50, 0x72, 0x65, 0x70,
+	0x61, 0x72, 0x65, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x21, 0x0a, 0x0c, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x44, 0x69, 0x67,
+	0x65, 0x73, 0x74, 0x22, 0x76, 0x0a, 0x12, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x49, 0x6d, 0x61,
+	0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74,
+	0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x10, 0x0a,
+	0x03, 0x74, 0x61, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x74, 0x61, 0x67, 0x12,
+	0x3a, 0x0a, 0x08, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1e, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75,
+	0x66, 0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72,
+	0x6d, 0x52, 0x08, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x22, 0x38, 0x0a, 0x13, 0x49,
+	0x6d, 0x70, 0x6f, 0x72, 0x74, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x5f, 0x64, 0x69, 0x67, 0x65,
+	0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x44,
+	0x69, 0x67, 0x65, 0x73, 0x74, 0x22, 0x36, 0x0a, 0x11, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65,
+	0x61, 0x64, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x6d,
+	0x61, 0x67, 0x65, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0b, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x22, 0x14, 0x0a,
+	0x12, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x61, 0x64, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x36, 0x0a, 0x11, 0x57, 0x61, 0x74, 0x63, 0x68, 0x49, 0x6d, 0x61, 0x67,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x6d, 0x61, 0x67,
+	0x65, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
+	0x69, 0x6d, 0x61, 0x67, 0x65, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x22, 0xfa, 0x01, 0x0a, 0x12,
+	0x57, 0x61, 0x74, 0x63, 0x68, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x38, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x24, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66,
+	0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x4b, 0x69, 0x6e, 0x64, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x12, 0x21, 0x0a, 0x0c,
+	0x6c, 0x61, 0x79, 0x65, 0x72, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0b, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12,
+	0x25, 0x0a, 0x0e, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x5f, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x50, 0x6f,
+	0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x29, 0x0a, 0x10, 0x62, 0x79, 0x74, 0x65, 0x73, 0x5f,
+	0x64, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x0f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x65,
+	0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x62, 0x79, 0x74, 0x65, 0x73, 0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x62, 0x79, 0x74, 0x65, 0x73, 0x54, 0x6f, 0x74,
+	0x61, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x4f, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x49,
+	0x6d, 0x61, 0x67, 0x65, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x21, 0x0a, 0x0c, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x44, 0x69, 0x67,
+	0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0xac, 0x01, 0x0a, 0x09, 0x46, 0x69,
+	0x6c, 0x65, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x21, 0x0a, 0x0c, 0x6c,
+	0x61, 0x79, 0x65, 0x72, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0b, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12, 0x1d,
+	0x0a, 0x0a, 0x6f, 0x70, 0x65, 0x6e, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x09, 0x6f, 0x70, 0x65, 0x6e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1d, 0x0a,
+	0x0a, 0x62, 0x79, 0x74, 0x65, 0x73, 0x5f, 0x72, 0x65, 0x61, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x09, 0x62, 0x79, 0x74, 0x65, 0x73, 0x52, 0x65, 0x61, 0x64, 0x12, 0x2a, 0x0a, 0x11,
+	0x66, 0x69, 0x72, 0x73, 0x74, 0x5f, 0x61, 0x63, 0x63, 0x65, 0x73, 0x73, 0x65, 0x64, 0x5f, 0x61,
+	0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x66, 0x69, 0x72, 0x73, 0x74, 0x41, 0x63,
+	0x63, 0x65, 0x73, 0x73, 0x65, 0x64, 0x41, 0x74, 0x22, 0xed, 0x01, 0x0a, 0x15, 0x47, 0x65, 0x74,
+	0x49, 0x6d, 0x61, 0x67, 0x65, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x3c, 0x0a, 0x09, 0x68, 0x6f, 0x74, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69,
+	0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c,
+	0x65, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x08, 0x68, 0x6f, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x73,
+	0x12, 0x42, 0x0a, 0x0c, 0x61, 0x63, 0x63, 0x65, 0x73, 0x73, 0x5f, 0x6f, 0x72, 0x64, 0x65, 0x72,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76,
+	0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69,
+	0x6c, 0x65, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x0b, 0x61, 0x63, 0x63, 0x65, 0x73, 0x73, 0x4f,
+	0x72, 0x64, 0x65, 0x72, 0x12, 0x28, 0x0a, 0x10, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x62, 0x79,
+	0x74, 0x65, 0x73, 0x5f, 0x72, 0x65, 0x61, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0e,
+	0x74, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x79, 0x74, 0x65, 0x73, 0x52, 0x65, 0x61, 0x64, 0x12, 0x28,
+	0x0a, 0x10, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x6f, 0x70, 0x65, 0x6e, 0x5f, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0e, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x4f,
+	0x70, 0x65, 0x6e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x3e, 0x0a, 0x19, 0x47, 0x65, 0x74, 0x50,
+	0x72, 0x65, 0x66, 0x65, 0x74, 0x63, 0x68, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x5f, 0x64,
+	0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x69, 0x6d, 0x61,
+	0x67, 0x65, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x22, 0x3f, 0x0a, 0x0d, 0x50, 0x72, 0x65, 0x66,
+	0x65, 0x74, 0x63, 0x68, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66,
+	0x73, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65,
+	0x74, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x22, 0x5f, 0x0a, 0x0c, 0x50, 0x72, 0x65,
+	0x66, 0x65, 0x74, 0x63, 0x68, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74,
+	0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x3b, 0x0a,
+	0x06, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x23, 0x2e,
+	0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66,
+	0x73, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x65, 0x66, 0x65, 0x74, 0x63, 0x68, 0x52, 0x61, 0x6e,
+	0x67, 0x65, 0x52, 0x06, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x22, 0x75, 0x0a, 0x1a, 0x47, 0x65,
+	0x74, 0x50, 0x72, 0x65, 0x66, 0x65, 0x74, 0x63, 0x68, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x38, 0x0a, 0x05, 0x66, 0x69, 0x6c, 0x65,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e,
+	0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x50,
+	0x72, 0x65, 0x66, 0x65, 0x74, 0x63, 0x68, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x05, 0x66, 0x69, 0x6c,
+	0x65, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x73, 0x69, 0x7a, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x53, 0x69, 0x7a,
+	0x65, 0x22, 0x52, 0x0a, 0x0e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x5f, 0x64, 0x69, 0x67, 0x65,
+	0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x44,
+	0x69, 0x67, 0x65, 0x73, 0x74, 0x22, 0x64, 0x0a, 0x0f, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x32, 0x0a, 0x15, 0x68, 0x65, 0x61, 0x72, 0x74,
+	0x62, 0x65, 0x61, 0x74, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x5f, 0x6d, 0x73,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x13, 0x68, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61,
+	0x74, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x4d, 0x73, 0x22, 0x34, 0x0a, 0x13, 0x4c,
+	0x69, 0x73, 0x74, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49,
+	0x64, 0x22, 0x72, 0x0a, 0x0a, 0x4f, 0x70, 0x65, 0x6e, 0x48, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x12,
+	0x10, 0x0a, 0x03, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x69,
+	0x64, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73,
+	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x44, 0x69,
+	0x67, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x1b, 0x0a, 0x09, 0x6f, 0x70, 0x65, 0x6e,
+	0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x6f, 0x70, 0x65,
+	0x6e, 0x65, 0x64, 0x41, 0x74, 0x22, 0x94, 0x01, 0x0a, 0x0b, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x49, 0x64, 0x12, 0x2a, 0x0a, 0x11, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x68, 0x65, 0x61,
+	0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x5f, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0f, 0x6c, 0x61, 0x73, 0x74, 0x48, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x41, 0x74,
+	0x12, 0x3a, 0x0a, 0x07, 0x68, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x20, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75,
+	0x66, 0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x70, 0x65, 0x6e, 0x48, 0x61, 0x6e,
+	0x64, 0x6c, 0x65, 0x52, 0x07, 0x68, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x73, 0x22, 0x55, 0x0a, 0x14,
+	0x4c, 0x69, 0x73, 0x74, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3d, 0x0a, 0x08, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76,
+	0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x08, 0x73, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x73, 0x22, 0x47, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x41, 0x74, 0x74, 0x72, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x6d, 0x61,
+	0x67, 0x65, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x22, 0x41, 0x0a, 0x0f,
+	0x47, 0x65, 0x74, 0x41, 0x74, 0x74, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x2e, 0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66,
+	0x73, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x22,
+	0x47, 0x0a, 0x0e, 0x52, 0x65, 0x61, 0x64, 0x44, 0x69, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x5f, 0x64,
+	0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x69, 0x6d, 0x61,
+	0x67, 0x65, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x22, 0x47, 0x0a, 0x0f, 0x52, 0x65, 0x61, 0x64,
+	0x44, 0x69, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x34, 0x0a, 0x07, 0x65,
+	0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x62,
+	0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66, 0x73,
+	0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65,
+	0x73, 0x22, 0x5c, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x58, 0x61, 0x74, 0x74, 0x72, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x6d, 0x61, 0x67,
+	0x65, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
+	0x69, 0x6d, 0x61, 0x67, 0x65, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22,
+	0x37, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x58, 0x61, 0x74, 0x74, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x19, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0c, 0x48, 0x00, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x88, 0x01, 0x01, 0x42, 0x08,
+	0x0a, 0x06, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x49, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74,
+	0x58, 0x61, 0x74, 0x74, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04,
+	0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68,
+	0x12, 0x21, 0x0a, 0x0c, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x44, 0x69, 0x67,
+	0x65, 0x73, 0x74, 0x22, 0x29, 0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x58, 0x61, 0x74, 0x74, 0x72,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x61, 0x6d, 0x65,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x22, 0x95,
+	0x01, 0x0a, 0x0b, 0x4f, 0x70, 0x65, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12,
+	0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61,
+	0x74, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x6c, 0x61, 0x67, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x05, 0x66, 0x6c, 0x61, 0x67, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x6d, 0x61, 0x67,
+	0x65, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
+	0x69, 0x6d, 0x61, 0x67, 0x65, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x70,
+	0x72, 0x65, 0x66, 0x65, 0x74, 0x63, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x70,
+	0x72, 0x65, 0x66, 0x65, 0x74, 0x63, 0x68, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x65, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x22, 0x20, 0x0a, 0x0c, 0x4f, 0x70, 0x65, 0x6e, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x69, 0x64, 0x22, 0x4b, 0x0a, 0x0b, 0x52, 0x65, 0x61, 0x64,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x69, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66,
+	0x73, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65,
+	0x74, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x04, 0x73, 0x69, 0x7a, 0x65, 0x22, 0x22, 0x0a, 0x0c, 0x52, 0x65, 0x61, 0x64, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x8e, 0x01, 0x0a, 0x0d, 0x52, 0x65,
+	0x61, 0x64, 0x41, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x69,
+	0x6d, 0x61, 0x67, 0x65, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0b, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12, 0x12,
+	0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61,
+	0x74, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69,
+	0x7a, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x1a,
+	0x0a, 0x08, 0x70, 0x72, 0x65, 0x66, 0x65, 0x74, 0x63, 0x68, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x08, 0x70, 0x72, 0x65, 0x66, 0x65, 0x74, 0x63, 0x68, 0x22, 0x24, 0x0a, 0x0e, 0x52, 0x65,
+	0x61, 0x64, 0x41, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61,
+	0x22, 0xa8, 0x01, 0x0a, 0x11, 0x52, 0x65, 0x61, 0x64, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x69, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x6d, 0x61, 0x67,
+	0x65, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
+	0x69, 0x6d, 0x61, 0x67, 0x65, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x70,
+	0x61, 0x74, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12,
+	0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74,
+	0x68, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x12,
+	0x1a, 0x0a, 0x08, 0x70, 0x72, 0x65, 0x66, 0x65, 0x74, 0x63, 0x68, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x08, 0x70, 0x72, 0x65, 0x66, 0x65, 0x74, 0x63, 0x68, 0x22, 0x40, 0x0a, 0x12, 0x52,
+	0x65, 0x61, 0x64, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74,
+	0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x7a, 0x0a,
+	0x11, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x61, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x5f, 0x64, 0x69, 0x67, 0x65,
+	0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x44,
+	0x69, 0x67, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66,
+	0x73, 0x65, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65,
+	0x74, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x22, 0x14, 0x0a, 0x12, 0x52, 0x65, 0x63,
+	0x6f, 0x72, 0x64, 0x52, 0x65, 0x61, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x22, 0x0a, 0x0e, 0x52, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x75, 0x69, 0x64, 0x22, 0x11, 0x0a, 0x0f, 0x52, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2a, 0xa5, 0x02, 0x0a, 0x0e, 0x49, 0x6d, 0x61, 0x67, 0x65,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x4b, 0x69, 0x6e, 0x64, 0x12, 0x20, 0x0a, 0x1c, 0x49, 0x4d, 0x41,
+	0x47, 0x45, 0x5f, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x4b, 0x49, 0x4e, 0x44, 0x5f, 0x55, 0x4e,
+	0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x23, 0x0a, 0x1f, 0x49,
+	0x4d, 0x41, 0x47, 0x45, 0x5f, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x4b, 0x49, 0x4e, 0x44, 0x5f,
+	0x4c, 0x41, 0x59, 0x45, 0x52, 0x5f, 0x52, 0x45, 0x53, 0x4f, 0x4c, 0x56, 0x45, 0x44, 0x10, 0x01,
+	0x12, 0x26, 0x0a, 0x22, 0x49, 0x4d, 0x41, 0x47, 0x45, 0x5f, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f,
+	0x4b, 0x49, 0x4e, 0x44, 0x5f, 0x4c, 0x41, 0x59, 0x45, 0x52, 0x5f, 0x44, 0x4f, 0x57, 0x4e, 0x4c,
+	0x4f, 0x41, 0x44, 0x49, 0x4e, 0x47, 0x10, 0x02, 0x12, 0x24, 0x0a, 0x20, 0x49, 0x4d, 0x41, 0x47,
+	0x45, 0x5f, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x4b, 0x49, 0x4e, 0x44, 0x5f, 0x4c, 0x41, 0x59,
+	0x45, 0x52, 0x5f, 0x45, 0x58, 0x54, 0x52, 0x41, 0x43, 0x54, 0x45, 0x44, 0x10, 0x03, 0x12, 0x22,
+	0x0a, 0x1e, 0x49, 0x4d, 0x41, 0x47, 0x45, 0x5f, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x4b, 0x49,
+	0x4e, 0x44, 0x5f, 0x4c, 0x41, 0x59, 0x45, 0x52, 0x5f, 0x49, 0x4e, 0x44, 0x45, 0x58, 0x45, 0x44,
+	0x10, 0x04, 0x12, 0x21, 0x0a, 0x1d, 0x49, 0x4d, 0x41, 0x47, 0x45, 0x5f, 0x45, 0x56, 0x45, 0x4e,
+	0x54, 0x5f, 0x4b, 0x49, 0x4e, 0x44, 0x5f, 0x4c, 0x41, 0x59, 0x45, 0x52, 0x5f, 0x4a, 0x4f, 0x49,
+	0x4e, 0x45, 0x44, 0x10, 0x05, 0x12, 0x1a, 0x0a, 0x16, 0x49, 0x4d, 0x41, 0x47, 0x45, 0x5f, 0x45,
+	0x56, 0x45, 0x4e, 0x54, 0x5f, 0x4b, 0x49, 0x4e, 0x44, 0x5f, 0x52, 0x45, 0x41, 0x44, 0x59, 0x10,
+	0x06, 0x12, 0x1b, 0x0a, 0x17, 0x49, 0x4d, 0x41, 0x47, 0x45, 0x5f, 0x45, 0x56, 0x45, 0x4e, 0x54,
+	0x5f, 0x4b, 0x49, 0x4e, 0x44, 0x5f, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x10, 0x07, 0x32, 0xde,
+	0x0d, 0x0a, 0x0b, 0x46, 0x75, 0x73, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x67,
+	0x0a, 0x0c, 0x50, 0x72, 0x65, 0x70, 0x61, 0x72, 0x65, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x29,
+	0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e,
+	0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x65, 0x70, 0x61, 0x72, 0x65, 0x49, 0x6d, 0x61,
+	0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2a, 0x2e, 0x62, 0x61, 0x65, 0x70,
+	0x6f, 0x2e, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31,
+	0x2e, 0x50, 0x72, 0x65, 0x70, 0x61, 0x72, 0x65, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x64, 0x0a, 0x0b, 0x49, 0x6d, 0x70, 0x6f, 0x72,
+	0x74, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x28, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76,
+	0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6d,
+	0x70, 0x6f, 0x72, 0x74, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x29, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66,
+	0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x49, 0x6d,
+	0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x61, 0x0a,
+	0x0a, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x61, 0x64, 0x79, 0x12, 0x27, 0x2e, 0x62, 0x61,
+	0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66, 0x73, 0x2e,
+	0x76, 0x31, 0x2e, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x61, 0x64, 0x79, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73,
+	0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6d, 0x61, 0x67,
+	0x65, 0x52, 0x65, 0x61, 0x64, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
+	0x12, 0x63, 0x0a, 0x0a, 0x57, 0x61, 0x74, 0x63, 0x68, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x27,
+	0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e,
+	0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x49, 0x6d, 0x61, 0x67, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e,
+	0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x57,
+	0x61, 0x74, 0x63, 0x68, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x6a, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x49, 0x6d, 0x61, 0x67,
+	0x65, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x2a, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76,
+	0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65,
+	0x74, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x2b, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73, 0x63, 0x61,
+	0x75, 0x66, 0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x6d, 0x61,
+	0x67, 0x65, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x00, 0x12, 0x79, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x50, 0x72, 0x65, 0x66, 0x65, 0x74, 0x63, 0x68,
+	0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x2f, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e,
+	0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x47,
+	0x65, 0x74, 0x50, 0x72, 0x65, 0x66, 0x65, 0x74, 0x63, 0x68, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x30, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f,
+	0x2e, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e,
+	0x47, 0x65, 0x74, 0x50, 0x72, 0x65, 0x66, 0x65, 0x74, 0x63, 0x68, 0x50, 0x72, 0x6f, 0x66, 0x69,
+	0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x5c, 0x0a, 0x07,
+	0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x24, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e,
+	0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x53,
+	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e,
+	0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66,
+	0x73, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x12, 0x67, 0x0a, 0x0c, 0x4c, 0x69,
+	0x73, 0x74, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x29, 0x2e, 0x62, 0x61, 0x65,
+	0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76,
+	0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2a, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69,
+	0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73,
+	0x74, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x00, 0x12, 0x58, 0x0a, 0x07, 0x47, 0x65, 0x74, 0x41, 0x74, 0x74, 0x72, 0x12, 0x24,
+	0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e,
+	0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x41, 0x74, 0x74, 0x72, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73,
+	0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x41,
+	0x74, 0x74, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x58, 0x0a,
+	0x07, 0x52, 0x65, 0x61, 0x64, 0x44, 0x69, 0x72, 0x12, 0x24, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f,
+	0x2e, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e,
+	0x52, 0x65, 0x61, 0x64, 0x44, 0x69, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x25,
+	0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e,
+	0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x44, 0x69, 0x72, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x5b, 0x0a, 0x08, 0x47, 0x65, 0x74, 0x58, 0x61,
+	0x74, 0x74, 0x72, 0x12, 0x25, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73, 0x63,
+	0x61, 0x75, 0x66, 0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x58, 0x61,
+	0x74, 0x74, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x26, 0x2e, 0x62, 0x61, 0x65,
+	0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76,
+	0x31, 0x2e, 0x47, 0x65, 0x74, 0x58, 0x61, 0x74, 0x74, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x00, 0x12, 0x5e, 0x0a, 0x09, 0x4c, 0x69, 0x73, 0x74, 0x58, 0x61, 0x74, 0x74,
+	0x72, 0x12, 0x26, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75,
+	0x66, 0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x58, 0x61, 0x74,
+	0x74, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x27, 0x2e, 0x62, 0x61, 0x65, 0x70,
+	0x6f, 0x2e, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31,
+	0x2e, 0x4c, 0x69, 0x73, 0x74, 0x58, 0x61, 0x74, 0x74, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x00, 0x12, 0x4f, 0x0a, 0x04, 0x4f, 0x70, 0x65, 0x6e, 0x12, 0x21, 0x2e, 0x62,
+	0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66, 0x73,
+	0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x70, 0x65, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x22, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73,
+	0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x70, 0x65, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4f, 0x0a, 0x04, 0x52, 0x65, 0x61, 0x64, 0x12, 0x21, 0x2e,
+	0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66,
+	0x73, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x22, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66,
+	0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x55, 0x0a, 0x06, 0x52, 0x65, 0x61, 0x64, 0x41, 0x74,
+	0x12, 0x23, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66,
+	0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x41, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69,
+	0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x61,
+	0x64, 0x41, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x63, 0x0a,
+	0x0a, 0x52, 0x65, 0x61, 0x64, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x27, 0x2e, 0x62, 0x61,
+	0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66, 0x73, 0x2e,
+	0x76, 0x31, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73,
+	0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x61, 0x64,
+	0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
+	0x30, 0x01, 0x12, 0x61, 0x0a, 0x0a, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x61, 0x64,
+	0x12, 0x27, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66,
+	0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x52, 0x65,
+	0x61, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e, 0x62, 0x61, 0x65, 0x70,
+	0x6f, 0x2e, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31,
+	0x2e, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x61, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x58, 0x0a, 0x07, 0x52, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65,
+	0x12, 0x24, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66,
+	0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76,
+	0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65,
+	0x6c, 0x65, 0x61, 0x73, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42,
+	0x35, 0x5a, 0x33, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x62, 0x61,
+	0x65, 0x70, 0x6f, 0x2d, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2f, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75,
+	0x66, 0x73, 0x2f, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2f, 0x66, 0x73, 0x70, 0x62, 0x2f, 0x76,
+	0x31, 0x3b, 0x66, 0x73, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_v1_rpc_proto_rawDescOnce sync.Once
+	file_v1_rpc_proto_rawDescData = file_v1_rpc_proto_rawDesc
+)
+
+func file_v1_rpc_proto_rawDescGZIP() []byte {
+	file_v1_rpc_proto_rawDescOnce.Do(func() {
+		file_v1_rpc_proto_rawDescData = protoimpl.X.CompressGZIP(file_v1_rpc_proto_rawDescData)
+	})
+	return file_v1_rpc_proto_rawDescData
+}
+
+var file_v1_rpc_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_v1_rpc_proto_msgTypes = make([]protoimpl.MessageInfo, 43)
+var file_v1_rpc_proto_goTypes = []interface{}{
+	(ImageEventKind)(0),                // 0: baepo.viscaufs.fs.v1.ImageEventKind
+	(*File)(nil),                       // 1: baepo.viscaufs.fs.v1.File
+	(*PrepareImageRequest)(nil),        // 2: baepo.viscaufs.fs.v1.PrepareImageRequest
+	(*Platform)(nil),                   // 3: baepo.viscaufs.fs.v1.Platform
+	(*PrepareImageResponse)(nil),       // 4: baepo.viscaufs.fs.v1.PrepareImageResponse
+	(*ImportImageRequest)(nil),         // 5: baepo.viscaufs.fs.v1.ImportImageRequest
+	(*ImportImageResponse)(nil),        // 6: baepo.viscaufs.fs.v1.ImportImageResponse
+	(*ImageReadyRequest)(nil),          // 7: baepo.viscaufs.fs.v1.ImageReadyRequest
+	(*ImageReadyResponse)(nil),         // 8: baepo.viscaufs.fs.v1.ImageReadyResponse
+	(*WatchImageRequest)(nil),          // 9: baepo.viscaufs.fs.v1.WatchImageRequest
+	(*WatchImageResponse)(nil),         // 10: baepo.viscaufs.fs.v1.WatchImageResponse
+	(*GetImageStatsRequest)(nil),       // 11: baepo.viscaufs.fs.v1.GetImageStatsRequest
+	(*FileStats)(nil),                  // 12: baepo.viscaufs.fs.v1.FileStats
+	(*GetImageStatsResponse)(nil),      // 13: baepo.viscaufs.fs.v1.GetImageStatsResponse
+	(*GetPrefetchProfileRequest)(nil),  // 14: baepo.viscaufs.fs.v1.GetPrefetchProfileRequest
+	(*PrefetchRange)(nil),              // 15: baepo.viscaufs.fs.v1.PrefetchRange
+	(*PrefetchFile)(nil),               // 16: baepo.viscaufs.fs.v1.PrefetchFile
+	(*GetPrefetchProfileResponse)(nil), // 17: baepo.viscaufs.fs.v1.GetPrefetchProfileResponse
+	(*SessionRequest)(nil),             // 18: baepo.viscaufs.fs.v1.SessionRequest
+	(*SessionResponse)(nil),            // 19: baepo.viscaufs.fs.v1.SessionResponse
+	(*ListSessionsRequest)(nil),        // 20: baepo.viscaufs.fs.v1.ListSessionsRequest
+	(*OpenHandle)(nil),                 // 21: baepo.viscaufs.fs.v1.OpenHandle
+	(*SessionInfo)(nil),                // 22: baepo.viscaufs.fs.v1.SessionInfo
+	(*ListSessionsResponse)(nil),       // 23: baepo.viscaufs.fs.v1.ListSessionsResponse
+	(*GetAttrRequest)(nil),             // 24: baepo.viscaufs.fs.v1.GetAttrRequest
+	(*GetAttrResponse)(nil),            // 25: baepo.viscaufs.fs.v1.GetAttrResponse
+	(*ReadDirRequest)(nil),             // 26: baepo.viscaufs.fs.v1.ReadDirRequest
+	(*ReadDirResponse)(nil),            // 27: baepo.viscaufs.fs.v1.ReadDirResponse
+	(*GetXattrRequest)(nil),            // 28: baepo.viscaufs.fs.v1.GetXattrRequest
+	(*GetXattrResponse)(nil),           // 29: baepo.viscaufs.fs.v1.GetXattrResponse
+	(*ListXattrRequest)(nil),           // 30: baepo.viscaufs.fs.v1.ListXattrRequest
+	(*ListXattrResponse)(nil),          // 31: baepo.viscaufs.fs.v1.ListXattrResponse
+	(*OpenRequest)(nil),                // 32: baepo.viscaufs.fs.v1.OpenRequest
+	(*OpenResponse)(nil),               // 33: baepo.viscaufs.fs.v1.OpenResponse
+	(*ReadRequest)(nil),                // 34: baepo.viscaufs.fs.v1.ReadRequest
+	(*ReadResponse)(nil),               // 35: baepo.viscaufs.fs.v1.ReadResponse
+	(*ReadAtRequest)(nil),              // 36: baepo.viscaufs.fs.v1.ReadAtRequest
+	(*ReadAtResponse)(nil),             // 37: baepo.viscaufs.fs.v1.ReadAtResponse
+	(*ReadStreamRequest)(nil),          // 38: baepo.viscaufs.fs.v1.ReadStreamRequest
+	(*ReadStreamResponse)(nil),         // 39: baepo.viscaufs.fs.v1.ReadStreamResponse
+	(*RecordReadRequest)(nil),          // 40: baepo.viscaufs.fs.v1.RecordReadRequest
+	(*RecordReadResponse)(nil),         // 41: baepo.viscaufs.fs.v1.RecordReadResponse
+	(*ReleaseRequest)(nil),             // 42: baepo.viscaufs.fs.v1.ReleaseRequest
+	(*ReleaseResponse)(nil),            // 43: baepo.viscaufs.fs.v1.ReleaseResponse
+	(*FileAttributes)(nil),             // 44: baepo.viscaufs.fs.v1.FileAttributes
+}
+var file_v1_rpc_proto_depIdxs = []int32{
+	44, // 0: baepo.viscaufs.fs.v1.File.attributes:type_name -> baepo.viscaufs.fs.v1.FileAttributes
+	3,  // 1: baepo.viscaufs.fs.v1.PrepareImageRequest.platform:type_name -> baepo.viscaufs.fs.v1.Platform
+	3,  // 2: baepo.viscaufs.fs.v1.ImportImageRequest.platform:type_name -> baepo.viscaufs.fs.v1.Platform
+	0,  // 3: baepo.viscaufs.fs.v1.WatchImageResponse.kind:type_name -> baepo.viscaufs.fs.v1.ImageEventKind
+	12, // 4: baepo.viscaufs.fs.v1.GetImageStatsResponse.hot_files:type_name -> baepo.viscaufs.fs.v1.FileStats
+	12, // 5: baepo.viscaufs.fs.v1.GetImageStatsResponse.access_order:type_name -> baepo.viscaufs.fs.v1.FileStats
+	15, // 6: baepo.viscaufs.fs.v1.PrefetchFile.ranges:type_name -> baepo.viscaufs.fs.v1.PrefetchRange
+	16, // 7: baepo.viscaufs.fs.v1.GetPrefetchProfileResponse.files:type_name -> baepo.viscaufs.fs.v1.PrefetchFile
+	21, // 8: baepo.viscaufs.fs.v1.SessionInfo.handles:type_name -> baepo.viscaufs.fs.v1.OpenHandle
+	22, // 9: baepo.viscaufs.fs.v1.ListSessionsResponse.sessions:type_name -> baepo.viscaufs.fs.v1.SessionInfo
+	1,  // 10: baepo.viscaufs.fs.v1.GetAttrResponse.file:type_name -> baepo.viscaufs.fs.v1.File
+	1,  // 11: baepo.viscaufs.fs.v1.ReadDirResponse.entries:type_name -> baepo.viscaufs.fs.v1.File
+	2,  // 12: baepo.viscaufs.fs.v1.FuseService.PrepareImage:input_type -> baepo.viscaufs.fs.v1.PrepareImageRequest
+	5,  // 13: baepo.viscaufs.fs.v1.FuseService.ImportImage:input_type -> baepo.viscaufs.fs.v1.ImportImageRequest
+	7,  // 14: baepo.viscaufs.fs.v1.FuseService.ImageReady:input_type -> baepo.viscaufs.fs.v1.ImageReadyRequest
+	9,  // 15: baepo.viscaufs.fs.v1.FuseService.WatchImage:input_type -> baepo.viscaufs.fs.v1.WatchImageRequest
+	11, // 16: baepo.viscaufs.fs.v1.FuseService.GetImageStats:input_type -> baepo.viscaufs.fs.v1.GetImageStatsRequest
+	14, // 17: baepo.viscaufs.fs.v1.FuseService.GetPrefetchProfile:input_type -> baepo.viscaufs.fs.v1.GetPrefetchProfileRequest
+	18, // 18: baepo.viscaufs.fs.v1.FuseService.Session:input_type -> baepo.viscaufs.fs.v1.SessionRequest
+	20, // 19: baepo.viscaufs.fs.v1.FuseService.ListSessions:input_type -> baepo.viscaufs.fs.v1.ListSessionsRequest
+	24, // 20: baepo.viscaufs.fs.v1.FuseService.GetAttr:input_type -> baepo.viscaufs.fs.v1.GetAttrRequest
+	26, // 21: baepo.viscaufs.fs.v1.FuseService.ReadDir:input_type -> baepo.viscaufs.fs.v1.ReadDirRequest
+	28, // 22: baepo.viscaufs.fs.v1.FuseService.GetXattr:input_type -> baepo.viscaufs.fs.v1.GetXattrRequest
+	30, // 23: baepo.viscaufs.fs.v1.FuseService.ListXattr:input_type -> baepo.viscaufs.fs.v1.ListXattrRequest
+	32, // 24: baepo.viscaufs.fs.v1.FuseService.Open:input_type -> baepo.viscaufs.fs.v1.OpenRequest
+	34, // 25: baepo.viscaufs.fs.v1.FuseService.Read:input_type -> baepo.viscaufs.fs.v1.ReadRequest
+	36, // 26: baepo.viscaufs.fs.v1.FuseService.ReadAt:input_type -> baepo.viscaufs.fs.v1.ReadAtRequest
+	38, // 27: baepo.viscaufs.fs.v1.FuseService.ReadStream:input_type -> baepo.viscaufs.fs.v1.ReadStreamRequest
+	40, // 28: baepo.viscaufs.fs.v1.FuseService.RecordRead:input_type -> baepo.viscaufs.fs.v1.RecordReadRequest
+	42, // 29: baepo.viscaufs.fs.v1.FuseService.Release:input_type -> baepo.viscaufs.fs.v1.ReleaseRequest
+	4,  // 30: baepo.viscaufs.fs.v1.FuseService.PrepareImage:output_type -> baepo.viscaufs.fs.v1.PrepareImageResponse
+	6,  // 31: baepo.viscaufs.fs.v1.FuseService.ImportImage:output_type -> baepo.viscaufs.fs.v1.ImportImageResponse
+	8,  // 32: baepo.viscaufs.fs.v1.FuseService.ImageReady:output_type -> baepo.viscaufs.fs.v1.ImageReadyResponse
+	10, // 33: baepo.viscaufs.fs.v1.FuseService.WatchImage:output_type -> baepo.viscaufs.fs.v1.WatchImageResponse
+	13, // 34: baepo.viscaufs.fs.v1.FuseService.GetImageStats:output_type -> baepo.viscaufs.fs.v1.GetImageStatsResponse
+	17, // 35: baepo.viscaufs.fs.v1.FuseService.GetPrefetchProfile:output_type -> baepo.viscaufs.fs.v1.GetPrefetchProfileResponse
+	19, // 36: baepo.viscaufs.fs.v1.FuseService.Session:output_type -> baepo.viscaufs.fs.v1.SessionResponse
+	23, // 37: baepo.viscaufs.fs.v1.FuseService.ListSessions:output_type -> baepo.viscaufs.fs.v1.ListSessionsResponse
+	25, // 38: baepo.viscaufs.fs.v1.FuseService.GetAttr:output_type -> baepo.viscaufs.fs.v1.GetAttrResponse
+	27, // 39: baepo.viscaufs.fs.v1.FuseService.ReadDir:output_type -> baepo.viscaufs.fs.v1.ReadDirResponse
+	29, // 40: baepo.viscaufs.fs.v1.FuseService.GetXattr:output_type -> baepo.viscaufs.fs.v1.GetXattrResponse
+	31, // 41: baepo.viscaufs.fs.v1.FuseService.ListXattr:output_type -> baepo.viscaufs.fs.v1.ListXattrResponse
+	33, // 42: baepo.viscaufs.fs.v1.FuseService.Open:output_type -> baepo.viscaufs.fs.v1.OpenResponse
+	35, // 43: baepo.viscaufs.fs.v1.FuseService.Read:output_type -> baepo.viscaufs.fs.v1.ReadResponse
+	37, // 44: baepo.viscaufs.fs.v1.FuseService.ReadAt:output_type -> baepo.viscaufs.fs.v1.ReadAtResponse
+	39, // 45: baepo.viscaufs.fs.v1.FuseService.ReadStream:output_type -> baepo.viscaufs.fs.v1.ReadStreamResponse
+	41, // 46: baepo.viscaufs.fs.v1.FuseService.RecordRead:output_type -> baepo.viscaufs.fs.v1.RecordReadResponse
+	43, // 47: baepo.viscaufs.fs.v1.FuseService.Release:output_type -> baepo.viscaufs.fs.v1.ReleaseResponse
+	30, // [30:48] is the sub-list for method output_type
+	12, // [12:30] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
+}
+
+func init() { file_v1_rpc_proto_init() }
+func file_v1_rpc_proto_init() {
+	if File_v1_rpc_proto != nil {
+		return
+	}
+	file_v1_types_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_v1_rpc_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*File); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_rpc_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PrepareImageRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_rpc_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Platform); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_rpc_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PrepareImageResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_rpc_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ImportImageRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_rpc_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ImportImageResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_rpc_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ImageReadyRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_rpc_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ImageReadyResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_rpc_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchImageRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_rpc_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchImageResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_rpc_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetImageStatsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_rpc_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FileStats); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_rpc_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetImageStatsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_rpc_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetPrefetchProfileRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_rpc_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PrefetchRange); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -958,8 +3248,8 @@ func file_v1_rpc_proto_init() {
 				return nil
 			}
 		}
-		file_v1_rpc_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PrepareImageRequest); i {
+		file_v1_rpc_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PrefetchFile); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -970,8 +3260,8 @@ func file_v1_rpc_proto_init() {
 				return nil
 			}
 		}
-		file_v1_rpc_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PrepareImageResponse); i {
+		file_v1_rpc_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetPrefetchProfileResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -982,8 +3272,8 @@ func file_v1_rpc_proto_init() {
 				return nil
 			}
 		}
-		file_v1_rpc_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ImageReadyRequest); i {
+		file_v1_rpc_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SessionRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -994,8 +3284,8 @@ func file_v1_rpc_proto_init() {
 				return nil
 			}
 		}
-		file_v1_rpc_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ImageReadyResponse); i {
+		file_v1_rpc_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SessionResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1006,7 +3296,55 @@ func file_v1_rpc_proto_init() {
 				return nil
 			}
 		}
-		file_v1_rpc_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+		file_v1_rpc_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListSessionsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_rpc_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OpenHandle); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_rpc_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SessionInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_rpc_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListSessionsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_rpc_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*GetAttrRequest); i {
 			case 0:
 				return &v.state
@@ -1018,7 +3356,7 @@ func file_v1_rpc_proto_init() {
 				return nil
 			}
 		}
-		file_v1_rpc_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+		file_v1_rpc_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*GetAttrResponse); i {
 			case 0:
 				return &v.state
@@ -1030,7 +3368,7 @@ func file_v1_rpc_proto_init() {
 				return nil
 			}
 		}
-		file_v1_rpc_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+		file_v1_rpc_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ReadDirRequest); i {
 			case 0:
 				return &v.state
@@ -1042,7 +3380,7 @@ func file_v1_rpc_proto_init() {
 				return nil
 			}
 		}
-		file_v1_rpc_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+		file_v1_rpc_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ReadDirResponse); i {
 			case 0:
 				return &v.state
@@ -1054,7 +3392,55 @@ func file_v1_rpc_proto_init() {
 				return nil
 			}
 		}
-		file_v1_rpc_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+		file_v1_rpc_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetXattrRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_rpc_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetXattrResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_rpc_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListXattrRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_rpc_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListXattrResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_rpc_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*OpenRequest); i {
 			case 0:
 				return &v.state
@@ -1066,7 +3452,7 @@ func file_v1_rpc_proto_init() {
 				return nil
 			}
 		}
-		file_v1_rpc_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+		file_v1_rpc_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*OpenResponse); i {
 			case 0:
 				return &v.state
@@ -1078,7 +3464,7 @@ func file_v1_rpc_proto_init() {
 				return nil
 			}
 		}
-		file_v1_rpc_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+		file_v1_rpc_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ReadRequest); i {
 			case 0:
 				return &v.state
@@ -1090,7 +3476,7 @@ func file_v1_rpc_proto_init() {
 				return nil
 			}
 		}
-		file_v1_rpc_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+		file_v1_rpc_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ReadResponse); i {
 			case 0:
 				return &v.state
@@ -1102,7 +3488,79 @@ func file_v1_rpc_proto_init() {
 				return nil
 			}
 		}
-		file_v1_rpc_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+		file_v1_rpc_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReadAtRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_rpc_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReadAtResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_rpc_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReadStreamRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_rpc_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReadStreamResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_rpc_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RecordReadRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_rpc_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RecordReadResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v1_rpc_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ReleaseRequest); i {
 			case 0:
 				return &v.state
@@ -1114,7 +3572,7 @@ func file_v1_rpc_proto_init() {
 				return nil
 			}
 		}
-		file_v1_rpc_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+		file_v1_rpc_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ReleaseResponse); i {
 			case 0:
 				return &v.state
@@ -1128,18 +3586,20 @@ func file_v1_rpc_proto_init() {
 		}
 	}
 	file_v1_rpc_proto_msgTypes[0].OneofWrappers = []interface{}{}
+	file_v1_rpc_proto_msgTypes[28].OneofWrappers = []interface{}{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_v1_rpc_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   15,
+			NumEnums:      1,
+			NumMessages:   43,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_v1_rpc_proto_goTypes,
 		DependencyIndexes: file_v1_rpc_proto_depIdxs,
+		EnumInfos:         file_v1_rpc_proto_enumTypes,
 		MessageInfos:      file_v1_rpc_proto_msgTypes,
 	}.Build()
 	File_v1_rpc_proto = out.File
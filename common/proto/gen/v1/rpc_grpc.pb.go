@@ -19,13 +19,24 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	FuseService_PrepareImage_FullMethodName = "/baepo.viscaufs.fs.v1.FuseService/PrepareImage"
-	FuseService_ImageReady_FullMethodName   = "/baepo.viscaufs.fs.v1.FuseService/ImageReady"
-	FuseService_GetAttr_FullMethodName      = "/baepo.viscaufs.fs.v1.FuseService/GetAttr"
-	FuseService_ReadDir_FullMethodName      = "/baepo.viscaufs.fs.v1.FuseService/ReadDir"
-	FuseService_Open_FullMethodName         = "/baepo.viscaufs.fs.v1.FuseService/Open"
-	FuseService_Read_FullMethodName         = "/baepo.viscaufs.fs.v1.FuseService/Read"
-	FuseService_Release_FullMethodName      = "/baepo.viscaufs.fs.v1.FuseService/Release"
+	FuseService_PrepareImage_FullMethodName       = "/baepo.viscaufs.fs.v1.FuseService/PrepareImage"
+	FuseService_ImportImage_FullMethodName        = "/baepo.viscaufs.fs.v1.FuseService/ImportImage"
+	FuseService_ImageReady_FullMethodName         = "/baepo.viscaufs.fs.v1.FuseService/ImageReady"
+	FuseService_WatchImage_FullMethodName         = "/baepo.viscaufs.fs.v1.FuseService/WatchImage"
+	FuseService_GetImageStats_FullMethodName      = "/baepo.viscaufs.fs.v1.FuseService/GetImageStats"
+	FuseService_GetPrefetchProfile_FullMethodName = "/baepo.viscaufs.fs.v1.FuseService/GetPrefetchProfile"
+	FuseService_Session_FullMethodName            = "/baepo.viscaufs.fs.v1.FuseService/Session"
+	FuseService_ListSessions_FullMethodName       = "/baepo.viscaufs.fs.v1.FuseService/ListSessions"
+	FuseService_GetAttr_FullMethodName            = "/baepo.viscaufs.fs.v1.FuseService/GetAttr"
+	FuseService_ReadDir_FullMethodName            = "/baepo.viscaufs.fs.v1.FuseService/ReadDir"
+	FuseService_GetXattr_FullMethodName           = "/baepo.viscaufs.fs.v1.FuseService/GetXattr"
+	FuseService_ListXattr_FullMethodName          = "/baepo.viscaufs.fs.v1.FuseService/ListXattr"
+	FuseService_Open_FullMethodName               = "/baepo.viscaufs.fs.v1.FuseService/Open"
+	FuseService_Read_FullMethodName               = "/baepo.viscaufs.fs.v1.FuseService/Read"
+	FuseService_ReadAt_FullMethodName             = "/baepo.viscaufs.fs.v1.FuseService/ReadAt"
+	FuseService_ReadStream_FullMethodName         = "/baepo.viscaufs.fs.v1.FuseService/ReadStream"
+	FuseService_RecordRead_FullMethodName         = "/baepo.viscaufs.fs.v1.FuseService/RecordRead"
+	FuseService_Release_FullMethodName            = "/baepo.viscaufs.fs.v1.FuseService/Release"
 )
 
 // FuseServiceClient is the client API for FuseService service.
@@ -34,16 +45,41 @@ const (
 type FuseServiceClient interface {
 	// PrepareImage prepares a container image for use with the FUSE filesystem
 	PrepareImage(ctx context.Context, in *PrepareImageRequest, opts ...grpc.CallOption) (*PrepareImageResponse, error)
+	// ImportImage prepares a container image stored on the server host, without going through a registry
+	ImportImage(ctx context.Context, in *ImportImageRequest, opts ...grpc.CallOption) (*ImportImageResponse, error)
 	// ImageReady indicates that the image is ready for use
 	ImageReady(ctx context.Context, in *ImageReadyRequest, opts ...grpc.CallOption) (*ImageReadyResponse, error)
+	// WatchImage streams the preparation progress of an image until it is ready or failed
+	WatchImage(ctx context.Context, in *WatchImageRequest, opts ...grpc.CallOption) (FuseService_WatchImageClient, error)
+	// GetImageStats returns the files of an image the clients opened and read
+	GetImageStats(ctx context.Context, in *GetImageStatsRequest, opts ...grpc.CallOption) (*GetImageStatsResponse, error)
+	// GetPrefetchProfile returns the files and ranges the previous runs of the image read during their startup, it
+	// marks the start of a new run
+	GetPrefetchProfile(ctx context.Context, in *GetPrefetchProfileRequest, opts ...grpc.CallOption) (*GetPrefetchProfileResponse, error)
+	// Session keeps a session of the client alive: the client sends a heartbeat message at the interval of the first
+	// response, the handles of the session are released once they stop. Closing the stream ends the session.
+	Session(ctx context.Context, opts ...grpc.CallOption) (FuseService_SessionClient, error)
+	// ListSessions lists the sessions and their open handles
+	ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error)
 	// GetAttr gets the attributes of a file or directory
 	GetAttr(ctx context.Context, in *GetAttrRequest, opts ...grpc.CallOption) (*GetAttrResponse, error)
 	// ReadDir reads a directory's contents
 	ReadDir(ctx context.Context, in *ReadDirRequest, opts ...grpc.CallOption) (*ReadDirResponse, error)
+	// GetXattr gets an extended attribute of a file or directory
+	GetXattr(ctx context.Context, in *GetXattrRequest, opts ...grpc.CallOption) (*GetXattrResponse, error)
+	// ListXattr lists the names of the extended attributes of a file or directory
+	ListXattr(ctx context.Context, in *ListXattrRequest, opts ...grpc.CallOption) (*ListXattrResponse, error)
 	// Open opens a file for reading
 	Open(ctx context.Context, in *OpenRequest, opts ...grpc.CallOption) (*OpenResponse, error)
 	// Read reads data from an open file
 	Read(ctx context.Context, in *ReadRequest, opts ...grpc.CallOption) (*ReadResponse, error)
+	// ReadAt reads data from a file without opening it, any server having the image can serve it
+	ReadAt(ctx context.Context, in *ReadAtRequest, opts ...grpc.CallOption) (*ReadAtResponse, error)
+	// ReadStream streams a range of a file in consecutive chunks
+	ReadStream(ctx context.Context, in *ReadStreamRequest, opts ...grpc.CallOption) (FuseService_ReadStreamClient, error)
+	// RecordRead records a range the client read from a window it streamed ahead: the windows are streamed as prefetch
+	// since the client may never read them
+	RecordRead(ctx context.Context, in *RecordReadRequest, opts ...grpc.CallOption) (*RecordReadResponse, error)
 	Release(ctx context.Context, in *ReleaseRequest, opts ...grpc.CallOption) (*ReleaseResponse, error)
 }
 
@@ -64,6 +100,15 @@ func (c *fuseServiceClient) PrepareImage(ctx context.Context, in *PrepareImageRe
 	return out, nil
 }
 
+func (c *fuseServiceClient) ImportImage(ctx context.Context, in *ImportImageRequest, opts ...grpc.CallOption) (*ImportImageResponse, error) {
+	out := new(ImportImageResponse)
+	err := c.cc.Invoke(ctx, FuseService_ImportImage_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *fuseServiceClient) ImageReady(ctx context.Context, in *ImageReadyRequest, opts ...grpc.CallOption) (*ImageReadyResponse, error) {
 	out := new(ImageReadyResponse)
 	err := c.cc.Invoke(ctx, FuseService_ImageReady_FullMethodName, in, out, opts...)
@@ -73,6 +118,96 @@ func (c *fuseServiceClient) ImageReady(ctx context.Context, in *ImageReadyReques
 	return out, nil
 }
 
+func (c *fuseServiceClient) WatchImage(ctx context.Context, in *WatchImageRequest, opts ...grpc.CallOption) (FuseService_WatchImageClient, error) {
+	stream, err := c.cc.NewStream(ctx, &FuseService_ServiceDesc.Streams[0], FuseService_WatchImage_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &fuseServiceWatchImageClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type FuseService_WatchImageClient interface {
+	Recv() (*WatchImageResponse, error)
+	grpc.ClientStream
+}
+
+type fuseServiceWatchImageClient struct {
+	grpc.ClientStream
+}
+
+func (x *fuseServiceWatchImageClient) Recv() (*WatchImageResponse, error) {
+	m := new(WatchImageResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *fuseServiceClient) GetImageStats(ctx context.Context, in *GetImageStatsRequest, opts ...grpc.CallOption) (*GetImageStatsResponse, error) {
+	out := new(GetImageStatsResponse)
+	err := c.cc.Invoke(ctx, FuseService_GetImageStats_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fuseServiceClient) GetPrefetchProfile(ctx context.Context, in *GetPrefetchProfileRequest, opts ...grpc.CallOption) (*GetPrefetchProfileResponse, error) {
+	out := new(GetPrefetchProfileResponse)
+	err := c.cc.Invoke(ctx, FuseService_GetPrefetchProfile_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fuseServiceClient) Session(ctx context.Context, opts ...grpc.CallOption) (FuseService_SessionClient, error) {
+	stream, err := c.cc.NewStream(ctx, &FuseService_ServiceDesc.Streams[1], FuseService_Session_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &fuseServiceSessionClient{stream}
+	return x, nil
+}
+
+type FuseService_SessionClient interface {
+	Send(*SessionRequest) error
+	Recv() (*SessionResponse, error)
+	grpc.ClientStream
+}
+
+type fuseServiceSessionClient struct {
+	grpc.ClientStream
+}
+
+func (x *fuseServiceSessionClient) Send(m *SessionRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *fuseServiceSessionClient) Recv() (*SessionResponse, error) {
+	m := new(SessionResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *fuseServiceClient) ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error) {
+	out := new(ListSessionsResponse)
+	err := c.cc.Invoke(ctx, FuseService_ListSessions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *fuseServiceClient) GetAttr(ctx context.Context, in *GetAttrRequest, opts ...grpc.CallOption) (*GetAttrResponse, error) {
 	out := new(GetAttrResponse)
 	err := c.cc.Invoke(ctx, FuseService_GetAttr_FullMethodName, in, out, opts...)
@@ -91,6 +226,24 @@ func (c *fuseServiceClient) ReadDir(ctx context.Context, in *ReadDirRequest, opt
 	return out, nil
 }
 
+func (c *fuseServiceClient) GetXattr(ctx context.Context, in *GetXattrRequest, opts ...grpc.CallOption) (*GetXattrResponse, error) {
+	out := new(GetXattrResponse)
+	err := c.cc.Invoke(ctx, FuseService_GetXattr_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fuseServiceClient) ListXattr(ctx context.Context, in *ListXattrRequest, opts ...grpc.CallOption) (*ListXattrResponse, error) {
+	out := new(ListXattrResponse)
+	err := c.cc.Invoke(ctx, FuseService_ListXattr_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *fuseServiceClient) Open(ctx context.Context, in *OpenRequest, opts ...grpc.CallOption) (*OpenResponse, error) {
 	out := new(OpenResponse)
 	err := c.cc.Invoke(ctx, FuseService_Open_FullMethodName, in, out, opts...)
@@ -109,6 +262,56 @@ func (c *fuseServiceClient) Read(ctx context.Context, in *ReadRequest, opts ...g
 	return out, nil
 }
 
+func (c *fuseServiceClient) ReadAt(ctx context.Context, in *ReadAtRequest, opts ...grpc.CallOption) (*ReadAtResponse, error) {
+	out := new(ReadAtResponse)
+	err := c.cc.Invoke(ctx, FuseService_ReadAt_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fuseServiceClient) ReadStream(ctx context.Context, in *ReadStreamRequest, opts ...grpc.CallOption) (FuseService_ReadStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &FuseService_ServiceDesc.Streams[2], FuseService_ReadStream_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &fuseServiceReadStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type FuseService_ReadStreamClient interface {
+	Recv() (*ReadStreamResponse, error)
+	grpc.ClientStream
+}
+
+type fuseServiceReadStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *fuseServiceReadStreamClient) Recv() (*ReadStreamResponse, error) {
+	m := new(ReadStreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *fuseServiceClient) RecordRead(ctx context.Context, in *RecordReadRequest, opts ...grpc.CallOption) (*RecordReadResponse, error) {
+	out := new(RecordReadResponse)
+	err := c.cc.Invoke(ctx, FuseService_RecordRead_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *fuseServiceClient) Release(ctx context.Context, in *ReleaseRequest, opts ...grpc.CallOption) (*ReleaseResponse, error) {
 	out := new(ReleaseResponse)
 	err := c.cc.Invoke(ctx, FuseService_Release_FullMethodName, in, out, opts...)
@@ -124,16 +327,41 @@ func (c *fuseServiceClient) Release(ctx context.Context, in *ReleaseRequest, opt
 type FuseServiceServer interface {
 	// PrepareImage prepares a container image for use with the FUSE filesystem
 	PrepareImage(context.Context, *PrepareImageRequest) (*PrepareImageResponse, error)
+	// ImportImage prepares a container image stored on the server host, without going through a registry
+	ImportImage(context.Context, *ImportImageRequest) (*ImportImageResponse, error)
 	// ImageReady indicates that the image is ready for use
 	ImageReady(context.Context, *ImageReadyRequest) (*ImageReadyResponse, error)
+	// WatchImage streams the preparation progress of an image until it is ready or failed
+	WatchImage(*WatchImageRequest, FuseService_WatchImageServer) error
+	// GetImageStats returns the files of an image the clients opened and read
+	GetImageStats(context.Context, *GetImageStatsRequest) (*GetImageStatsResponse, error)
+	// GetPrefetchProfile returns the files and ranges the previous runs of the image read during their startup, it
+	// marks the start of a new run
+	GetPrefetchProfile(context.Context, *GetPrefetchProfileRequest) (*GetPrefetchProfileResponse, error)
+	// Session keeps a session of the client alive: the client sends a heartbeat message at the interval of the first
+	// response, the handles of the session are released once they stop. Closing the stream ends the session.
+	Session(FuseService_SessionServer) error
+	// ListSessions lists the sessions and their open handles
+	ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error)
 	// GetAttr gets the attributes of a file or directory
 	GetAttr(context.Context, *GetAttrRequest) (*GetAttrResponse, error)
 	// ReadDir reads a directory's contents
 	ReadDir(context.Context, *ReadDirRequest) (*ReadDirResponse, error)
+	// GetXattr gets an extended attribute of a file or directory
+	GetXattr(context.Context, *GetXattrRequest) (*GetXattrResponse, error)
+	// ListXattr lists the names of the extended attributes of a file or directory
+	ListXattr(context.Context, *ListXattrRequest) (*ListXattrResponse, error)
 	// Open opens a file for reading
 	Open(context.Context, *OpenRequest) (*OpenResponse, error)
 	// Read reads data from an open file
 	Read(context.Context, *ReadRequest) (*ReadResponse, error)
+	// ReadAt reads data from a file without opening it, any server having the image can serve it
+	ReadAt(context.Context, *ReadAtRequest) (*ReadAtResponse, error)
+	// ReadStream streams a range of a file in consecutive chunks
+	ReadStream(*ReadStreamRequest, FuseService_ReadStreamServer) error
+	// RecordRead records a range the client read from a window it streamed ahead: the windows are streamed as prefetch
+	// since the client may never read them
+	RecordRead(context.Context, *RecordReadRequest) (*RecordReadResponse, error)
 	Release(context.Context, *ReleaseRequest) (*ReleaseResponse, error)
 	mustEmbedUnimplementedFuseServiceServer()
 }
@@ -145,21 +373,54 @@ type UnimplementedFuseServiceServer struct {
 func (UnimplementedFuseServiceServer) PrepareImage(context.Context, *PrepareImageRequest) (*PrepareImageResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PrepareImage not implemented")
 }
+func (UnimplementedFuseServiceServer) ImportImage(context.Context, *ImportImageRequest) (*ImportImageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportImage not implemented")
+}
 func (UnimplementedFuseServiceServer) ImageReady(context.Context, *ImageReadyRequest) (*ImageReadyResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ImageReady not implemented")
 }
+func (UnimplementedFuseServiceServer) WatchImage(*WatchImageRequest, FuseService_WatchImageServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchImage not implemented")
+}
+func (UnimplementedFuseServiceServer) GetImageStats(context.Context, *GetImageStatsRequest) (*GetImageStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetImageStats not implemented")
+}
+func (UnimplementedFuseServiceServer) GetPrefetchProfile(context.Context, *GetPrefetchProfileRequest) (*GetPrefetchProfileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPrefetchProfile not implemented")
+}
+func (UnimplementedFuseServiceServer) Session(FuseService_SessionServer) error {
+	return status.Errorf(codes.Unimplemented, "method Session not implemented")
+}
+func (UnimplementedFuseServiceServer) ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSessions not implemented")
+}
 func (UnimplementedFuseServiceServer) GetAttr(context.Context, *GetAttrRequest) (*GetAttrResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAttr not implemented")
 }
 func (UnimplementedFuseServiceServer) ReadDir(context.Context, *ReadDirRequest) (*ReadDirResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ReadDir not implemented")
 }
+func (UnimplementedFuseServiceServer) GetXattr(context.Context, *GetXattrRequest) (*GetXattrResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetXattr not implemented")
+}
+func (UnimplementedFuseServiceServer) ListXattr(context.Context, *ListXattrRequest) (*ListXattrResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListXattr not implemented")
+}
 func (UnimplementedFuseServiceServer) Open(context.Context, *OpenRequest) (*OpenResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Open not implemented")
 }
 func (UnimplementedFuseServiceServer) Read(context.Context, *ReadRequest) (*ReadResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Read not implemented")
 }
+func (UnimplementedFuseServiceServer) ReadAt(context.Context, *ReadAtRequest) (*ReadAtResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReadAt not implemented")
+}
+func (UnimplementedFuseServiceServer) ReadStream(*ReadStreamRequest, FuseService_ReadStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method ReadStream not implemented")
+}
+func (UnimplementedFuseServiceServer) RecordRead(context.Context, *RecordReadRequest) (*RecordReadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RecordRead not implemented")
+}
 func (UnimplementedFuseServiceServer) Release(context.Context, *ReleaseRequest) (*ReleaseResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Release not implemented")
 }
@@ -194,6 +455,24 @@ func _FuseService_PrepareImage_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _FuseService_ImportImage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportImageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FuseServiceServer).ImportImage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FuseService_ImportImage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FuseServiceServer).ImportImage(ctx, req.(*ImportImageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _FuseService_ImageReady_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ImageReadyRequest)
 	if err := dec(in); err != nil {
@@ -212,6 +491,107 @@ func _FuseService_ImageReady_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _FuseService_WatchImage_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchImageRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FuseServiceServer).WatchImage(m, &fuseServiceWatchImageServer{stream})
+}
+
+type FuseService_WatchImageServer interface {
+	Send(*WatchImageResponse) error
+	grpc.ServerStream
+}
+
+type fuseServiceWatchImageServer struct {
+	grpc.ServerStream
+}
+
+func (x *fuseServiceWatchImageServer) Send(m *WatchImageResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _FuseService_GetImageStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetImageStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FuseServiceServer).GetImageStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FuseService_GetImageStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FuseServiceServer).GetImageStats(ctx, req.(*GetImageStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FuseService_GetPrefetchProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPrefetchProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FuseServiceServer).GetPrefetchProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FuseService_GetPrefetchProfile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FuseServiceServer).GetPrefetchProfile(ctx, req.(*GetPrefetchProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FuseService_Session_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(FuseServiceServer).Session(&fuseServiceSessionServer{stream})
+}
+
+type FuseService_SessionServer interface {
+	Send(*SessionResponse) error
+	Recv() (*SessionRequest, error)
+	grpc.ServerStream
+}
+
+type fuseServiceSessionServer struct {
+	grpc.ServerStream
+}
+
+func (x *fuseServiceSessionServer) Send(m *SessionResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *fuseServiceSessionServer) Recv() (*SessionRequest, error) {
+	m := new(SessionRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _FuseService_ListSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FuseServiceServer).ListSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FuseService_ListSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FuseServiceServer).ListSessions(ctx, req.(*ListSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _FuseService_GetAttr_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetAttrRequest)
 	if err := dec(in); err != nil {
@@ -248,6 +628,42 @@ func _FuseService_ReadDir_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _FuseService_GetXattr_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetXattrRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FuseServiceServer).GetXattr(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FuseService_GetXattr_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FuseServiceServer).GetXattr(ctx, req.(*GetXattrRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FuseService_ListXattr_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListXattrRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FuseServiceServer).ListXattr(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FuseService_ListXattr_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FuseServiceServer).ListXattr(ctx, req.(*ListXattrRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _FuseService_Open_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(OpenRequest)
 	if err := dec(in); err != nil {
@@ -284,6 +700,63 @@ func _FuseService_Read_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _FuseService_ReadAt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadAtRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FuseServiceServer).ReadAt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FuseService_ReadAt_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FuseServiceServer).ReadAt(ctx, req.(*ReadAtRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FuseService_ReadStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ReadStreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FuseServiceServer).ReadStream(m, &fuseServiceReadStreamServer{stream})
+}
+
+type FuseService_ReadStreamServer interface {
+	Send(*ReadStreamResponse) error
+	grpc.ServerStream
+}
+
+type fuseServiceReadStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *fuseServiceReadStreamServer) Send(m *ReadStreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _FuseService_RecordRead_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecordReadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FuseServiceServer).RecordRead(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FuseService_RecordRead_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FuseServiceServer).RecordRead(ctx, req.(*RecordReadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _FuseService_Release_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ReleaseRequest)
 	if err := dec(in); err != nil {
@@ -313,10 +786,26 @@ var FuseService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "PrepareImage",
 			Handler:    _FuseService_PrepareImage_Handler,
 		},
+		{
+			MethodName: "ImportImage",
+			Handler:    _FuseService_ImportImage_Handler,
+		},
 		{
 			MethodName: "ImageReady",
 			Handler:    _FuseService_ImageReady_Handler,
 		},
+		{
+			MethodName: "GetImageStats",
+			Handler:    _FuseService_GetImageStats_Handler,
+		},
+		{
+			MethodName: "GetPrefetchProfile",
+			Handler:    _FuseService_GetPrefetchProfile_Handler,
+		},
+		{
+			MethodName: "ListSessions",
+			Handler:    _FuseService_ListSessions_Handler,
+		},
 		{
 			MethodName: "GetAttr",
 			Handler:    _FuseService_GetAttr_Handler,
@@ -325,6 +814,14 @@ var FuseService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ReadDir",
 			Handler:    _FuseService_ReadDir_Handler,
 		},
+		{
+			MethodName: "GetXattr",
+			Handler:    _FuseService_GetXattr_Handler,
+		},
+		{
+			MethodName: "ListXattr",
+			Handler:    _FuseService_ListXattr_Handler,
+		},
 		{
 			MethodName: "Open",
 			Handler:    _FuseService_Open_Handler,
@@ -333,11 +830,36 @@ var FuseService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Read",
 			Handler:    _FuseService_Read_Handler,
 		},
+		{
+			MethodName: "ReadAt",
+			Handler:    _FuseService_ReadAt_Handler,
+		},
+		{
+			MethodName: "RecordRead",
+			Handler:    _FuseService_RecordRead_Handler,
+		},
 		{
 			MethodName: "Release",
 			Handler:    _FuseService_Release_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchImage",
+			Handler:       _FuseService_WatchImage_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Session",
+			Handler:       _FuseService_Session_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ReadStream",
+			Handler:       _FuseService_ReadStream_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "v1/rpc.proto",
 }
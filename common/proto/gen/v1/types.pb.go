@@ -188,6 +188,10 @@ type FSIndexNode struct {
 	Attributes    *FileAttributes `protobuf:"bytes,2,opt,name=attributes,proto3" json:"attributes,omitempty"`
 	LayerPosition uint32          `protobuf:"varint,3,opt,name=layer_position,json=layerPosition,proto3" json:"layer_position,omitempty"`
 	SymlinkTarget *string         `protobuf:"bytes,4,opt,name=symlink_target,json=symlinkTarget,proto3,oneof" json:"symlink_target,omitempty"`
+	// hardlink_group is the path of the first entry of the layer linked to the same file, empty when not hardlinked
+	HardlinkGroup string `protobuf:"bytes,5,opt,name=hardlink_group,json=hardlinkGroup,proto3" json:"hardlink_group,omitempty"`
+	// xattrs are the extended attributes of the entry by name, like security.capability
+	Xattrs map[string][]byte `protobuf:"bytes,6,rep,name=xattrs,proto3" json:"xattrs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
 func (x *FSIndexNode) Reset() {
@@ -250,6 +254,20 @@ func (x *FSIndexNode) GetSymlinkTarget() string {
 	return ""
 }
 
+func (x *FSIndexNode) GetHardlinkGroup() string {
+	if x != nil {
+		return x.HardlinkGroup
+	}
+	return ""
+}
+
+func (x *FSIndexNode) GetXattrs() map[string][]byte {
+	if x != nil {
+		return x.Xattrs
+	}
+	return nil
+}
+
 type FSIndex struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -349,7 +367,7 @@ var file_v1_types_proto_rawDesc = []byte{
 	0x52, 0x03, 0x67, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x64, 0x65, 0x76, 0x18, 0x0e, 0x20,
 	0x01, 0x28, 0x04, 0x52, 0x04, 0x72, 0x64, 0x65, 0x76, 0x12, 0x18, 0x0a, 0x07, 0x62, 0x6c, 0x6b,
 	0x73, 0x69, 0x7a, 0x65, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x62, 0x6c, 0x6b, 0x73,
-	0x69, 0x7a, 0x65, 0x22, 0xcd, 0x01, 0x0a, 0x0b, 0x46, 0x53, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x4e,
+	0x69, 0x7a, 0x65, 0x22, 0xf6, 0x02, 0x0a, 0x0b, 0x46, 0x53, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x4e,
 	0x6f, 0x64, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28,
 	0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x44, 0x0a, 0x0a, 0x61, 0x74, 0x74, 0x72, 0x69,
 	0x62, 0x75, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x62, 0x61,
@@ -361,22 +379,32 @@ var file_v1_types_proto_rawDesc = []byte{
 	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2a, 0x0a, 0x0e, 0x73, 0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b, 0x5f,
 	0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0d,
 	0x73, 0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x88, 0x01, 0x01,
-	0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x73, 0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b, 0x5f, 0x74, 0x61, 0x72,
-	0x67, 0x65, 0x74, 0x22, 0xa0, 0x01, 0x0a, 0x07, 0x46, 0x53, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12,
-	0x37, 0x0a, 0x05, 0x70, 0x61, 0x74, 0x68, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21,
-	0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e,
-	0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x53, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x4e, 0x6f, 0x64,
-	0x65, 0x52, 0x05, 0x70, 0x61, 0x74, 0x68, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x77, 0x69, 0x74, 0x68,
-	0x6f, 0x75, 0x74, 0x5f, 0x64, 0x69, 0x72, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x77,
-	0x69, 0x74, 0x68, 0x6f, 0x75, 0x74, 0x44, 0x69, 0x72, 0x12, 0x21, 0x0a, 0x0c, 0x77, 0x69, 0x74,
-	0x68, 0x6f, 0x75, 0x74, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52,
-	0x0b, 0x77, 0x69, 0x74, 0x68, 0x6f, 0x75, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x18, 0x0a, 0x07,
-	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x76,
-	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x42, 0x35, 0x5a, 0x33, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
-	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2d, 0x63, 0x6c, 0x6f, 0x75, 0x64,
-	0x2f, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2f, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e,
-	0x2f, 0x66, 0x73, 0x70, 0x62, 0x2f, 0x76, 0x31, 0x3b, 0x66, 0x73, 0x70, 0x62, 0x62, 0x06, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x12, 0x25, 0x0a, 0x0e, 0x68, 0x61, 0x72, 0x64, 0x6c, 0x69, 0x6e, 0x6b, 0x5f, 0x67, 0x72, 0x6f,
+	0x75, 0x70, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x68, 0x61, 0x72, 0x64, 0x6c, 0x69,
+	0x6e, 0x6b, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x45, 0x0a, 0x06, 0x78, 0x61, 0x74, 0x74, 0x72,
+	0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2d, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e,
+	0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x46,
+	0x53, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x4e, 0x6f, 0x64, 0x65, 0x2e, 0x58, 0x61, 0x74, 0x74, 0x72,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x78, 0x61, 0x74, 0x74, 0x72, 0x73, 0x1a, 0x39,
+	0x0a, 0x0b, 0x58, 0x61, 0x74, 0x74, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
+	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x73, 0x79,
+	0x6d, 0x6c, 0x69, 0x6e, 0x6b, 0x5f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x22, 0xa0, 0x01, 0x0a,
+	0x07, 0x46, 0x53, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x37, 0x0a, 0x05, 0x70, 0x61, 0x74, 0x68,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x62, 0x61, 0x65, 0x70, 0x6f, 0x2e,
+	0x76, 0x69, 0x73, 0x63, 0x61, 0x75, 0x66, 0x73, 0x2e, 0x66, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x46,
+	0x53, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x05, 0x70, 0x61, 0x74, 0x68,
+	0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x77, 0x69, 0x74, 0x68, 0x6f, 0x75, 0x74, 0x5f, 0x64, 0x69, 0x72,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x77, 0x69, 0x74, 0x68, 0x6f, 0x75, 0x74, 0x44,
+	0x69, 0x72, 0x12, 0x21, 0x0a, 0x0c, 0x77, 0x69, 0x74, 0x68, 0x6f, 0x75, 0x74, 0x5f, 0x66, 0x69,
+	0x6c, 0x65, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x77, 0x69, 0x74, 0x68, 0x6f, 0x75,
+	0x74, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x42,
+	0x35, 0x5a, 0x33, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x62, 0x61,
+	0x65, 0x70, 0x6f, 0x2d, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x2f, 0x76, 0x69, 0x73, 0x63, 0x61, 0x75,
+	0x66, 0x73, 0x2f, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2f, 0x66, 0x73, 0x70, 0x62, 0x2f, 0x76,
+	0x31, 0x3b, 0x66, 0x73, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -391,20 +419,22 @@ func file_v1_types_proto_rawDescGZIP() []byte {
 	return file_v1_types_proto_rawDescData
 }
 
-var file_v1_types_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_v1_types_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_v1_types_proto_goTypes = []interface{}{
 	(*FileAttributes)(nil), // 0: baepo.viscaufs.fs.v1.FileAttributes
 	(*FSIndexNode)(nil),    // 1: baepo.viscaufs.fs.v1.FSIndexNode
 	(*FSIndex)(nil),        // 2: baepo.viscaufs.fs.v1.FSIndex
+	nil,                    // 3: baepo.viscaufs.fs.v1.FSIndexNode.XattrsEntry
 }
 var file_v1_types_proto_depIdxs = []int32{
 	0, // 0: baepo.viscaufs.fs.v1.FSIndexNode.attributes:type_name -> baepo.viscaufs.fs.v1.FileAttributes
-	1, // 1: baepo.viscaufs.fs.v1.FSIndex.paths:type_name -> baepo.viscaufs.fs.v1.FSIndexNode
-	2, // [2:2] is the sub-list for method output_type
-	2, // [2:2] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	3, // 1: baepo.viscaufs.fs.v1.FSIndexNode.xattrs:type_name -> baepo.viscaufs.fs.v1.FSIndexNode.XattrsEntry
+	1, // 2: baepo.viscaufs.fs.v1.FSIndex.paths:type_name -> baepo.viscaufs.fs.v1.FSIndexNode
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_v1_types_proto_init() }
@@ -457,7 +487,7 @@ func file_v1_types_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_v1_types_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   4,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -12,6 +12,7 @@ import (
 	"github.com/hanwen/go-fuse/v2/fuse"
 
 	"github.com/baepo-cloud/viscaufs-fs/viscaufs"
+	"github.com/baepo-cloud/viscaufs/common/loadbalancer"
 	fspb "github.com/baepo-cloud/viscaufs/common/proto/gen/v1"
 	"github.com/hanwen/go-fuse/v2/fs"
 	"google.golang.org/grpc"
@@ -25,12 +26,14 @@ func main() {
 		mountPoint  string
 		imageDigest string
 		debug       bool
+		prefetch    int
 	)
 
-	flag.StringVar(&serverAddr, "server", "localhost:8080", "filesystem server address")
+	flag.StringVar(&serverAddr, "server", "localhost:8080", "filesystem server address, host:port, unix:///path/to.sock, or srv:///_service._tcp.example.com to balance the images across the servers of a DNS SRV record")
 	flag.StringVar(&mountPoint, "mount", "/mnt/viscaufs", "Mount point for FUSE filesystem")
 	flag.StringVar(&imageDigest, "digest", "", "Docker image reference ID")
 	flag.BoolVar(&debug, "debug", true, "Enable debug logging")
+	flag.IntVar(&prefetch, "prefetch", 8, "Number of workers warming the caches with the startup profile of the image, 0 disables the prefetch")
 	flag.Parse()
 
 	handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
@@ -44,7 +47,10 @@ func main() {
 		os.Exit(1)
 	}
 
-	conn, err := grpc.NewClient(serverAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.NewClient(serverAddr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(loadbalancer.UnaryClientInterceptor),
+	)
 	if err != nil {
 		slog.Error("failed to connect to gRPC server", "error", err)
 		os.Exit(1)
@@ -77,10 +83,17 @@ func main() {
 			FsName:               "viscaufs",
 			RememberInodes:       true,
 			EnableSymlinkCaching: true,
-			DisableXAttrs:        true,
 		},
 	}
 
+	// the handles opened by the kernel are released by the server when the client stops, even without unmount
+	sessionCtx, endSession := context.WithCancel(context.Background())
+	sessionDone := make(chan struct{})
+	go func() {
+		defer close(sessionDone)
+		vfs.KeepSession(sessionCtx)
+	}()
+
 	// Mount the filesystem
 	server, err := fs.Mount(mountPoint, rootNode, opts)
 	if err != nil {
@@ -90,6 +103,17 @@ func main() {
 
 	slog.Info("successfully mounted filesystem", "mount_point", mountPoint)
 
+	if prefetch > 0 {
+		go func() {
+			start := time.Now()
+			if err := vfs.Prefetch(context.Background(), prefetch); err != nil {
+				slog.Warn("failed to prefetch startup profile", "error", err)
+				return
+			}
+			slog.Info("startup profile prefetched", "duration", time.Since(start))
+		}()
+	}
+
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
@@ -100,21 +124,99 @@ func main() {
 	}()
 
 	server.Wait()
+
+	endSession()
+	<-sessionDone
 }
 
-func waitForImageReady(client fspb.FuseServiceClient, ref string, timeout time.Duration) {
-	now := time.Now()
+// waitForImageReady watches the preparation of the image until it can be mounted, that is once its top layer is
+// joined or it is ready. The remaining events are logged in the background. It exits when the preparation fails or
+// when no event is received for timeout.
+func waitForImageReady(client fspb.FuseServiceClient, digest string, timeout time.Duration) {
+	ctx, cancel := context.WithCancel(loadbalancer.WithImageDigest(context.Background(), digest))
+
+	stream, err := client.WatchImage(ctx, &fspb.WatchImageRequest{ImageDigest: digest})
+	if err != nil {
+		slog.Error("failed to watch image", "error", err)
+		os.Exit(1)
+	}
+
+	type recvResult struct {
+		event *fspb.WatchImageResponse
+		err   error
+	}
+	results := make(chan recvResult)
+	go func() {
+		defer cancel()
+		defer close(results)
+		for {
+			event, err := stream.Recv()
+			results <- recvResult{event: event, err: err}
+			if err != nil || isTerminalImageEvent(event) {
+				return
+			}
+		}
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
 	for {
-		_, err := client.ImageReady(context.Background(), &fspb.ImageReadyRequest{ImageDigest: ref})
-		if err != nil {
-			if time.Since(now) > timeout {
-				slog.Error("Image not ready", "error", err)
+		select {
+		case <-timer.C:
+			cancel()
+			slog.Error("Image not ready", "error", "no progress received", "timeout", timeout)
+			os.Exit(1)
+		case result := <-results:
+			if result.err != nil {
+				slog.Error("Image not ready", "error", result.err)
+				os.Exit(1)
+			}
+
+			logImageEvent(result.event)
+			switch result.event.Kind {
+			case fspb.ImageEventKind_IMAGE_EVENT_KIND_FAILED:
 				os.Exit(1)
+			case fspb.ImageEventKind_IMAGE_EVENT_KIND_READY:
+				return
+			case fspb.ImageEventKind_IMAGE_EVENT_KIND_LAYER_JOINED:
+				// the top layer is indexed, lookups of the lower layers wait for them on the server
+				go func() {
+					for result := range results {
+						if result.err != nil {
+							slog.Error("image preparation watch interrupted", "error", result.err)
+							return
+						}
+						logImageEvent(result.event)
+					}
+				}()
+				return
 			}
-			time.Sleep(time.Millisecond * 150)
-			continue
+
+			timer.Reset(timeout)
 		}
+	}
+}
+
+func isTerminalImageEvent(event *fspb.WatchImageResponse) bool {
+	return event.Kind == fspb.ImageEventKind_IMAGE_EVENT_KIND_READY || event.Kind == fspb.ImageEventKind_IMAGE_EVENT_KIND_FAILED
+}
 
-		break
+func logImageEvent(event *fspb.WatchImageResponse) {
+	switch event.Kind {
+	case fspb.ImageEventKind_IMAGE_EVENT_KIND_FAILED:
+		slog.Error("image preparation failed", "error", event.Error)
+	case fspb.ImageEventKind_IMAGE_EVENT_KIND_READY:
+		slog.Info("image ready")
+	case fspb.ImageEventKind_IMAGE_EVENT_KIND_LAYER_DOWNLOADING:
+		slog.Debug("layer downloading",
+			"layer_digest", event.LayerDigest,
+			"bytes_downloaded", event.BytesDownloaded,
+			"bytes_total", event.BytesTotal)
+	default:
+		slog.Info("image preparation progress",
+			"event", event.Kind.String(),
+			"layer_digest", event.LayerDigest,
+			"layer_position", event.LayerPosition)
 	}
 }
@@ -13,13 +13,41 @@ type Cache struct {
 	indexAttrs *fsindex.Index
 	readDirs   *haxmap.Map[string, struct{}]
 
+	// xattrs holds the extended attributes fetched by path and name, xattrNames their names by path
+	xattrs     *haxmap.Map[string, xattrValue]
+	xattrNames *haxmap.Map[string, []string]
+
 	m sync.RWMutex
+
+	// blocks holds the file contents fetched ahead by the prefetch, aligned to blockSize
+	blocks     map[blockKey][]byte
+	blockSize  int64
+	blocksLock sync.Mutex
+}
+
+type blockKey struct {
+	path  string
+	index int64
+}
+
+// xattrKey joins the path and the name of an attribute, a path never holds a NUL byte
+func xattrKey(path, name string) string {
+	return path + "\x00" + name
+}
+
+type xattrValue struct {
+	value []byte
+	// ok is false when the file has no attribute of the name
+	ok bool
 }
 
 func NewCache() *Cache {
 	return &Cache{
 		indexAttrs: fsindex.NewFSIndex(),
 		readDirs:   haxmap.New[string, struct{}](),
+		xattrs:     haxmap.New[string, xattrValue](),
+		xattrNames: haxmap.New[string, []string](),
+		blocks:     make(map[blockKey][]byte),
 	}
 }
 
@@ -91,3 +119,97 @@ func (c *Cache) GetOrFetchDir(path string, fetch func() (*fspb.ReadDirResponse,
 	c.readDirs.Set(path, struct{}{})
 	return files, nil
 }
+
+// GetOrFetchXattr returns the extended attribute of the file, false when it has none of the name. The image never
+// changes, an attribute is fetched once.
+func (c *Cache) GetOrFetchXattr(path, name string, fetch func() (*fspb.GetXattrResponse, error)) ([]byte, bool, error) {
+	key := xattrKey(path, name)
+	if cached, ok := c.xattrs.Get(key); ok {
+		return cached.value, cached.ok, nil
+	}
+
+	resp, err := fetch()
+	if err != nil {
+		return nil, false, err
+	}
+
+	cached := xattrValue{value: resp.Value, ok: resp.Value != nil}
+	c.xattrs.Set(key, cached)
+	return cached.value, cached.ok, nil
+}
+
+// GetOrFetchXattrNames returns the names of the extended attributes of the file
+func (c *Cache) GetOrFetchXattrNames(path string, fetch func() (*fspb.ListXattrResponse, error)) ([]string, error) {
+	if names, ok := c.xattrNames.Get(path); ok {
+		return names, nil
+	}
+
+	resp, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	c.xattrNames.Set(path, resp.Names)
+	return resp.Names, nil
+}
+
+// StoreBlocks stores data read at offset, a multiple of blockSize, as prefetched blocks of the file
+func (c *Cache) StoreBlocks(path string, blockSize, offset int64, data []byte) {
+	c.blocksLock.Lock()
+	defer c.blocksLock.Unlock()
+
+	c.blockSize = blockSize
+	for index := offset / blockSize; len(data) > 0; index++ {
+		n := min(int64(len(data)), blockSize)
+		c.blocks[blockKey{path: path, index: index}] = data[:n]
+		data = data[n:]
+	}
+}
+
+// ReadBlocks reads size bytes at offset from the prefetched blocks of the file, it reports false when they do not
+// hold the whole range. The page cache keeps what is read, a block is dropped once its end is read.
+func (c *Cache) ReadBlocks(path string, offset int64, size int) ([]byte, bool) {
+	c.blocksLock.Lock()
+	defer c.blocksLock.Unlock()
+
+	if c.blockSize == 0 || len(c.blocks) == 0 {
+		return nil, false
+	}
+
+	var (
+		data = make([]byte, 0, size)
+		read []blockKey
+	)
+	for position := offset; len(data) < size; {
+		key := blockKey{path: path, index: position / c.blockSize}
+		block, ok := c.blocks[key]
+		if !ok {
+			return nil, false
+		}
+
+		start := position - key.index*c.blockSize
+		if start >= int64(len(block)) {
+			// past the end of the file
+			break
+		}
+
+		chunk := block[start:min(int64(len(block)), start+int64(size-len(data)))]
+		data = append(data, chunk...)
+		position += int64(len(chunk))
+
+		if start+int64(len(chunk)) == int64(len(block)) {
+			read = append(read, key)
+		}
+		if int64(len(block)) < c.blockSize {
+			// the last block of the file
+			break
+		}
+	}
+
+	for _, key := range read {
+		delete(c.blocks, key)
+	}
+
+	slog.Debug("cache: hit on prefetched blocks", "path", path, "offset", offset, "size", len(data))
+	return data, true
+}
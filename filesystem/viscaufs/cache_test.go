@@ -0,0 +1,67 @@
+package viscaufs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadBlocksAcrossAlignedBlocks(t *testing.T) {
+	cache := NewCache()
+	cache.StoreBlocks("/file", 4, 8, []byte("abcdefghij"))
+
+	// the data stored at offset 8 fills the blocks 2 and 3, the read starts inside the block 2
+	data, ok := cache.ReadBlocks("/file", 9, 4)
+	require.True(t, ok)
+	assert.Equal(t, "bcde", string(data))
+
+	// the block 2 was read to its end and is dropped, the block 3 is kept
+	_, ok = cache.ReadBlocks("/file", 8, 4)
+	assert.False(t, ok)
+	data, ok = cache.ReadBlocks("/file", 13, 3)
+	require.True(t, ok)
+	assert.Equal(t, "fgh", string(data))
+}
+
+func TestReadBlocksMisses(t *testing.T) {
+	cache := NewCache()
+	_, ok := cache.ReadBlocks("/file", 0, 4)
+	assert.False(t, ok, "nothing is stored")
+
+	cache.StoreBlocks("/file", 4, 4, []byte("abcd"))
+
+	_, ok = cache.ReadBlocks("/other", 4, 4)
+	assert.False(t, ok, "the blocks belong to another file")
+	_, ok = cache.ReadBlocks("/file", 0, 4)
+	assert.False(t, ok, "the block before is not stored")
+	_, ok = cache.ReadBlocks("/file", 6, 4)
+	assert.False(t, ok, "the range goes past the stored block")
+
+	// a miss drops nothing
+	data, ok := cache.ReadBlocks("/file", 4, 4)
+	require.True(t, ok)
+	assert.Equal(t, "abcd", string(data))
+}
+
+func TestReadBlocksStopsAtThePartialBlock(t *testing.T) {
+	cache := NewCache()
+	cache.StoreBlocks("/file", 4, 0, []byte("abcdef"))
+
+	// the last block of the file is shorter than the block size, the read is short
+	data, ok := cache.ReadBlocks("/file", 2, 16)
+	require.True(t, ok)
+	assert.Equal(t, "cdef", string(data))
+
+	_, ok = cache.ReadBlocks("/file", 4, 2)
+	assert.False(t, ok, "the partial block was read to its end")
+}
+
+func TestReadBlocksPastTheEndOfTheFile(t *testing.T) {
+	cache := NewCache()
+	cache.StoreBlocks("/file", 4, 0, []byte("abcdef"))
+
+	data, ok := cache.ReadBlocks("/file", 6, 4)
+	require.True(t, ok)
+	assert.Empty(t, data)
+}
@@ -7,11 +7,15 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"syscall"
 
+	"github.com/baepo-cloud/viscaufs/common/loadbalancer"
 	fspb "github.com/baepo-cloud/viscaufs/common/proto/gen/v1"
 	"github.com/hanwen/go-fuse/v2/fs"
 	"github.com/hanwen/go-fuse/v2/fuse"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // FS represents our FUSE filesystem
@@ -20,6 +24,13 @@ type FS struct {
 	ImageDigest string
 	MountPath   string
 	Cache       *Cache
+
+	sessionMutex sync.Mutex
+	sessionID    string
+	// sessionReady is closed once the session is established, it is replaced while a new session is opened
+	sessionReady chan struct{}
+	// sessionStale is closed when the servers no longer know the session
+	sessionStale chan struct{}
 }
 
 // Node directly implements FS interfaces
@@ -32,6 +43,8 @@ type Node struct {
 
 type FileHandle struct {
 	Uid string
+
+	readAhead *readAhead
 }
 
 // Ensure interfaces are implemented
@@ -93,6 +106,8 @@ func (n *Node) Lookup(ctx context.Context, name string, out *fuse.EntryOut) (*fs
 		SymlinkTarget: file.SymlinkTarget,
 	}
 
+	// the hardlinks of a file share its inode number, the inode already looked up under another name is returned
+	// so the kernel sees a single file with its link count
 	childInode := n.NewPersistentInode(ctx, child, fs.StableAttr{
 		Mode: file.Attributes.Mode,
 		Ino:  file.Attributes.Inode,
@@ -161,8 +176,6 @@ func (n *Node) Readlink(_ context.Context) ([]byte, syscall.Errno) {
 		return nil, syscall.EINVAL
 	}
 
-	fmt.Sprintf("ASKING CACHE FOR SYMLINK: %s", n.Path)
-
 	if n.SymlinkTarget == nil {
 		return nil, syscall.ENOENT
 	}
@@ -170,35 +183,77 @@ func (n *Node) Readlink(_ context.Context) ([]byte, syscall.Errno) {
 	return []byte(filepath.Join(n.FS.MountPath, *n.SymlinkTarget)), 0
 }
 
+// Open opens a handle of the file in the session of the client, the server rejects the opens without a session so
+// the opens issued while the mount starts wait for it. A server not knowing the session, like after the servers
+// changed, has the session opened again on it.
 func (n *Node) Open(ctx context.Context, flags uint32) (fh fs.FileHandle, fuseFlags uint32, errno syscall.Errno) {
-	resp, err := n.FS.Client.Open(ctx, &fspb.OpenRequest{
-		Path:        n.Path,
-		Flags:       flags,
-		ImageDigest: n.FS.ImageDigest,
-	})
+	var (
+		resp *fspb.OpenResponse
+		err  error
+	)
+	for reopened := false; ; reopened = true {
+		var sessionID string
+		if sessionID, err = n.FS.waitSession(ctx); err != nil {
+			slog.Error("open: no session", "path", n.Path, "err", err)
+			return nil, 0, syscall.EINTR
+		}
+
+		resp, err = n.FS.Client.Open(ctx, &fspb.OpenRequest{
+			Path:        n.Path,
+			Flags:       flags,
+			ImageDigest: n.FS.ImageDigest,
+			SessionId:   sessionID,
+		})
+		if status.Code(err) != codes.FailedPrecondition || reopened {
+			break
+		}
+		n.FS.reopenSession(sessionID)
+	}
 
 	if err != nil {
 		slog.Error("open: error", "path", n.Path, "err", err)
-		return nil, 0, syscall.EIO
+		switch status.Code(err) {
+		case codes.NotFound:
+			return nil, 0, syscall.ENOENT
+		case codes.ResourceExhausted:
+			return nil, 0, syscall.ENFILE
+		default:
+			return nil, 0, syscall.EIO
+		}
 	}
 
 	handle := &FileHandle{
-		Uid: resp.Uid,
+		Uid:       resp.Uid,
+		readAhead: newReadAhead(n.FS, n.Path),
 	}
 
 	return handle, fuse.FOPEN_KEEP_CACHE, 0
 }
 
+// Read reads the file by path without its handle, so any server having the image can serve it. The sequential
+// reads are served from a window streamed ahead of them.
 func (n *Node) Read(ctx context.Context, f fs.FileHandle, dest []byte, off int64) (fuse.ReadResult, syscall.Errno) {
 	fh, ok := f.(*FileHandle)
 	if !ok {
 		return nil, syscall.EINVAL
 	}
 
-	resp, err := n.FS.Client.Read(ctx, &fspb.ReadRequest{
-		Uid:    fh.Uid,
-		Offset: off,
-		Size:   uint32(len(dest)),
+	if data, ok := n.FS.Cache.ReadBlocks(n.Path, off, len(dest)); ok {
+		return fuse.ReadResultData(data), 0
+	}
+
+	if data, ok := fh.readAhead.Read(ctx, off, len(dest)); ok {
+		return fuse.ReadResultData(data), 0
+	}
+	if ctx.Err() != nil {
+		return nil, syscall.EINTR
+	}
+
+	resp, err := n.FS.Client.ReadAt(ctx, &fspb.ReadAtRequest{
+		ImageDigest: n.FS.ImageDigest,
+		Path:        n.Path,
+		Offset:      off,
+		Size:        uint32(len(dest)),
 	})
 
 	if err != nil {
@@ -215,7 +270,9 @@ func (n *Node) Release(ctx context.Context, f fs.FileHandle) syscall.Errno {
 		return syscall.EINVAL
 	}
 
-	_, err := n.FS.Client.Release(ctx, &fspb.ReleaseRequest{
+	fh.readAhead.Close()
+
+	_, err := n.FS.Client.Release(loadbalancer.WithImageDigest(ctx, n.FS.ImageDigest), &fspb.ReleaseRequest{
 		Uid: fh.Uid,
 	})
 
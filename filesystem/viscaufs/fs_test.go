@@ -0,0 +1,175 @@
+package viscaufs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	fspb "github.com/baepo-cloud/viscaufs/common/proto/gen/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// openClient records the sessions the handles are opened in
+type openClient struct {
+	fspb.FuseServiceClient
+	opens chan *fspb.OpenRequest
+}
+
+func (c *openClient) Open(_ context.Context, in *fspb.OpenRequest, _ ...grpc.CallOption) (*fspb.OpenResponse, error) {
+	c.opens <- in
+	return &fspb.OpenResponse{Uid: "uid"}, nil
+}
+
+func TestOpenWaitsForTheSession(t *testing.T) {
+	client := &openClient{opens: make(chan *fspb.OpenRequest, 1)}
+	node := &Node{FS: &FS{Client: client, ImageDigest: "sha256:image"}, Path: "/file"}
+
+	opened := make(chan *FileHandle)
+	go func() {
+		fh, _, errno := node.Open(context.Background(), 0)
+		assert.Zero(t, errno)
+		opened <- fh.(*FileHandle)
+	}()
+
+	select {
+	case <-client.opens:
+		t.Fatal("the handle was opened without a session")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	node.FS.setSessionID("session")
+	select {
+	case request := <-client.opens:
+		assert.Equal(t, "session", request.SessionId)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the open was not woken up by the session")
+	}
+	assert.Equal(t, "uid", (<-opened).Uid)
+
+	// the later sessions do not block the opens
+	node.FS.setSessionID("resumed")
+	_, _, errno := node.Open(context.Background(), 0)
+	require.Zero(t, errno)
+	assert.Equal(t, "resumed", (<-client.opens).SessionId)
+}
+
+func TestOpenIsInterruptedWithoutSession(t *testing.T) {
+	node := &Node{FS: &FS{Client: &openClient{}, ImageDigest: "sha256:image"}, Path: "/file"}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, _, errno := node.Open(ctx, 0)
+	assert.Equal(t, syscall.EINTR, errno)
+}
+
+// sessionClient opens the sessions of a fake server, the opens in a session it does not know fail like on a server
+// that never received the session
+type sessionClient struct {
+	fspb.FuseServiceClient
+
+	mutex   sync.Mutex
+	known   map[string]bool
+	streams []*sessionStream
+	opens   []string
+}
+
+func (c *sessionClient) Session(ctx context.Context, _ ...grpc.CallOption) (fspb.FuseService_SessionClient, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	sessionID := fmt.Sprintf("session-%d", len(c.streams)+1)
+	c.known[sessionID] = true
+	stream := &sessionStream{ctx: ctx, sessionID: sessionID, closed: make(chan struct{})}
+	c.streams = append(c.streams, stream)
+	return stream, nil
+}
+
+func (c *sessionClient) Open(_ context.Context, in *fspb.OpenRequest, _ ...grpc.CallOption) (*fspb.OpenResponse, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.opens = append(c.opens, in.SessionId)
+	if !c.known[in.SessionId] {
+		return nil, status.Error(codes.FailedPrecondition, "session not found")
+	}
+	return &fspb.OpenResponse{Uid: "uid"}, nil
+}
+
+func (c *sessionClient) forget(sessionID string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.known, sessionID)
+}
+
+type sessionStream struct {
+	grpc.ClientStream
+	ctx       context.Context
+	sessionID string
+	answered  bool
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func (s *sessionStream) Send(*fspb.SessionRequest) error { return nil }
+
+func (s *sessionStream) Recv() (*fspb.SessionResponse, error) {
+	if !s.answered {
+		s.answered = true
+		return &fspb.SessionResponse{SessionId: s.sessionID, HeartbeatIntervalMs: 60_000}, nil
+	}
+	select {
+	case <-s.closed:
+		return nil, io.EOF
+	case <-s.ctx.Done():
+		return nil, s.ctx.Err()
+	}
+}
+
+func (s *sessionStream) CloseSend() error {
+	s.closeOnce.Do(func() { close(s.closed) })
+	return nil
+}
+
+func TestOpenReopensTheSessionUnknownToTheServer(t *testing.T) {
+	client := &sessionClient{known: make(map[string]bool)}
+	node := &Node{FS: &FS{Client: client, ImageDigest: "sha256:image"}, Path: "/file"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		node.FS.KeepSession(ctx)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	_, _, errno := node.Open(context.Background(), 0)
+	require.Zero(t, errno)
+
+	// the image moved to a server that never received the session
+	client.forget("session-1")
+	_, _, errno = node.Open(context.Background(), 0)
+	require.Zero(t, errno)
+
+	assert.Equal(t, "session-2", node.FS.SessionID())
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	assert.Equal(t, []string{"session-1", "session-1", "session-2"}, client.opens)
+	require.Len(t, client.streams, 2)
+	select {
+	case <-client.streams[0].closed:
+	default:
+		t.Fatal("the unknown session was not ended")
+	}
+}
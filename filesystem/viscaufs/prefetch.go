@@ -0,0 +1,112 @@
+package viscaufs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"sync"
+	"syscall"
+
+	fspb "github.com/baepo-cloud/viscaufs/common/proto/gen/v1"
+)
+
+// prefetchReadSize is the size of the reads fetching the ranges of the profile
+const prefetchReadSize = 1 << 20
+
+// Prefetch warms the caches with the files the previous runs of the image accessed during their startup: their
+// attributes, those of their parent directories and the ranges they read. The files are fetched by workers in
+// the order of the profile so the first accessed are cached first.
+func (f *FS) Prefetch(ctx context.Context, workers int) error {
+	profile, err := f.Client.GetPrefetchProfile(ctx, &fspb.GetPrefetchProfileRequest{ImageDigest: f.ImageDigest})
+	if err != nil {
+		return fmt.Errorf("failed to get prefetch profile: %w", err)
+	}
+
+	if len(profile.Files) == 0 || profile.BlockSize <= 0 {
+		return nil
+	}
+
+	files := make(chan *fspb.PrefetchFile)
+	var wg sync.WaitGroup
+	for range max(workers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for file := range files {
+				if err := f.prefetchFile(ctx, file, profile.BlockSize); err != nil {
+					slog.Debug("prefetch: failed to fetch file", "path", file.Path, "err", err)
+				}
+			}
+		}()
+	}
+
+	defer func() {
+		close(files)
+		wg.Wait()
+	}()
+
+	for _, file := range profile.Files {
+		select {
+		case files <- file:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+func (f *FS) prefetchFile(ctx context.Context, file *fspb.PrefetchFile, blockSize int64) error {
+	// the kernel looks every parent up before the file
+	for dir := filepath.Dir(file.Path); dir != "/"; dir = filepath.Dir(dir) {
+		if _, err := f.prefetchAttr(ctx, dir); err != nil {
+			return err
+		}
+	}
+
+	attr, err := f.prefetchAttr(ctx, file.Path)
+	if err != nil {
+		return err
+	}
+	if len(file.Ranges) == 0 || attr.Attributes.Mode&syscall.S_IFMT != syscall.S_IFREG {
+		return nil
+	}
+
+	readSize := blockSize * max(1, prefetchReadSize/blockSize)
+	for _, r := range file.Ranges {
+		// the ranges are widened to whole blocks, a block shorter than the block size is the end of the file
+		start := r.Offset / blockSize * blockSize
+		end := (r.Offset + r.Length + blockSize - 1) / blockSize * blockSize
+		for offset := start; offset < end; offset += readSize {
+			size := min(readSize, end-offset)
+			data, err := f.Client.ReadAt(ctx, &fspb.ReadAtRequest{
+				ImageDigest: f.ImageDigest,
+				Path:        file.Path,
+				Offset:      offset,
+				Size:        uint32(size),
+				Prefetch:    true,
+			})
+			if err != nil {
+				return err
+			}
+
+			f.Cache.StoreBlocks(file.Path, blockSize, offset, data.Data)
+			if int64(len(data.Data)) < size {
+				// the end of the file
+				return nil
+			}
+		}
+	}
+
+	return nil
+}
+
+func (f *FS) prefetchAttr(ctx context.Context, path string) (*fspb.File, error) {
+	return f.Cache.GetOrFetchAttr(path, func() (*fspb.GetAttrResponse, error) {
+		return f.Client.GetAttr(ctx, &fspb.GetAttrRequest{
+			Path:        path,
+			ImageDigest: f.ImageDigest,
+		})
+	})
+}
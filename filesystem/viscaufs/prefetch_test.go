@@ -0,0 +1,124 @@
+package viscaufs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"syscall"
+	"testing"
+
+	fspb "github.com/baepo-cloud/viscaufs/common/proto/gen/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// profileClient serves a prefetch profile and the files of an image
+type profileClient struct {
+	fspb.FuseServiceClient
+	profile  *fspb.GetPrefetchProfileResponse
+	contents map[string][]byte
+	dirs     map[string]bool
+
+	mutex sync.Mutex
+	reads []*fspb.ReadAtRequest
+}
+
+func (c *profileClient) GetPrefetchProfile(context.Context, *fspb.GetPrefetchProfileRequest, ...grpc.CallOption) (*fspb.GetPrefetchProfileResponse, error) {
+	return c.profile, nil
+}
+
+func (c *profileClient) GetAttr(_ context.Context, in *fspb.GetAttrRequest, _ ...grpc.CallOption) (*fspb.GetAttrResponse, error) {
+	if c.dirs[in.Path] {
+		return &fspb.GetAttrResponse{File: &fspb.File{Path: in.Path, Attributes: &fspb.FileAttributes{Mode: syscall.S_IFDIR | 0755}}}, nil
+	}
+	content, ok := c.contents[in.Path]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "file not found")
+	}
+	return &fspb.GetAttrResponse{File: &fspb.File{Path: in.Path, Attributes: &fspb.FileAttributes{
+		Mode: syscall.S_IFREG | 0644,
+		Size: int64(len(content)),
+	}}}, nil
+}
+
+func (c *profileClient) ReadAt(_ context.Context, in *fspb.ReadAtRequest, _ ...grpc.CallOption) (*fspb.ReadAtResponse, error) {
+	c.mutex.Lock()
+	c.reads = append(c.reads, in)
+	c.mutex.Unlock()
+
+	content := c.contents[in.Path]
+	start := min(in.Offset, int64(len(content)))
+	end := min(in.Offset+int64(in.Size), int64(len(content)))
+	return &fspb.ReadAtResponse{Data: content[start:end]}, nil
+}
+
+func TestPrefetchWarmsTheCaches(t *testing.T) {
+	app := newFakeClient(300).content
+	client := &profileClient{
+		profile: &fspb.GetPrefetchProfileResponse{
+			BlockSize: 128,
+			Files: []*fspb.PrefetchFile{
+				{Path: "/usr/bin/app", Ranges: []*fspb.PrefetchRange{{Offset: 0, Length: 512}}},
+				{Path: "/usr/lib", Ranges: []*fspb.PrefetchRange{{Offset: 0, Length: 128}}},
+			},
+		},
+		contents: map[string][]byte{"/usr/bin/app": app},
+		dirs:     map[string]bool{"/usr": true, "/usr/bin": true, "/usr/lib": true},
+	}
+	fs := &FS{Client: client, ImageDigest: "sha256:image", Cache: NewCache()}
+
+	require.NoError(t, fs.Prefetch(context.Background(), 2))
+
+	// the ranges are read once, the directories are not read
+	require.Len(t, client.reads, 1)
+	assert.Equal(t, "/usr/bin/app", client.reads[0].Path)
+	assert.True(t, client.reads[0].Prefetch, "the prefetch is not recorded in the statistics")
+
+	// the file and its parents are cached
+	for _, path := range []string{"/usr", "/usr/bin", "/usr/bin/app"} {
+		_, err := fs.Cache.GetOrFetchAttr(path, func() (*fspb.GetAttrResponse, error) {
+			return nil, errors.New("not prefetched")
+		})
+		assert.NoError(t, err, path)
+	}
+
+	// the reads of the file are served by the blocks, the last one is partial
+	data, ok := fs.Cache.ReadBlocks("/usr/bin/app", 0, 256)
+	require.True(t, ok)
+	assert.Equal(t, app[:256], data)
+	data, ok = fs.Cache.ReadBlocks("/usr/bin/app", 256, 256)
+	require.True(t, ok)
+	assert.Equal(t, app[256:], data)
+}
+
+func TestPrefetchReadsAlignedToTheBlocks(t *testing.T) {
+	content := newFakeClient(3*prefetchReadSize + 100).content
+	client := &profileClient{
+		profile: &fspb.GetPrefetchProfileResponse{
+			BlockSize: 128 << 10,
+			Files: []*fspb.PrefetchFile{
+				{Path: "/data", Ranges: []*fspb.PrefetchRange{{Offset: 128<<10 + 100, Length: 2*prefetchReadSize - 99}}},
+			},
+		},
+		contents: map[string][]byte{"/data": content},
+	}
+	fs := &FS{Client: client, ImageDigest: "sha256:image", Cache: NewCache()}
+
+	require.NoError(t, fs.Prefetch(context.Background(), 1))
+
+	// the range is widened to whole blocks and split in reads of whole blocks
+	require.Len(t, client.reads, 3)
+	for i, read := range client.reads {
+		assert.Equal(t, int64(128<<10+i*prefetchReadSize), read.Offset)
+	}
+	assert.Equal(t, uint32(128<<10), client.reads[2].Size, "the range ends inside the block")
+
+	// the last block of the range is whole, a read through it is not cut short as the end of the file
+	end := int64(128<<10 + 2*prefetchReadSize)
+	data, ok := fs.Cache.ReadBlocks("/data", end+64<<10, 64<<10)
+	require.True(t, ok)
+	assert.Equal(t, content[end+64<<10:end+128<<10], data)
+}
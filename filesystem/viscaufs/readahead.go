@@ -0,0 +1,269 @@
+package viscaufs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/baepo-cloud/viscaufs/common/loadbalancer"
+	fspb "github.com/baepo-cloud/viscaufs/common/proto/gen/v1"
+)
+
+const (
+	// readAheadWindow is the number of bytes a stream fetches ahead of the reads
+	readAheadWindow = 4 << 20
+	// readAheadSequentialReads is the number of consecutive reads after which the file is read ahead
+	readAheadSequentialReads = 2
+	// recordReadTimeout bounds the report of the bytes read from the window
+	recordReadTimeout = 10 * time.Second
+)
+
+// readAhead detects the sequential reads of a file handle and serves them from a window filled by a ReadStream, the
+// next window is streamed once half of the current one is consumed
+type readAhead struct {
+	fs   *FS
+	path string
+
+	mutex sync.Mutex
+	// changed is closed, and replaced, when the window or the stream changes, the reads waiting for bytes wait on it
+	changed chan struct{}
+	// next is the offset following the last read, a read at another offset breaks the sequence
+	next       int64
+	sequential int
+	// data holds the bytes streamed from start, those before the last read are dropped
+	start int64
+	data  []byte
+	// generation identifies the current stream, the chunks of a cancelled stream are discarded
+	generation uint64
+	streaming  bool
+	eof        bool
+	err        error
+	cancel     context.CancelFunc
+	// recordStart and recordEnd are the bytes served from the window and not reported yet, the window is streamed as
+	// prefetch so the server only records the bytes the reads consumed
+	recordStart int64
+	recordEnd   int64
+}
+
+func newReadAhead(fs *FS, path string) *readAhead {
+	return &readAhead{fs: fs, path: path, changed: make(chan struct{})}
+}
+
+// Read returns the bytes at off from the read-ahead window, false when the reads are not sequential yet, the stream
+// failed or ctx is done while waiting for it, and the caller must read the file itself
+func (r *readAhead) Read(ctx context.Context, off int64, size int) ([]byte, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if off != r.next {
+		r.reset(off)
+	}
+	r.next = off + int64(size)
+	r.sequential++
+
+	if r.sequential < readAheadSequentialReads {
+		return nil, false
+	}
+
+	if off < r.start || off > r.end() || r.err != nil {
+		r.reset(off)
+		r.sequential = readAheadSequentialReads
+	}
+	if !r.streaming && !r.eof && r.end()-off < readAheadWindow/2 {
+		r.stream()
+	}
+
+	// a stream ending short of the read, without reaching the end of the file, is followed by the next one
+	generation := r.generation
+	for r.end() < off+int64(size) && !r.eof && r.err == nil && generation == r.generation {
+		if !r.streaming {
+			r.stream()
+		}
+		if !r.wait(ctx) {
+			// the read is interrupted, its retry continues the sequence
+			if generation == r.generation && r.next == off+int64(size) {
+				r.next = off
+			}
+			return nil, false
+		}
+	}
+	// a concurrent read of the handle may have reset the window or consumed it past off while this one waited
+	if r.err != nil || generation != r.generation || off < r.start {
+		return nil, false
+	}
+
+	// drop the bytes before the read, the sequence never goes back
+	r.data = r.data[off-r.start:]
+	r.start = off
+
+	data := make([]byte, min(int64(size), int64(len(r.data))))
+	copy(data, r.data)
+	r.consume(off, int64(len(data)))
+
+	// the window is refilled before it runs out
+	if !r.streaming && !r.eof && r.end()-r.next < readAheadWindow/2 {
+		r.stream()
+	}
+
+	return data, true
+}
+
+// Close stops the stream of the window
+func (r *readAhead) Close() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.reset(r.next)
+}
+
+// wait waits for a change of the window until ctx is done, the caller holds the mutex
+func (r *readAhead) wait(ctx context.Context) bool {
+	changed := r.changed
+	r.mutex.Unlock()
+	defer r.mutex.Lock()
+
+	select {
+	case <-changed:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// broadcast wakes up the waiting reads, the caller holds the mutex
+func (r *readAhead) broadcast() {
+	close(r.changed)
+	r.changed = make(chan struct{})
+}
+
+func (r *readAhead) end() int64 {
+	return r.start + int64(len(r.data))
+}
+
+// reset cancels the stream and drops the window, starting it back at offset, the caller holds the mutex
+func (r *readAhead) reset(offset int64) {
+	r.report()
+	if r.cancel != nil {
+		r.cancel()
+		r.cancel = nil
+	}
+
+	r.generation++
+	r.sequential = 0
+	r.start = offset
+	r.data = nil
+	r.streaming = false
+	r.eof = false
+	r.err = nil
+	r.broadcast()
+}
+
+// consume adds the bytes served from the window to those to report, the caller holds the mutex
+func (r *readAhead) consume(off, length int64) {
+	if off != r.recordEnd {
+		r.report()
+		r.recordStart, r.recordEnd = off, off
+	}
+	r.recordEnd += length
+}
+
+// report records the bytes served from the window on the server in the background, the caller holds the mutex
+func (r *readAhead) report() {
+	if r.recordEnd <= r.recordStart {
+		return
+	}
+
+	offset, length := r.recordStart, r.recordEnd-r.recordStart
+	r.recordStart = r.recordEnd
+	go func() {
+		ctx, cancel := context.WithTimeout(loadbalancer.WithImageDigest(context.Background(), r.fs.ImageDigest), recordReadTimeout)
+		defer cancel()
+
+		_, err := r.fs.Client.RecordRead(ctx, &fspb.RecordReadRequest{
+			ImageDigest: r.fs.ImageDigest,
+			Path:        r.path,
+			Offset:      offset,
+			Length:      length,
+		})
+		if err != nil {
+			slog.Warn("read ahead: failed to record read", "path", r.path, "offset", offset, "length", length, "err", err)
+		}
+	}()
+}
+
+// stream starts streaming a window following the bytes already read ahead, the bytes read from the previous ones are
+// reported, the caller holds the mutex
+func (r *readAhead) stream() {
+	r.report()
+	ctx, cancel := context.WithCancel(loadbalancer.WithImageDigest(context.Background(), r.fs.ImageDigest))
+	r.cancel = cancel
+	r.streaming = true
+
+	go r.receive(ctx, r.generation, r.end())
+}
+
+func (r *readAhead) receive(ctx context.Context, generation uint64, offset int64) {
+	received, err := r.receiveWindow(ctx, generation, offset)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if generation != r.generation {
+		return
+	}
+
+	r.cancel()
+	r.cancel = nil
+	r.streaming = false
+	if err != nil {
+		slog.Error("read ahead: error", "path", r.path, "offset", offset, "err", err)
+		r.err = err
+	} else if received < readAheadWindow {
+		r.eof = true
+	}
+	r.broadcast()
+}
+
+// receiveWindow appends the chunks of a window to the data, it returns the number of bytes received
+func (r *readAhead) receiveWindow(ctx context.Context, generation uint64, offset int64) (int64, error) {
+	stream, err := r.fs.Client.ReadStream(ctx, &fspb.ReadStreamRequest{
+		ImageDigest: r.fs.ImageDigest,
+		Path:        r.path,
+		Offset:      offset,
+		Length:      readAheadWindow,
+		// the window is read speculatively, the bytes the reads consume from it are reported with RecordRead
+		Prefetch: true,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var received int64
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return received, nil
+		}
+		if err != nil {
+			return received, err
+		}
+
+		r.mutex.Lock()
+		if generation != r.generation {
+			r.mutex.Unlock()
+			return received, nil
+		}
+		if chunk.Offset != r.end() {
+			r.mutex.Unlock()
+			return received, errors.New("read ahead: chunk is not consecutive")
+		}
+		r.data = append(r.data, chunk.Data...)
+		r.broadcast()
+		r.mutex.Unlock()
+
+		received += int64(len(chunk.Data))
+	}
+}
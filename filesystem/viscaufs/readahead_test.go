@@ -0,0 +1,303 @@
+package viscaufs
+
+import (
+	"cmp"
+	"context"
+	"io"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	fspb "github.com/baepo-cloud/viscaufs/common/proto/gen/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// streamChunkSize is the size of the chunks the fake server streams
+const streamChunkSize = 64 << 10
+
+// fakeClient serves the reads of a single file, the server methods the tests do not use are left nil
+type fakeClient struct {
+	fspb.FuseServiceClient
+	content []byte
+
+	mutex   sync.Mutex
+	streams []*fspb.ReadStreamRequest
+	records []*fspb.RecordReadRequest
+	// gate, when set, holds the streams until it is closed
+	gate chan struct{}
+}
+
+func newFakeClient(size int) *fakeClient {
+	content := make([]byte, size)
+	r := rand.New(rand.NewPCG(uint64(size), 0))
+	for i := range content {
+		content[i] = byte(r.Uint32())
+	}
+	return &fakeClient{content: content}
+}
+
+func (c *fakeClient) ReadStream(ctx context.Context, in *fspb.ReadStreamRequest, _ ...grpc.CallOption) (fspb.FuseService_ReadStreamClient, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.streams = append(c.streams, in)
+	return &fakeStream{ctx: ctx, client: c, gate: c.gate, offset: in.Offset, end: min(in.Offset+in.Length, int64(len(c.content)))}, nil
+}
+
+func (c *fakeClient) RecordRead(_ context.Context, in *fspb.RecordReadRequest, _ ...grpc.CallOption) (*fspb.RecordReadResponse, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.records = append(c.records, in)
+	return &fspb.RecordReadResponse{}, nil
+}
+
+// recordedRanges returns the ranges reported as read, sorted by offset since they are reported in the background
+func (c *fakeClient) recordedRanges() [][2]int64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	ranges := make([][2]int64, 0, len(c.records))
+	for _, record := range c.records {
+		ranges = append(ranges, [2]int64{record.Offset, record.Length})
+	}
+	slices.SortFunc(ranges, func(a, b [2]int64) int { return cmp.Compare(a[0], b[0]) })
+	return ranges
+}
+
+func (c *fakeClient) streamRequests() []*fspb.ReadStreamRequest {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return append([]*fspb.ReadStreamRequest(nil), c.streams...)
+}
+
+type fakeStream struct {
+	grpc.ClientStream
+	ctx    context.Context
+	client *fakeClient
+	gate   chan struct{}
+	offset int64
+	end    int64
+}
+
+// Recv returns the next chunk, the chunks held by the gate are delivered even once the stream is cancelled, like the
+// chunks a real stream received before its cancellation
+func (s *fakeStream) Recv() (*fspb.ReadStreamResponse, error) {
+	if s.gate != nil {
+		<-s.gate
+	} else if err := s.ctx.Err(); err != nil {
+		return nil, err
+	}
+	if s.offset >= s.end {
+		return nil, io.EOF
+	}
+
+	end := min(s.offset+streamChunkSize, s.end)
+	chunk := &fspb.ReadStreamResponse{Offset: s.offset, Data: s.client.content[s.offset:end]}
+	s.offset = end
+	return chunk, nil
+}
+
+func newTestReadAhead(client *fakeClient) *readAhead {
+	return newReadAhead(&FS{Client: client, ImageDigest: "sha256:image"}, "/file")
+}
+
+func TestReadAheadStartsOnSequentialReads(t *testing.T) {
+	client := newFakeClient(1 << 20)
+	r := newTestReadAhead(client)
+	defer r.Close()
+
+	_, ok := r.Read(context.Background(), 0, 4096)
+	assert.False(t, ok, "a single read is not sequential")
+	assert.Empty(t, client.streamRequests())
+
+	data, ok := r.Read(context.Background(), 4096, 4096)
+	require.True(t, ok)
+	assert.Equal(t, client.content[4096:8192], data)
+
+	requests := client.streamRequests()
+	require.Len(t, requests, 1)
+	assert.Equal(t, int64(4096), requests[0].Offset)
+	assert.Equal(t, int64(readAheadWindow), requests[0].Length)
+	assert.True(t, requests[0].Prefetch, "the window is not recorded in the statistics")
+}
+
+func TestReadAheadStopsOnRandomReads(t *testing.T) {
+	client := newFakeClient(1 << 20)
+	r := newTestReadAhead(client)
+	defer r.Close()
+
+	r.Read(context.Background(), 0, 4096)
+	_, ok := r.Read(context.Background(), 4096, 4096)
+	require.True(t, ok)
+
+	_, ok = r.Read(context.Background(), 512<<10, 4096)
+	assert.False(t, ok, "a read elsewhere breaks the sequence")
+	_, ok = r.Read(context.Background(), 16384, 4096)
+	assert.False(t, ok)
+
+	// the sequence starts over from the last read
+	data, ok := r.Read(context.Background(), 20480, 4096)
+	require.True(t, ok)
+	assert.Equal(t, client.content[20480:24576], data)
+	assert.Len(t, client.streamRequests(), 2)
+}
+
+func TestReadAheadRecordsTheConsumedBytes(t *testing.T) {
+	client := newFakeClient(1 << 20)
+	r := newTestReadAhead(client)
+
+	// the first read is not served by the window, the client reads and records it with ReadAt
+	r.Read(context.Background(), 0, 4096)
+	for off := int64(4096); off < 64<<10; off += 4096 {
+		_, ok := r.Read(context.Background(), off, 4096)
+		require.True(t, ok)
+	}
+
+	// the window is streamed as prefetch, only the bytes the reads consumed are recorded once the sequence ends
+	assert.Empty(t, client.recordedRanges())
+	r.Read(context.Background(), 512<<10, 4096)
+	for off := int64(516 << 10); off < 528<<10; off += 4096 {
+		_, ok := r.Read(context.Background(), off, 4096)
+		require.True(t, ok)
+	}
+	r.Close()
+
+	expected := [][2]int64{{4096, 60 << 10}, {516 << 10, 12 << 10}}
+	require.Eventually(t, func() bool { return len(client.recordedRanges()) == len(expected) }, 5*time.Second, time.Millisecond)
+	assert.Equal(t, expected, client.recordedRanges())
+}
+
+func TestReadAheadRefillsTheWindow(t *testing.T) {
+	size := 3*readAheadWindow + 12345
+	client := newFakeClient(size)
+	r := newTestReadAhead(client)
+	defer r.Close()
+
+	const readSize = 128 << 10
+	r.Read(context.Background(), 0, readSize)
+	for off := int64(readSize); off < int64(size); off += readSize {
+		data, ok := r.Read(context.Background(), off, readSize)
+		require.True(t, ok, "offset %d", off)
+		require.Equal(t, client.content[off:min(off+readSize, int64(size))], data, "offset %d", off)
+	}
+
+	// the windows follow each other, each one streamed once half of the previous one is read, no window is streamed
+	// past the one reaching the end of the file
+	requests := client.streamRequests()
+	require.Len(t, requests, 3)
+	assert.Equal(t, int64(readSize), requests[0].Offset)
+	for i := 1; i < len(requests); i++ {
+		assert.Equal(t, requests[i-1].Offset+readAheadWindow, requests[i].Offset)
+	}
+}
+
+func TestReadAheadWaitsForTheStream(t *testing.T) {
+	client := newFakeClient(1 << 20)
+	client.gate = make(chan struct{})
+	r := newTestReadAhead(client)
+	defer r.Close()
+
+	r.Read(context.Background(), 0, 4096)
+
+	done := make(chan []byte)
+	go func() {
+		data, _ := r.Read(context.Background(), 4096, 4096)
+		done <- data
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("the read returned before the window was streamed")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(client.gate)
+	select {
+	case data := <-done:
+		assert.Equal(t, client.content[4096:8192], data)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the read was not woken up by the stream")
+	}
+}
+
+func TestReadAheadHonorsTheContext(t *testing.T) {
+	client := newFakeClient(1 << 20)
+	client.gate = make(chan struct{})
+	r := newTestReadAhead(client)
+	defer r.Close()
+
+	r.Read(context.Background(), 0, 4096)
+
+	// the stream stalls, the interrupted read gives up waiting for it
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan bool)
+	go func() {
+		_, ok := r.Read(ctx, 4096, 4096)
+		done <- ok
+	}()
+	cancel()
+
+	select {
+	case ok := <-done:
+		assert.False(t, ok)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the interrupted read kept waiting for the stream")
+	}
+
+	// the window is kept for the retry of the interrupted read
+	close(client.gate)
+	data, ok := r.Read(context.Background(), 4096, 4096)
+	require.True(t, ok)
+	assert.Equal(t, client.content[4096:8192], data)
+	assert.Len(t, client.streamRequests(), 1)
+}
+
+func TestReadAheadDiscardsTheResetStreams(t *testing.T) {
+	client := newFakeClient(1 << 20)
+	client.gate = make(chan struct{})
+	r := newTestReadAhead(client)
+
+	r.Read(context.Background(), 0, 4096)
+
+	type result struct {
+		data []byte
+		ok   bool
+	}
+	done := make(chan result)
+	go func() {
+		data, ok := r.Read(context.Background(), 4096, 4096)
+		done <- result{data, ok}
+	}()
+
+	require.Eventually(t, func() bool { return len(client.streamRequests()) == 1 }, 5*time.Second, time.Millisecond)
+
+	// a concurrent read elsewhere resets the window the first read waits for
+	_, ok := r.Read(context.Background(), 512<<10, 4096)
+	assert.False(t, ok)
+
+	select {
+	case res := <-done:
+		assert.False(t, res.ok, "the waiting read falls back to reading the file")
+		assert.Nil(t, res.data)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the waiting read was not woken up by the reset")
+	}
+
+	// the chunks and the failure of the cancelled stream do not reach the new window
+	close(client.gate)
+	time.Sleep(50 * time.Millisecond)
+
+	r.mutex.Lock()
+	assert.Empty(t, r.data)
+	assert.Equal(t, int64(512<<10), r.start)
+	assert.NoError(t, r.err)
+	r.mutex.Unlock()
+
+	r.Close()
+}
@@ -0,0 +1,190 @@
+package viscaufs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/baepo-cloud/viscaufs/common/loadbalancer"
+	fspb "github.com/baepo-cloud/viscaufs/common/proto/gen/v1"
+)
+
+const (
+	// sessionRetryDelay is the delay before resuming a broken session
+	sessionRetryDelay = time.Second
+	// defaultHeartbeatInterval is used when the server does not give one
+	defaultHeartbeatInterval = 10 * time.Second
+)
+
+// errSessionStale ends a session the servers no longer know, the session loop opens a new one right away
+var errSessionStale = errors.New("session unknown to the server")
+
+// SessionID returns the id of the session the handles are opened in, empty until the session is established
+func (f *FS) SessionID() string {
+	f.sessionMutex.Lock()
+	defer f.sessionMutex.Unlock()
+
+	return f.sessionID
+}
+
+// sessionChannels returns the channel closed once the session is established and the one closed when it is stale
+func (f *FS) sessionChannels() (ready, stale chan struct{}) {
+	f.sessionMutex.Lock()
+	defer f.sessionMutex.Unlock()
+
+	f.initSessionLocked()
+	return f.sessionReady, f.sessionStale
+}
+
+func (f *FS) initSessionLocked() {
+	if f.sessionReady == nil {
+		f.sessionReady = make(chan struct{})
+		f.sessionStale = make(chan struct{})
+	}
+}
+
+// setSessionID stores the id of the established session and wakes up the opens waiting for it, it is only called by
+// the session loop
+func (f *FS) setSessionID(sessionID string) {
+	f.sessionMutex.Lock()
+	defer f.sessionMutex.Unlock()
+
+	f.initSessionLocked()
+	f.sessionID = sessionID
+	select {
+	case <-f.sessionReady:
+	default:
+		close(f.sessionReady)
+	}
+}
+
+// waitSession returns the id of the session, once it is established or until ctx is done
+func (f *FS) waitSession(ctx context.Context) (string, error) {
+	ready, _ := f.sessionChannels()
+	select {
+	case <-ready:
+		return f.SessionID(), nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// reopenSession ends the session the server serving the image does not know, like after the servers changed, and
+// holds the opens until the session loop opened a new one. A session already reopened is left alone.
+func (f *FS) reopenSession(sessionID string) {
+	f.sessionMutex.Lock()
+	defer f.sessionMutex.Unlock()
+
+	f.initSessionLocked()
+	select {
+	case <-f.sessionReady:
+	default:
+		return
+	}
+	if f.sessionID != sessionID {
+		return
+	}
+
+	f.sessionReady = make(chan struct{})
+	close(f.sessionStale)
+	f.sessionStale = make(chan struct{})
+}
+
+// KeepSession heartbeats the session of the client until ctx is done, then ends it so the server releases its
+// handles. A broken session is resumed, the server releases the handles of a session that expired meanwhile.
+func (f *FS) KeepSession(ctx context.Context) {
+	for {
+		err := f.runSession(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if errors.Is(err, errSessionStale) {
+			slog.Warn("session unknown to the server, opening a new one", "session_id", f.SessionID())
+			continue
+		}
+		slog.Warn("session interrupted", "session_id", f.SessionID(), "error", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(sessionRetryDelay):
+		}
+	}
+}
+
+func (f *FS) runSession(ctx context.Context) error {
+	// the stream outlives ctx to end the session cleanly
+	streamCtx, cancel := context.WithCancel(loadbalancer.WithImageDigest(context.Background(), f.ImageDigest))
+	defer cancel()
+
+	_, stale := f.sessionChannels()
+	stream, err := f.Client.Session(streamCtx)
+	if err != nil {
+		return fmt.Errorf("failed to open session stream: %w", err)
+	}
+
+	previous := f.SessionID()
+	if err := stream.Send(&fspb.SessionRequest{SessionId: previous, ImageDigest: f.ImageDigest}); err != nil {
+		return fmt.Errorf("failed to open session: %w", err)
+	}
+
+	resp, err := stream.Recv()
+	if err != nil {
+		return fmt.Errorf("failed to open session: %w", err)
+	}
+
+	if previous != "" && previous != resp.SessionId {
+		slog.Warn("session expired, its handles were released", "session_id", previous, "new_session_id", resp.SessionId)
+	}
+	f.setSessionID(resp.SessionId)
+
+	interval := time.Duration(resp.HeartbeatIntervalMs) * time.Millisecond
+	if interval <= 0 {
+		interval = defaultHeartbeatInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// the server only ends the stream when the session expired or on failure
+	ended := make(chan error, 1)
+	go func() {
+		_, err := stream.Recv()
+		ended <- err
+	}()
+
+	// end ends the session so the server releases its handles
+	end := func() error {
+		if err := stream.CloseSend(); err != nil {
+			return err
+		}
+		select {
+		case <-ended:
+		case <-time.After(time.Second):
+		}
+		return nil
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return end()
+		case <-stale:
+			if err := end(); err != nil {
+				return err
+			}
+			return errSessionStale
+		case err := <-ended:
+			if errors.Is(err, io.EOF) {
+				return errors.New("session ended by the server")
+			}
+			return err
+		case <-ticker.C:
+			if err := stream.Send(&fspb.SessionRequest{}); err != nil {
+				return fmt.Errorf("failed to send heartbeat: %w", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package viscaufs
+
+import (
+	"context"
+	"log/slog"
+	"syscall"
+
+	fspb "github.com/baepo-cloud/viscaufs/common/proto/gen/v1"
+	"github.com/hanwen/go-fuse/v2/fs"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	_ fs.NodeGetxattrer  = (*Node)(nil)
+	_ fs.NodeListxattrer = (*Node)(nil)
+)
+
+// Getxattr returns the extended attribute of the image file, like the security.capability of a binary. A dest too
+// small for the value gets its size with ERANGE.
+func (n *Node) Getxattr(ctx context.Context, attr string, dest []byte) (uint32, syscall.Errno) {
+	value, ok, err := n.FS.Cache.GetOrFetchXattr(n.Path, attr, func() (*fspb.GetXattrResponse, error) {
+		return n.FS.Client.GetXattr(ctx, &fspb.GetXattrRequest{
+			Path:        n.Path,
+			ImageDigest: n.FS.ImageDigest,
+			Name:        attr,
+		})
+	})
+
+	if err != nil {
+		slog.Error("getxattr: error", "path", n.Path, "name", attr, "err", err)
+		return 0, xattrErrno(err)
+	}
+	if !ok {
+		return 0, syscall.ENODATA
+	}
+
+	if len(dest) < len(value) {
+		return uint32(len(value)), syscall.ERANGE
+	}
+
+	return uint32(copy(dest, value)), 0
+}
+
+// Listxattr returns the names of the extended attributes of the image file, each terminated by a NUL byte. A dest too
+// small for the list gets its size with ERANGE.
+func (n *Node) Listxattr(ctx context.Context, dest []byte) (uint32, syscall.Errno) {
+	names, err := n.FS.Cache.GetOrFetchXattrNames(n.Path, func() (*fspb.ListXattrResponse, error) {
+		return n.FS.Client.ListXattr(ctx, &fspb.ListXattrRequest{
+			Path:        n.Path,
+			ImageDigest: n.FS.ImageDigest,
+		})
+	})
+
+	if err != nil {
+		slog.Error("listxattr: error", "path", n.Path, "err", err)
+		return 0, xattrErrno(err)
+	}
+
+	var size int
+	for _, name := range names {
+		size += len(name) + 1
+	}
+	if len(dest) < size {
+		return uint32(size), syscall.ERANGE
+	}
+
+	var written int
+	for _, name := range names {
+		written += copy(dest[written:], name)
+		dest[written] = 0
+		written++
+	}
+
+	return uint32(written), 0
+}
+
+func xattrErrno(err error) syscall.Errno {
+	if status.Code(err) == codes.NotFound {
+		return syscall.ENOENT
+	}
+	return syscall.EIO
+}
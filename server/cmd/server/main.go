@@ -1,11 +1,16 @@
 package main
 
 import (
+	"context"
+
 	"github.com/baepo-cloud/viscaufs-server/internal/config"
 	"github.com/baepo-cloud/viscaufs-server/internal/fxutil"
 	"github.com/baepo-cloud/viscaufs-server/internal/service/filehandlerservice"
 	"github.com/baepo-cloud/viscaufs-server/internal/service/fsindexservice"
+	"github.com/baepo-cloud/viscaufs-server/internal/service/gcservice"
 	"github.com/baepo-cloud/viscaufs-server/internal/service/imgservice"
+	"github.com/baepo-cloud/viscaufs-server/internal/service/progressservice"
+	"github.com/baepo-cloud/viscaufs-server/internal/service/statsservice"
 	"github.com/baepo-cloud/viscaufs-server/internal/types"
 	"github.com/baepo-cloud/viscaufs-server/internal/viscaufsserver"
 	_ "github.com/joho/godotenv/autoload"
@@ -19,10 +24,36 @@ func main() {
 		fx.Provide(fxutil.ProvideGORM),
 		fx.Provide(fxutil.ProvideGRPCServer),
 		fx.Provide(config.ParseConfig),
+		fx.Provide(fx.Annotate(progressservice.NewService, fx.As(new(types.ImageProgressService)))),
 		fx.Provide(fx.Annotate(fsindexservice.NewService, fx.As(new(types.FileSystemIndexService)))),
 		fx.Provide(fx.Annotate(imgservice.NewService, fx.As(new(types.ImageService)))),
+		fx.Provide(fx.Annotate(statsservice.NewService, fx.As(new(types.FileStatsService)))),
 		fx.Provide(fx.Annotate(filehandlerservice.NewService, fx.As(new(types.FileHandlerService)))),
+		fx.Provide(fx.Annotate(gcservice.NewService, fx.As(new(types.GarbageCollectorService)))),
 		fx.Provide(viscaufsserver.New),
+		fx.Invoke(func(lc fx.Lifecycle, imageService types.ImageService) {
+			lc.Append(fx.Hook{
+				OnStart: func(context.Context) error {
+					return imageService.ResumeInterrupted()
+				},
+			})
+		}),
+		fx.Invoke(func(lc fx.Lifecycle, gcService types.GarbageCollectorService, statsService types.FileStatsService, fileHandlerService types.FileHandlerService) {
+			lc.Append(fx.Hook{
+				OnStart: func(context.Context) error {
+					gcService.Start()
+					statsService.Start()
+					fileHandlerService.Start()
+					return nil
+				},
+				OnStop: func(context.Context) error {
+					fileHandlerService.Stop()
+					statsService.Stop()
+					gcService.Stop()
+					return nil
+				},
+			})
+		}),
 		fx.Invoke(func(server *grpc.Server) {}),
 		//fx.Invoke(func(db *gorm.DB) {
 		//	img := "sha256:86b823a6ef96fb1766da15f65eceb1378b748f45e3ef4ab00c7c7b0d8e00e46b"
@@ -0,0 +1,9 @@
+-- migrate:up
+
+alter table images add column index_digest text default '' not null;
+alter table images add column platform text default '' not null;
+
+-- migrate:down
+
+alter table images drop column platform;
+alter table images drop column index_digest;
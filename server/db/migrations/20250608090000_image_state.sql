@@ -0,0 +1,11 @@
+-- migrate:up
+
+alter table images add column state text default 'pending' not null;
+alter table images add column state_reason text default '' not null;
+
+update images set state = 'ready' where fs_index is not null;
+
+-- migrate:down
+
+alter table images drop column state_reason;
+alter table images drop column state;
@@ -0,0 +1,7 @@
+-- migrate:up
+
+alter table layers add column size integer default 0 not null;
+
+-- migrate:down
+
+alter table layers drop column size;
@@ -0,0 +1,16 @@
+-- migrate:up
+
+create table file_stats
+(
+    image_digest      text      not null,
+    layer_digest      text      not null,
+    path              text      not null,
+    open_count        integer   default 0 not null,
+    bytes_read        integer   default 0 not null,
+    first_accessed_at timestamp not null,
+    primary key (image_digest, path)
+);
+
+-- migrate:down
+
+drop table file_stats;
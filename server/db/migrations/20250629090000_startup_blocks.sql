@@ -0,0 +1,16 @@
+-- migrate:up
+
+-- blocks of the files read during the startup of the images, block -1 records a file opened without being read
+create table startup_blocks
+(
+    image_digest  text    not null,
+    path          text    not null,
+    block         integer not null,
+    runs          integer default 0 not null,
+    offset_ms_sum integer default 0 not null,
+    primary key (image_digest, path, block)
+);
+
+-- migrate:down
+
+drop table startup_blocks;
@@ -0,0 +1,14 @@
+-- migrate:up
+
+-- the file system indexes of the images deeper than 256 layers were written with wrapped layer positions, they are
+-- dropped and the images set back to partial so the server resumes their preparation at startup, rebuilding the
+-- indexes from the stored layers
+update images
+set fs_index = null,
+    state = 'partial',
+    state_reason = ''
+where id in (select image_id from image_layers group by image_id having count(*) > 256);
+
+-- migrate:down
+
+-- the dropped indexes are rebuilt when the preparations resume, there is nothing to restore
@@ -0,0 +1,7 @@
+-- migrate:up
+
+alter table images add column registry_token_required boolean default false not null;
+
+-- migrate:down
+
+alter table images drop column registry_token_required;
@@ -1,24 +1,80 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // Config holds application configuration
 type Config struct {
-	Addr                   string
+	// Addr is a comma separated list of addresses to listen on, TCP "host:port" or unix socket "unix:///path/to.sock".
+	Addr string
+	// SocketMode is the permission of the unix sockets listened on.
+	SocketMode             os.FileMode
 	SqliteDir              string
 	ImageDir               string
 	ImageServiceNumWorkers int
+	// ImageDiskQuota is the number of bytes the extracted layers may use, least recently used images are evicted
+	// to stay below it. There is no limit when zero.
+	ImageDiskQuota int64
+	// ImageImportDir is the directory OCI layouts and docker archives can be imported from, imports are disabled when empty.
+	ImageImportDir string
+	// DefaultPlatform is the os/arch[/variant] selected in multi-arch images when the request does not specify one.
+	DefaultPlatform string
+	// FDCacheSize is the number of file descriptors kept open once no handle or read uses them.
+	FDCacheSize int
+	// MaxOpenFiles is the number of file handles the clients may have open, there is no limit when zero.
+	MaxOpenFiles int
+	// SessionTimeout is the delay after which a client session without heartbeat expires and its handles are released.
+	SessionTimeout time.Duration
+
+	// GCInterval is the delay between two garbage collections of the unused images, collection is disabled when zero.
+	GCInterval time.Duration
+	// ImageRetentionDays is the number of days an image is kept after it was last used.
+	ImageRetentionDays int
+
+	// RegistryConfigFile is the path to a docker config.json used to resolve registry credentials,
+	// when empty the default docker/podman locations are used.
+	RegistryConfigFile string
+	// RegistryCredentials holds static credentials by registry host, they take precedence over the config file.
+	RegistryCredentials map[string]RegistryCredential
+	// RegistryMirrors holds the mirrors to try, in order, before contacting a registry.
+	RegistryMirrors map[string][]RegistryMirror
+}
+
+// RegistryCredential is a static username/password pair for a registry
+type RegistryCredential struct {
+	Username string
+	Password string
 }
 
-func ParseConfig() *Config {
+// RegistryMirror is a pull-through mirror of a registry
+type RegistryMirror struct {
+	Host string
+	// Insecure skips the TLS certificate verification of the mirror
+	Insecure bool
+	// PlainHTTP contacts the mirror without TLS
+	PlainHTTP bool
+}
+
+func ParseConfig() (*Config, error) {
 	defaultConfig := &Config{
 		Addr:                   ":8080",
+		SocketMode:             0660,
 		SqliteDir:              "db",
 		ImageDir:               "images",
 		ImageServiceNumWorkers: 8,
+		DefaultPlatform:        "linux/amd64",
+		FDCacheSize:            1024,
+		MaxOpenFiles:           65536,
+		SessionTimeout:         30 * time.Second,
+		GCInterval:             time.Hour,
+		ImageRetentionDays:     30,
+		RegistryCredentials:    map[string]RegistryCredential{},
+		RegistryMirrors:        map[string][]RegistryMirror{},
 	}
 
 	addr := os.Getenv("ADDR")
@@ -26,6 +82,14 @@ func ParseConfig() *Config {
 		defaultConfig.Addr = addr
 	}
 
+	socketMode := os.Getenv("SOCKET_MODE")
+	if socketMode != "" {
+		mode, err := strconv.ParseUint(socketMode, 8, 32)
+		if err == nil {
+			defaultConfig.SocketMode = os.FileMode(mode) & os.ModePerm
+		}
+	}
+
 	sqliteDir := os.Getenv("SQLITE_DIR")
 	if sqliteDir != "" {
 		defaultConfig.SqliteDir = sqliteDir
@@ -36,6 +100,19 @@ func ParseConfig() *Config {
 		defaultConfig.ImageDir = imageDir
 	}
 
+	imageImportDir := os.Getenv("IMAGE_IMPORT_DIR")
+	if imageImportDir != "" {
+		defaultConfig.ImageImportDir = imageImportDir
+	}
+
+	imageDiskQuota := os.Getenv("IMAGE_DISK_QUOTA")
+	if imageDiskQuota != "" {
+		quota, err := strconv.ParseInt(imageDiskQuota, 10, 64)
+		if err == nil && quota >= 0 {
+			defaultConfig.ImageDiskQuota = quota
+		}
+	}
+
 	imageServiceNumWorkers := os.Getenv("IMAGE_SERVICE_NUM_WORKERS")
 	if imageServiceNumWorkers != "" {
 		numWorkers, err := strconv.Atoi(imageServiceNumWorkers)
@@ -46,5 +123,129 @@ func ParseConfig() *Config {
 		defaultConfig.ImageServiceNumWorkers = 8
 	}
 
-	return defaultConfig
+	defaultPlatform := os.Getenv("DEFAULT_PLATFORM")
+	if defaultPlatform != "" {
+		defaultConfig.DefaultPlatform = defaultPlatform
+	}
+
+	fdCacheSize := os.Getenv("FD_CACHE_SIZE")
+	if fdCacheSize != "" {
+		size, err := strconv.Atoi(fdCacheSize)
+		if err == nil && size >= 0 {
+			defaultConfig.FDCacheSize = size
+		}
+	}
+
+	maxOpenFiles := os.Getenv("MAX_OPEN_FILES")
+	if maxOpenFiles != "" {
+		limit, err := strconv.Atoi(maxOpenFiles)
+		if err == nil && limit >= 0 {
+			defaultConfig.MaxOpenFiles = limit
+		}
+	}
+
+	sessionTimeout := os.Getenv("SESSION_TIMEOUT")
+	if sessionTimeout != "" {
+		timeout, err := time.ParseDuration(sessionTimeout)
+		if err == nil && timeout > 0 {
+			defaultConfig.SessionTimeout = timeout
+		}
+	}
+
+	gcInterval := os.Getenv("GC_INTERVAL")
+	if gcInterval != "" {
+		interval, err := time.ParseDuration(gcInterval)
+		if err == nil {
+			defaultConfig.GCInterval = interval
+		}
+	}
+
+	imageRetentionDays := os.Getenv("IMAGE_RETENTION_DAYS")
+	if imageRetentionDays != "" {
+		days, err := strconv.Atoi(imageRetentionDays)
+		if err == nil && days > 0 {
+			defaultConfig.ImageRetentionDays = days
+		}
+	}
+
+	registryConfigFile := os.Getenv("REGISTRY_CONFIG_FILE")
+	if registryConfigFile != "" {
+		defaultConfig.RegistryConfigFile = registryConfigFile
+	}
+
+	registryCredentials := os.Getenv("REGISTRY_CREDENTIALS")
+	if registryCredentials != "" {
+		credentials, err := parseRegistryCredentials(registryCredentials)
+		if err != nil {
+			return nil, err
+		}
+		defaultConfig.RegistryCredentials = credentials
+	}
+
+	registryMirrors := os.Getenv("REGISTRY_MIRRORS")
+	if registryMirrors != "" {
+		defaultConfig.RegistryMirrors = parseRegistryMirrors(registryMirrors)
+	}
+
+	return defaultConfig, nil
+}
+
+// parseRegistryCredentials parses a comma separated list of host=username:password entries, a malformed entry is
+// an error rather than a registry silently pulled anonymously. The errors never quote the credentials.
+func parseRegistryCredentials(raw string) (map[string]RegistryCredential, error) {
+	credentials := make(map[string]RegistryCredential)
+	for position, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		host, userPass, ok := strings.Cut(entry, "=")
+		if !ok || host == "" {
+			return nil, fmt.Errorf("invalid REGISTRY_CREDENTIALS entry %d: expected host=username:password", position+1)
+		}
+
+		username, password, ok := strings.Cut(userPass, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid REGISTRY_CREDENTIALS entry for %s: expected host=username:password", host)
+		}
+
+		credentials[host] = RegistryCredential{
+			Username: username,
+			Password: password,
+		}
+	}
+
+	return credentials, nil
+}
+
+// parseRegistryMirrors parses a comma separated list of registry=host[;insecure][;plain-http] entries,
+// mirrors of the same registry are tried in the order they appear.
+func parseRegistryMirrors(raw string) map[string][]RegistryMirror {
+	mirrors := make(map[string][]RegistryMirror)
+	for _, entry := range strings.Split(raw, ",") {
+		registry, rawMirror, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || registry == "" {
+			continue
+		}
+
+		options := strings.Split(rawMirror, ";")
+		mirror := RegistryMirror{Host: options[0]}
+		if mirror.Host == "" {
+			continue
+		}
+
+		for _, option := range options[1:] {
+			switch option {
+			case "insecure":
+				mirror.Insecure = true
+			case "plain-http":
+				mirror.PlainHTTP = true
+			}
+		}
+
+		mirrors[registry] = append(mirrors[registry], mirror)
+	}
+
+	return mirrors
 }
@@ -7,6 +7,7 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/baepo-cloud/viscaufs-server/db/migrations"
 	"github.com/baepo-cloud/viscaufs-server/internal/config"
@@ -31,13 +32,31 @@ func ProvideGRPCServer(lc fx.Lifecycle, cfg *config.Config, server *viscaufsserv
 
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
-			ln, err := net.Listen("tcp", cfg.Addr)
-			if err != nil {
-				return err
+			var listeners []net.Listener
+			for _, addr := range strings.Split(cfg.Addr, ",") {
+				addr = strings.TrimSpace(addr)
+				if addr == "" {
+					continue
+				}
+
+				ln, err := helper.Listen(addr, cfg.SocketMode)
+				if err != nil {
+					for _, l := range listeners {
+						l.Close()
+					}
+					return fmt.Errorf("failed to listen on %s: %w", addr, err)
+				}
+				listeners = append(listeners, ln)
 			}
 
-			slog.Info("grpc server starting", slog.String("addr", cfg.Addr), slog.String("service", "grpc"))
-			go grpcServer.Serve(ln)
+			if len(listeners) == 0 {
+				return fmt.Errorf("no address to listen on")
+			}
+
+			for _, ln := range listeners {
+				slog.Info("grpc server starting", slog.String("addr", ln.Addr().String()), slog.String("network", ln.Addr().Network()), slog.String("service", "grpc"))
+				go grpcServer.Serve(ln)
+			}
 			return nil
 		},
 		OnStop: func(ctx context.Context) error {
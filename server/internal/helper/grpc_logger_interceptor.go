@@ -157,6 +157,11 @@ func (s *serverStream) RecvMsg(m interface{}) error {
 	return err
 }
 
+// redactedFields lists the request fields holding secrets that must not be logged
+var redactedFields = map[string]struct{}{
+	"registrytoken": {},
+}
+
 // logRequestFields extracts and logs individual fields from the request object
 func logRequestFields(line *clog.Line, req interface{}) {
 	if req == nil {
@@ -189,6 +194,12 @@ func logRequestFields(line *clog.Line, req interface{}) {
 		fieldValue := v.Field(i)
 		fieldName := strings.ToLower(field.Name)
 
+		// Never log credentials carried by requests
+		if _, ok := redactedFields[fieldName]; ok {
+			line.Add("field."+fieldName, "[redacted]")
+			continue
+		}
+
 		// Handle nested structs if needed
 		if fieldValue.Kind() == reflect.Struct && !isSimpleType(fieldValue.Type()) {
 			// For complex nested structs, you might want to handle them specially
@@ -0,0 +1,81 @@
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const unixScheme = "unix://"
+
+// umaskMutex serializes the listens changing the umask of the process
+var umaskMutex sync.Mutex
+
+// Listen listens on addr, either a TCP address ("host:port", optionally prefixed with "tcp://") or a unix socket
+// ("unix:///path/to.sock"). The socket file is given mode, a socket left behind by a server that is not running
+// anymore is replaced, a socket of a running server or any other file is never removed.
+func Listen(addr string, mode os.FileMode) (net.Listener, error) {
+	path, ok := strings.CutPrefix(addr, unixScheme)
+	if !ok {
+		return net.Listen("tcp", strings.TrimPrefix(addr, "tcp://"))
+	}
+
+	if path == "" {
+		return nil, fmt.Errorf("invalid unix socket address %q", addr)
+	}
+
+	if err := removeStaleSocket(path); err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create socket directory: %w", err)
+	}
+
+	return listenUnix(path, mode)
+}
+
+// listenUnix binds the socket under a umask leaving it only the permissions of mode, a socket given its mode once
+// bound could be connected to by anyone in between
+func listenUnix(path string, mode os.FileMode) (net.Listener, error) {
+	umaskMutex.Lock()
+	defer umaskMutex.Unlock()
+
+	umask := syscall.Umask(int(^mode.Perm() & os.ModePerm))
+	defer syscall.Umask(umask)
+
+	return net.Listen("unix", path)
+}
+
+// removeStaleSocket removes the socket at path when no server accepts connections on it
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%s exists and is not a socket", path)
+	}
+
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err == nil {
+		conn.Close()
+		return fmt.Errorf("%s is already in use", path)
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove stale socket: %w", err)
+	}
+
+	return nil
+}
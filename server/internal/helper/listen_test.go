@@ -0,0 +1,88 @@
+package helper
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListenUnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run", "viscaufs.sock")
+
+	ln, err := Listen("unix://"+path, 0600)
+	require.NoError(t, err)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.NotZero(t, info.Mode()&os.ModeSocket)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	go func() {
+		conn, err := ln.Accept()
+		if err == nil {
+			conn.Close()
+		}
+	}()
+	conn, err := net.Dial("unix", path)
+	require.NoError(t, err)
+	conn.Close()
+
+	t.Run("socket in use", func(t *testing.T) {
+		_, err := Listen("unix://"+path, 0600)
+		assert.ErrorContains(t, err, "already in use")
+	})
+
+	t.Run("stale socket", func(t *testing.T) {
+		// a crashed server leaves its socket behind
+		ln.(*net.UnixListener).SetUnlinkOnClose(false)
+		require.NoError(t, ln.Close())
+		_, err := os.Stat(path)
+		require.NoError(t, err)
+
+		ln, err := Listen("unix://"+path, 0660)
+		require.NoError(t, err)
+		defer ln.Close()
+
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0660), info.Mode().Perm())
+	})
+
+	t.Run("not a socket", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "file")
+		require.NoError(t, os.WriteFile(file, []byte("data"), 0644))
+
+		_, err := Listen("unix://"+file, 0600)
+		assert.ErrorContains(t, err, "not a socket")
+
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		assert.Equal(t, "data", string(data))
+	})
+}
+
+func TestListenTCP(t *testing.T) {
+	for _, addr := range []string{"127.0.0.1:0", "tcp://127.0.0.1:0"} {
+		ln, err := Listen(addr, 0600)
+		require.NoError(t, err, addr)
+		assert.Equal(t, "tcp", ln.Addr().Network())
+		ln.Close()
+	}
+}
+
+func TestListenUnixSocketRestoresTheUmask(t *testing.T) {
+	umask := syscall.Umask(0022)
+	defer syscall.Umask(umask)
+
+	ln, err := Listen("unix://"+filepath.Join(t.TempDir(), "viscaufs.sock"), 0600)
+	require.NoError(t, err)
+	defer ln.Close()
+
+	// the socket is bound under the umask of its mode, the one of the process is restored afterwards
+	assert.Equal(t, 0022, syscall.Umask(0022))
+}
@@ -0,0 +1,50 @@
+package helper
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// OpenInRoot opens path read-only as if root was the filesystem root: "..", absolute paths and symlinks
+// (including absolute targets) are resolved inside root and can never reach a file outside of it.
+// It relies on openat2 RESOLVE_IN_ROOT and falls back to os.Root, which rejects escaping symlinks instead
+// of resolving them, on kernels without openat2.
+func OpenInRoot(root, path string) (*os.File, error) {
+	dirFd, err := unix.Open(root, unix.O_PATH|unix.O_DIRECTORY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, &os.PathError{Op: "open", Path: root, Err: err}
+	}
+	defer unix.Close(dirFd)
+
+	relPath := strings.TrimPrefix(filepath.Clean("/"+path), "/")
+	if relPath == "" {
+		relPath = "."
+	}
+
+	fd, err := unix.Openat2(dirFd, relPath, &unix.OpenHow{
+		Flags:   unix.O_RDONLY | unix.O_CLOEXEC,
+		Resolve: unix.RESOLVE_IN_ROOT | unix.RESOLVE_NO_MAGICLINKS,
+	})
+	if errors.Is(err, unix.ENOSYS) {
+		return openInRootFallback(root, relPath)
+	}
+	if err != nil {
+		return nil, &os.PathError{Op: "openat2", Path: filepath.Join(root, relPath), Err: err}
+	}
+
+	return os.NewFile(uintptr(fd), filepath.Join(root, relPath)), nil
+}
+
+func openInRootFallback(root, relPath string) (*os.File, error) {
+	r, err := os.OpenRoot(root)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return r.Open(relPath)
+}
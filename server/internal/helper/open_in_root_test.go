@@ -0,0 +1,52 @@
+package helper
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenInRoot(t *testing.T) {
+	parent := t.TempDir()
+	root := filepath.Join(parent, "root")
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "etc"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "etc", "hostname"), []byte("inside"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(parent, "secret"), []byte("outside"), 0644))
+
+	require.NoError(t, os.Symlink("/etc/hostname", filepath.Join(root, "absolute")))
+	require.NoError(t, os.Symlink("../../../secret", filepath.Join(root, "relative")))
+	require.NoError(t, os.Symlink(filepath.Join(parent, "secret"), filepath.Join(root, "host")))
+
+	testCases := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{name: "regular file", path: "/etc/hostname", expected: "inside"},
+		{name: "absolute symlink resolves inside root", path: "/absolute", expected: "inside"},
+		{name: "parent traversal is clamped to root", path: "/../../etc/hostname", expected: "inside"},
+		{name: "relative symlink is clamped to root", path: "/relative"},
+		{name: "symlink to a host path", path: "/host"},
+		{name: "parent traversal to a host path", path: "/../secret"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := OpenInRoot(root, tc.path)
+			if tc.expected == "" {
+				require.Error(t, err, "must never open a file outside of the root")
+				return
+			}
+			require.NoError(t, err)
+			defer f.Close()
+
+			content, err := io.ReadAll(f)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, string(content))
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package filehandlerservice
+
+import (
+	"container/list"
+	"os"
+	"sync"
+)
+
+type fdKey struct {
+	layerDigest string
+	path        string
+}
+
+type fdEntry struct {
+	key  fdKey
+	file *os.File
+	refs int
+	// idle is the position of the entry in the idle list, nil while it is referenced
+	idle *list.Element
+	// forgotten entries are closed once they are not referenced anymore
+	forgotten bool
+}
+
+// fdCache shares the descriptors of the files read across the requests, the descriptors not referenced anymore stay
+// open until the least recently used are closed to keep at most capacity of them
+type fdCache struct {
+	capacity int
+
+	mutex   sync.Mutex
+	entries map[fdKey]*fdEntry
+	idle    *list.List // least recently released first
+}
+
+func newFDCache(capacity int) *fdCache {
+	return &fdCache{
+		capacity: capacity,
+		entries:  make(map[fdKey]*fdEntry),
+		idle:     list.New(),
+	}
+}
+
+// acquire returns the descriptor of the file, opened with open when it is not cached. The descriptor must be given
+// back with release.
+func (c *fdCache) acquire(key fdKey, open func() (*os.File, error)) (*fdEntry, error) {
+	c.mutex.Lock()
+	if entry, ok := c.entries[key]; ok {
+		c.reference(entry)
+		c.mutex.Unlock()
+		return entry, nil
+	}
+	c.mutex.Unlock()
+
+	file, err := open()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// another request opened the file meanwhile
+	if entry, ok := c.entries[key]; ok {
+		file.Close()
+		c.reference(entry)
+		return entry, nil
+	}
+
+	entry := &fdEntry{key: key, file: file, refs: 1}
+	c.entries[key] = entry
+	return entry, nil
+}
+
+// reference adds a reference to the entry, the caller holds the mutex
+func (c *fdCache) reference(entry *fdEntry) {
+	if entry.idle != nil {
+		c.idle.Remove(entry.idle)
+		entry.idle = nil
+	}
+	entry.refs++
+}
+
+// release gives back a descriptor returned by acquire
+func (c *fdCache) release(entry *fdEntry) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry.refs--
+	if entry.refs > 0 {
+		return
+	}
+
+	if entry.forgotten {
+		entry.file.Close()
+		return
+	}
+
+	entry.idle = c.idle.PushBack(entry)
+	for c.idle.Len() > c.capacity {
+		c.evict(c.idle.Front().Value.(*fdEntry))
+	}
+}
+
+// evict closes an idle entry, the caller holds the mutex
+func (c *fdCache) evict(entry *fdEntry) {
+	c.idle.Remove(entry.idle)
+	entry.idle = nil
+	delete(c.entries, entry.key)
+	entry.file.Close()
+}
+
+// forgetLayer drops the descriptors of the files of the layer, those still referenced are closed on their release
+func (c *fdCache) forgetLayer(layerDigest string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for key, entry := range c.entries {
+		if key.layerDigest != layerDigest {
+			continue
+		}
+
+		if entry.idle != nil {
+			c.evict(entry)
+			continue
+		}
+
+		entry.forgotten = true
+		delete(c.entries, key)
+	}
+}
@@ -0,0 +1,121 @@
+package filehandlerservice
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// opener opens the files of dir and counts the opens
+type opener struct {
+	dir   string
+	opens int
+}
+
+func (o *opener) open(name string) func() (*os.File, error) {
+	return func() (*os.File, error) {
+		o.opens++
+		return os.Open(filepath.Join(o.dir, name))
+	}
+}
+
+func newOpener(t *testing.T, names ...string) *opener {
+	dir := t.TempDir()
+	for _, name := range names {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(name), 0644))
+	}
+	return &opener{dir: dir}
+}
+
+func isClosed(file *os.File) bool {
+	_, err := file.Stat()
+	return err != nil
+}
+
+func TestFDCacheSharesDescriptors(t *testing.T) {
+	o := newOpener(t, "a")
+	cache := newFDCache(2)
+	key := fdKey{layerDigest: "sha256:layer", path: "/a"}
+
+	first, err := cache.acquire(key, o.open("a"))
+	require.NoError(t, err)
+	second, err := cache.acquire(key, o.open("a"))
+	require.NoError(t, err)
+
+	assert.Same(t, first.file, second.file)
+	assert.Equal(t, 1, o.opens)
+
+	// the descriptor stays open once released, the next read reuses it
+	cache.release(first)
+	cache.release(second)
+	assert.False(t, isClosed(first.file))
+
+	third, err := cache.acquire(key, o.open("a"))
+	require.NoError(t, err)
+	assert.Same(t, first.file, third.file)
+	assert.Equal(t, 1, o.opens)
+	cache.release(third)
+}
+
+func TestFDCacheClosesLeastRecentlyUsed(t *testing.T) {
+	o := newOpener(t, "a", "b", "c")
+	cache := newFDCache(2)
+
+	entries := make(map[string]*fdEntry)
+	for _, name := range []string{"a", "b", "c"} {
+		entry, err := cache.acquire(fdKey{layerDigest: "sha256:layer", path: "/" + name}, o.open(name))
+		require.NoError(t, err)
+		entries[name] = entry
+	}
+
+	// the referenced descriptors are never closed
+	for _, entry := range entries {
+		assert.False(t, isClosed(entry.file))
+	}
+
+	cache.release(entries["a"])
+	cache.release(entries["b"])
+	cache.release(entries["c"])
+
+	assert.True(t, isClosed(entries["a"].file))
+	assert.False(t, isClosed(entries["b"].file))
+	assert.False(t, isClosed(entries["c"].file))
+
+	entry, err := cache.acquire(fdKey{layerDigest: "sha256:layer", path: "/a"}, o.open("a"))
+	require.NoError(t, err)
+	assert.Equal(t, 4, o.opens)
+	cache.release(entry)
+}
+
+func TestFDCacheForgetsLayers(t *testing.T) {
+	o := newOpener(t, "a", "b")
+	cache := newFDCache(2)
+
+	idle, err := cache.acquire(fdKey{layerDigest: "sha256:removed", path: "/a"}, o.open("a"))
+	require.NoError(t, err)
+	cache.release(idle)
+
+	used, err := cache.acquire(fdKey{layerDigest: "sha256:removed", path: "/b"}, o.open("b"))
+	require.NoError(t, err)
+
+	kept, err := cache.acquire(fdKey{layerDigest: "sha256:kept", path: "/a"}, o.open("a"))
+	require.NoError(t, err)
+	cache.release(kept)
+
+	cache.forgetLayer("sha256:removed")
+	assert.True(t, isClosed(idle.file))
+	assert.False(t, isClosed(kept.file))
+
+	// the descriptor being read is closed after the read
+	assert.False(t, isClosed(used.file))
+	cache.release(used)
+	assert.True(t, isClosed(used.file))
+
+	reopened, err := cache.acquire(fdKey{layerDigest: "sha256:removed", path: "/b"}, o.open("b"))
+	require.NoError(t, err)
+	assert.NotSame(t, used.file, reopened.file)
+	cache.release(reopened)
+}
@@ -4,26 +4,40 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"github.com/baepo-cloud/viscaufs/common/fsindex"
 	"io"
 	"log"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/alphadose/haxmap"
 	"github.com/baepo-cloud/viscaufs-server/internal/config"
+	"github.com/baepo-cloud/viscaufs-server/internal/helper"
 	"github.com/baepo-cloud/viscaufs-server/internal/types"
 	"github.com/nrednav/cuid2"
 	"gorm.io/gorm"
 )
 
+const (
+	// maxReadSize bounds the data returned by a stateless read
+	maxReadSize = 4 << 20
+	// streamChunkSize is the size of the chunks of a streamed read
+	streamChunkSize = 256 << 10
+)
+
 // FileHandle represents information about an open file
 type fileHandle struct {
+	ImageDigest  string
+	LayerDigest  string
 	RelativePath string
 	AbsolutePath string
-	File         *os.File
+	FD           *fdEntry
 	Flag         uint32
+	Prefetch     bool
+	SessionID    string
+	OpenedAt     time.Time
 }
 
 // Service handles container image operations
@@ -31,49 +45,58 @@ type Service struct {
 	basePath        string
 	db              *gorm.DB
 	fsIndexService  types.FileSystemIndexService
+	statsService    types.FileStatsService
 	pendingFileOpen *haxmap.Map[string, fileHandle]
+	fds             *fdCache
+	layerDigests    *haxmap.Map[string, []string] // by image digest
 	logger          *slog.Logger
+
+	maxOpenFiles   int
+	sessionTimeout time.Duration
+	sessionMutex   sync.Mutex
+	sessions       map[string]*session // by id
+	openHandles    int
+
+	stop chan struct{}
+	done chan struct{}
 }
 
 // NewService creates a new image service
-func NewService(cfg *config.Config, db *gorm.DB, fsIndexSvc types.FileSystemIndexService) (*Service, error) {
+func NewService(cfg *config.Config, db *gorm.DB, fsIndexSvc types.FileSystemIndexService, statsSvc types.FileStatsService) (*Service, error) {
 	return &Service{
 		basePath:        cfg.ImageDir,
 		db:              db,
 		fsIndexService:  fsIndexSvc,
+		statsService:    statsSvc,
 		pendingFileOpen: haxmap.New[string, fileHandle](),
+		fds:             newFDCache(cfg.FDCacheSize),
+		layerDigests:    haxmap.New[string, []string](),
 		logger:          slog.New(slog.NewTextHandler(log.Writer(), nil)).With("service", "file_handler"),
+		maxOpenFiles:    cfg.MaxOpenFiles,
+		sessionTimeout:  cfg.SessionTimeout,
+		sessions:        make(map[string]*session),
+		stop:            make(chan struct{}),
+		done:            make(chan struct{}),
 	}, nil
 }
 
-// OpenFile opens a file for reading
-func (s *Service) OpenFile(ctx context.Context, params types.OpenFileParams) (string, error) {
-	var (
-		image types.Image
-		uid   = cuid2.Generate()
-		node  *fsindex.Node
-	)
+// OpenFile opens a file for reading, the handle shares the descriptor of the file with the other handles and reads
+// of the same layer file
+func (s *Service) OpenFile(ctx context.Context, params types.OpenFileParams) (_ string, err error) {
+	uid := cuid2.Generate()
 
-	node = s.fsIndexService.Lookup(ctx, params.ImageDigest, params.Path)
-	if node == nil {
-		return "", types.ErrFileNotFound
+	if params.SessionID == "" {
+		return "", types.ErrSessionRequired
 	}
 
-	err := s.db.Where("digest = ?", params.ImageDigest).First(&image).Error
-	if err != nil {
-		return "", fmt.Errorf("failed to find image: %w", err)
+	if err := s.reserveHandle(); err != nil {
+		return "", err
 	}
-
-	layerDigest := image.LayerDigests[node.LayerPosition]
-
-	// check if the file exists on the appropriate path
-	filePath := filepath.Join(s.basePath, "layers", layerDigest, "content", params.Path)
-	if _, err := os.Stat(filePath); err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return "", fmt.Errorf("file not found: %w", err)
+	defer func() {
+		if err != nil {
+			s.unreserveHandle()
 		}
-		return "", fmt.Errorf("failed to stat file: %w", err)
-	}
+	}()
 
 	//// Combine all write-related flags
 	//writeFlags := os.O_WRONLY | os.O_RDWR | os.O_APPEND | os.O_CREATE | os.O_EXCL | os.O_TRUNC
@@ -81,36 +104,43 @@ func (s *Service) OpenFile(ctx context.Context, params types.OpenFileParams) (st
 	//// Remove write flags because we only want to read
 	//newFlag := int(params.Flags) &^ writeFlags
 
-	// open the file
-	file, err := os.OpenFile(filePath, os.O_RDONLY, 0)
+	fd, layerDigest, err := s.acquireFile(ctx, params.ImageDigest, params.Path)
 	if err != nil {
-		return "", fmt.Errorf("failed to open file: %w", err)
+		return "", err
 	}
 
 	fh := fileHandle{
-		RelativePath: params.Path,
+		ImageDigest:  params.ImageDigest,
+		LayerDigest:  layerDigest,
+		RelativePath: fd.key.path,
 		Flag:         params.Flags,
-		AbsolutePath: filePath,
-		File:         file,
+		Prefetch:     params.Prefetch,
+		AbsolutePath: fd.file.Name(),
+		FD:           fd,
+		SessionID:    params.SessionID,
+		OpenedAt:     time.Now(),
 	}
 
-	s.pendingFileOpen.Set(uid, fh)
+	if err := s.addHandle(uid, fh); err != nil {
+		s.fds.release(fd)
+		return "", err
+	}
+	if !fh.Prefetch {
+		s.statsService.RecordOpen(fh.ImageDigest, fh.LayerDigest, fh.RelativePath)
+	}
 
 	return uid, nil
 }
 
 // ReleaseFile releases the file handle
 func (s *Service) ReleaseFile(uid string) error {
-	fh, ok := s.pendingFileOpen.Get(uid)
+	fh, ok := s.pendingFileOpen.GetAndDel(uid)
 	if !ok {
 		return fmt.Errorf("file handle not found: %s", uid)
 	}
 
-	if err := fh.File.Close(); err != nil {
-		return fmt.Errorf("failed to close file: %w", err)
-	}
-
-	s.pendingFileOpen.Del(uid)
+	s.removeHandle(uid, fh)
+	s.fds.release(fh.FD)
 	return nil
 }
 
@@ -121,9 +151,12 @@ func (s *Service) ReadFile(uid string, offset int64, length uint32) ([]byte, err
 	}
 
 	data := make([]byte, length)
-	n, err := fh.File.ReadAt(data, offset)
+	n, err := fh.FD.file.ReadAt(data, offset)
 
 	if (err == nil || err == io.EOF) && n > 0 {
+		if !fh.Prefetch {
+			s.statsService.RecordRead(fh.ImageDigest, fh.LayerDigest, fh.RelativePath, offset, n)
+		}
 		return data[:n], nil
 	}
 
@@ -133,3 +166,189 @@ func (s *Service) ReadFile(uid string, offset int64, length uint32) ([]byte, err
 
 	return []byte{}, nil
 }
+
+// ReadAt reads a file of the image without a handle, the descriptor of the file is shared with the other reads of
+// the same layer file
+func (s *Service) ReadAt(ctx context.Context, params types.ReadAtParams) ([]byte, error) {
+	if params.Length > maxReadSize {
+		return nil, fmt.Errorf("%w: %d bytes requested, at most %d bytes", types.ErrReadTooLarge, params.Length, maxReadSize)
+	}
+
+	fd, layerDigest, err := s.acquireFile(ctx, params.ImageDigest, params.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer s.fds.release(fd)
+
+	data := make([]byte, params.Length)
+	n, err := fd.file.ReadAt(data, params.Offset)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	if n > 0 && !params.Prefetch {
+		s.statsService.RecordRead(params.ImageDigest, layerDigest, fd.key.path, params.Offset, n)
+	}
+
+	return data[:n], nil
+}
+
+// ReadStream reads the range through the handle, or by path, in chunks of streamChunkSize
+func (s *Service) ReadStream(ctx context.Context, params types.ReadStreamParams, send func(offset int64, data []byte) error) error {
+	var (
+		fd                       *fdEntry
+		imageDigest, layerDigest string
+		prefetch                 = params.Prefetch
+		err                      error
+	)
+	if params.Uid != "" {
+		fh, ok := s.pendingFileOpen.Get(params.Uid)
+		if !ok {
+			return fmt.Errorf("file handle not found: %s", params.Uid)
+		}
+
+		// the descriptor of the handle may be closed by its release while streaming, the stream holds its own
+		fd, err = s.acquireLayerFile(fh.LayerDigest, fh.RelativePath)
+		imageDigest, layerDigest, prefetch = fh.ImageDigest, fh.LayerDigest, fh.Prefetch
+	} else {
+		fd, layerDigest, err = s.acquireFile(ctx, params.ImageDigest, params.Path)
+		imageDigest = params.ImageDigest
+	}
+	if err != nil {
+		return err
+	}
+	defer s.fds.release(fd)
+
+	buffer := make([]byte, min(params.Length, streamChunkSize))
+	for offset, end := params.Offset, params.Offset+params.Length; offset < end; {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		n, err := fd.file.ReadAt(buffer[:min(end-offset, streamChunkSize)], offset)
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("failed to read file: %w", err)
+		}
+		if n == 0 {
+			return nil
+		}
+
+		if !prefetch {
+			s.statsService.RecordRead(imageDigest, layerDigest, fd.key.path, offset, n)
+		}
+		if err := send(offset, buffer[:n]); err != nil {
+			return err
+		}
+
+		offset += int64(n)
+		if err == io.EOF {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// RecordRead records a range the client read from a window it streamed as prefetch, without reading the file
+func (s *Service) RecordRead(ctx context.Context, params types.RecordReadParams) error {
+	layerDigest, path, err := s.locateFile(ctx, params.ImageDigest, params.Path)
+	if err != nil {
+		return err
+	}
+
+	if params.Length > 0 {
+		s.statsService.RecordRead(params.ImageDigest, layerDigest, path, params.Offset, int(params.Length))
+	}
+	return nil
+}
+
+// acquireFile returns the shared descriptor of the file of the image and the layer it is read from, it is given back
+// with fds.release. Once the descriptor and the layers of the image are cached, it does not touch the disk.
+func (s *Service) acquireFile(ctx context.Context, imageDigest, path string) (*fdEntry, string, error) {
+	layerDigest, path, err := s.locateFile(ctx, imageDigest, path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	fd, err := s.acquireLayerFile(layerDigest, path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return fd, layerDigest, nil
+}
+
+// locateFile returns the layer the file of the image is read from and its clean absolute path in the layer
+func (s *Service) locateFile(ctx context.Context, imageDigest, path string) (string, string, error) {
+	node := s.fsIndexService.Lookup(ctx, imageDigest, path)
+	if node == nil {
+		return "", "", types.ErrFileNotFound
+	}
+
+	layerDigests, err := s.imageLayerDigests(imageDigest)
+	if err != nil {
+		return "", "", err
+	}
+	if int(node.LayerPosition) >= len(layerDigests) {
+		return "", "", fmt.Errorf("layer %d of image %s not found", node.LayerPosition, imageDigest)
+	}
+
+	return layerDigests[node.LayerPosition], filepath.Clean("/" + path), nil
+}
+
+// acquireLayerFile returns the shared descriptor of the file of the layer, path is clean and absolute
+func (s *Service) acquireLayerFile(layerDigest, path string) (*fdEntry, error) {
+	fd, err := s.fds.acquire(fdKey{layerDigest: layerDigest, path: path}, func() (*os.File, error) {
+		// open the file inside its layer root, symlinks and ".." can never resolve outside of it
+		return helper.OpenInRoot(filepath.Join(s.basePath, "layers", layerDigest, "content"), path)
+	})
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %w", types.ErrFileNotFound, err)
+		}
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+
+	return fd, nil
+}
+
+// imageLayerDigests returns the digests of the layers of the image, bottom first. They are cached, the layers of an
+// image never change.
+func (s *Service) imageLayerDigests(imageDigest string) ([]string, error) {
+	if layerDigests, ok := s.layerDigests.Get(imageDigest); ok {
+		return layerDigests, nil
+	}
+
+	var image types.Image
+	err := s.db.Select("layer_digests").Where("digest = ?", imageDigest).First(&image).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, types.ErrImageNotFound
+		}
+		return nil, fmt.Errorf("failed to find image: %w", err)
+	}
+
+	s.layerDigests.Set(imageDigest, image.LayerDigests)
+	return image.LayerDigests, nil
+}
+
+// ForgetImage drops the cached layers of a deleted image
+func (s *Service) ForgetImage(imageDigest string) {
+	s.layerDigests.Del(imageDigest)
+}
+
+// CloseLayerFiles closes the shared descriptors of the files of the layer, those in use are closed after their read
+func (s *Service) CloseLayerFiles(layerDigest string) {
+	s.fds.forgetLayer(layerDigest)
+}
+
+// HasOpenFiles reports whether a file of the image is currently open
+func (s *Service) HasOpenFiles(imageDigest string) bool {
+	found := false
+	s.pendingFileOpen.ForEach(func(_ string, fh fileHandle) bool {
+		found = fh.ImageDigest == imageDigest
+		return !found
+	})
+
+	return found
+}
@@ -0,0 +1,140 @@
+package filehandlerservice
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/baepo-cloud/viscaufs-server/internal/config"
+	"github.com/baepo-cloud/viscaufs-server/internal/fxutil"
+	"github.com/baepo-cloud/viscaufs-server/internal/types"
+	"github.com/baepo-cloud/viscaufs/common/fsindex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImageLayerDigestsAreCached(t *testing.T) {
+	cfg := &config.Config{SqliteDir: t.TempDir(), ImageDir: t.TempDir()}
+	db, err := fxutil.ProvideGORM(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		sqlDB, err := db.DB()
+		if err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	service, err := NewService(cfg, db, nil, nil)
+	require.NoError(t, err)
+
+	layerDigests := []string{"sha256:base", "sha256:top"}
+	image := &types.Image{ID: "image", Digest: "sha256:image", LayerDigests: layerDigests, State: types.ImageStateReady}
+	require.NoError(t, db.Create(image).Error)
+
+	cached, err := service.imageLayerDigests("sha256:image")
+	require.NoError(t, err)
+	assert.Equal(t, layerDigests, cached)
+
+	// the next lookups do not query the database
+	require.NoError(t, db.Delete(image).Error)
+	cached, err = service.imageLayerDigests("sha256:image")
+	require.NoError(t, err)
+	assert.Equal(t, layerDigests, cached)
+
+	service.ForgetImage("sha256:image")
+	_, err = service.imageLayerDigests("sha256:image")
+	assert.ErrorIs(t, err, types.ErrImageNotFound)
+}
+
+func TestReadStreamSendsConsecutiveChunks(t *testing.T) {
+	cfg := &config.Config{ImageDir: t.TempDir()}
+	service, err := NewService(cfg, nil, nil, nil)
+	require.NoError(t, err)
+
+	content := make([]byte, 2*streamChunkSize+1000)
+	for i := range content {
+		content[i] = byte(i % 251)
+	}
+	contentDir := filepath.Join(cfg.ImageDir, "layers", "sha256:layer", "content", "bin")
+	require.NoError(t, os.MkdirAll(contentDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(contentDir, "app"), content, 0644))
+
+	fd, err := service.acquireLayerFile("sha256:layer", "/bin/app")
+	require.NoError(t, err)
+	sessionID, _ := service.OpenSession("")
+	require.NoError(t, service.addHandle("uid", fileHandle{
+		ImageDigest:  "sha256:image",
+		LayerDigest:  "sha256:layer",
+		RelativePath: "/bin/app",
+		FD:           fd,
+		Prefetch:     true,
+		SessionID:    sessionID,
+	}))
+
+	read := func(offset, length int64) ([]int64, []byte) {
+		var (
+			offsets []int64
+			data    []byte
+		)
+		err := service.ReadStream(context.Background(), types.ReadStreamParams{Uid: "uid", Offset: offset, Length: length},
+			func(offset int64, chunk []byte) error {
+				offsets = append(offsets, offset)
+				data = append(data, chunk...)
+				return nil
+			})
+		require.NoError(t, err)
+		return offsets, data
+	}
+
+	// the stream stops at the end of the file
+	offsets, data := read(100, 1<<20)
+	assert.Equal(t, []int64{100, 100 + streamChunkSize, 100 + 2*streamChunkSize}, offsets)
+	assert.Equal(t, content[100:], data)
+
+	offsets, data = read(10, 20)
+	assert.Equal(t, []int64{10}, offsets)
+	assert.Equal(t, content[10:30], data)
+
+	offsets, _ = read(int64(len(content)), 100)
+	assert.Empty(t, offsets)
+}
+
+// fileIndex finds every file in the top layer of a two layers image
+type fileIndex struct {
+	types.FileSystemIndexService
+}
+
+func (fileIndex) Lookup(_ context.Context, _, path string) *fsindex.Node {
+	return &fsindex.Node{Path: path, LayerPosition: 1}
+}
+
+// readRecorder records the reads of the files
+type readRecorder struct {
+	types.FileStatsService
+	reads []string
+}
+
+func (r *readRecorder) RecordRead(imageDigest, layerDigest, path string, offset int64, bytes int) {
+	r.reads = append(r.reads, fmt.Sprintf("%s %s %s %d+%d", imageDigest, layerDigest, path, offset, bytes))
+}
+
+func TestRecordReadRecordsTheLayerOfTheFile(t *testing.T) {
+	cfg := &config.Config{ImageDir: t.TempDir()}
+	stats := &readRecorder{}
+	service, err := NewService(cfg, nil, fileIndex{}, stats)
+	require.NoError(t, err)
+	service.layerDigests.Set("sha256:image", []string{"sha256:base", "sha256:top"})
+
+	// the file is not read, the client read it from a window it streamed as prefetch
+	require.NoError(t, service.RecordRead(context.Background(), types.RecordReadParams{
+		ImageDigest: "sha256:image",
+		Path:        "bin//app",
+		Offset:      4096,
+		Length:      1 << 20,
+	}))
+	require.NoError(t, service.RecordRead(context.Background(), types.RecordReadParams{ImageDigest: "sha256:image", Path: "/bin/app"}))
+
+	assert.Equal(t, []string{"sha256:image sha256:top /bin/app 4096+1048576"}, stats.reads)
+}
@@ -0,0 +1,199 @@
+package filehandlerservice
+
+import (
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/baepo-cloud/viscaufs-server/internal/types"
+	"github.com/nrednav/cuid2"
+)
+
+// heartbeatsPerTimeout is the number of heartbeats a client sends during the timeout of its session, a session
+// expires after missing all of them
+const heartbeatsPerTimeout = 3
+
+// session holds the handles of a client, they are released when the client stops its heartbeats
+type session struct {
+	id              string
+	lastHeartbeatAt time.Time
+	handles         map[string]struct{}
+}
+
+func (s *Service) OpenSession(sessionID string) (string, time.Duration) {
+	s.sessionMutex.Lock()
+	defer s.sessionMutex.Unlock()
+
+	if current, ok := s.sessions[sessionID]; ok {
+		current.lastHeartbeatAt = time.Now()
+		return current.id, s.sessionTimeout / heartbeatsPerTimeout
+	}
+
+	current := &session{id: cuid2.Generate(), lastHeartbeatAt: time.Now(), handles: make(map[string]struct{})}
+	s.sessions[current.id] = current
+	s.logger.Info("session opened", slog.String("session_id", current.id), slog.String("resumed_session_id", sessionID))
+	return current.id, s.sessionTimeout / heartbeatsPerTimeout
+}
+
+func (s *Service) Heartbeat(sessionID string) error {
+	s.sessionMutex.Lock()
+	defer s.sessionMutex.Unlock()
+
+	current, ok := s.sessions[sessionID]
+	if !ok {
+		return types.ErrSessionNotFound
+	}
+
+	current.lastHeartbeatAt = time.Now()
+	return nil
+}
+
+func (s *Service) CloseSession(sessionID string) {
+	s.sessionMutex.Lock()
+	current, ok := s.sessions[sessionID]
+	delete(s.sessions, sessionID)
+	s.sessionMutex.Unlock()
+
+	if ok {
+		s.releaseSession(current)
+	}
+}
+
+func (s *Service) ListSessions(sessionID string) []types.Session {
+	s.sessionMutex.Lock()
+	sessions := make([]types.Session, 0, len(s.sessions))
+	for _, current := range s.sessions {
+		if sessionID != "" && current.id != sessionID {
+			continue
+		}
+
+		handles := make([]types.OpenHandle, 0, len(current.handles))
+		for uid := range current.handles {
+			if fh, ok := s.pendingFileOpen.Get(uid); ok {
+				handles = append(handles, types.OpenHandle{
+					Uid:         uid,
+					ImageDigest: fh.ImageDigest,
+					Path:        fh.RelativePath,
+					OpenedAt:    fh.OpenedAt,
+				})
+			}
+		}
+		slices.SortFunc(handles, func(a, b types.OpenHandle) int {
+			return a.OpenedAt.Compare(b.OpenedAt)
+		})
+
+		sessions = append(sessions, types.Session{
+			ID:              current.id,
+			LastHeartbeatAt: current.lastHeartbeatAt,
+			Handles:         handles,
+		})
+	}
+	s.sessionMutex.Unlock()
+
+	slices.SortFunc(sessions, func(a, b types.Session) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	return sessions
+}
+
+// Start expires the sessions without heartbeat in the background
+func (s *Service) Start() {
+	go func() {
+		defer close(s.done)
+
+		ticker := time.NewTicker(s.sessionTimeout / heartbeatsPerTimeout)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				s.expireSessions()
+			}
+		}
+	}()
+}
+
+// Stop stops the expiration of the sessions
+func (s *Service) Stop() {
+	close(s.stop)
+	<-s.done
+}
+
+// expireSessions releases the handles of the sessions whose last heartbeat is older than the timeout
+func (s *Service) expireSessions() {
+	var expired []*session
+
+	s.sessionMutex.Lock()
+	for id, current := range s.sessions {
+		if time.Since(current.lastHeartbeatAt) > s.sessionTimeout {
+			expired = append(expired, current)
+			delete(s.sessions, id)
+		}
+	}
+	s.sessionMutex.Unlock()
+
+	for _, current := range expired {
+		s.logger.Warn("session expired", slog.String("session_id", current.id), slog.Int("handles", len(current.handles)))
+		s.releaseSession(current)
+	}
+}
+
+// releaseSession releases the handles of a session removed from the sessions
+func (s *Service) releaseSession(current *session) {
+	for uid := range current.handles {
+		if err := s.ReleaseFile(uid); err != nil {
+			s.logger.Error("failed to release handle of session",
+				slog.String("session_id", current.id), slog.String("uid", uid), slog.Any("error", err))
+		}
+	}
+}
+
+// reserveHandle counts a handle being opened against the limit of open files
+func (s *Service) reserveHandle() error {
+	s.sessionMutex.Lock()
+	defer s.sessionMutex.Unlock()
+
+	if s.maxOpenFiles > 0 && s.openHandles >= s.maxOpenFiles {
+		return types.ErrTooManyOpenFiles
+	}
+
+	s.openHandles++
+	return nil
+}
+
+// unreserveHandle gives back a handle counted by reserveHandle
+func (s *Service) unreserveHandle() {
+	s.sessionMutex.Lock()
+	s.openHandles--
+	s.sessionMutex.Unlock()
+}
+
+// addHandle ties an opened handle to its session. A session unknown to this server, like one opened on another server
+// or already expired, is never heartbeated here: the handle is refused so the client opens its session again.
+func (s *Service) addHandle(uid string, fh fileHandle) error {
+	s.sessionMutex.Lock()
+	defer s.sessionMutex.Unlock()
+
+	current, ok := s.sessions[fh.SessionID]
+	if !ok {
+		return types.ErrSessionNotFound
+	}
+
+	s.pendingFileOpen.Set(uid, fh)
+	current.handles[uid] = struct{}{}
+	return nil
+}
+
+// removeHandle unties a released handle from its session
+func (s *Service) removeHandle(uid string, fh fileHandle) {
+	s.sessionMutex.Lock()
+	defer s.sessionMutex.Unlock()
+
+	s.openHandles--
+	if current, ok := s.sessions[fh.SessionID]; ok {
+		delete(current.handles, uid)
+	}
+}
@@ -0,0 +1,168 @@
+package filehandlerservice
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/baepo-cloud/viscaufs-server/internal/config"
+	"github.com/baepo-cloud/viscaufs-server/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSessionService(t *testing.T, maxOpenFiles int) *Service {
+	service, err := NewService(&config.Config{
+		ImageDir:       t.TempDir(),
+		MaxOpenFiles:   maxOpenFiles,
+		SessionTimeout: time.Minute,
+	}, nil, nil, nil)
+	require.NoError(t, err)
+	return service
+}
+
+// open registers a handle of the session the way OpenFile does once the file is found, the service caches no
+// descriptor so the file is closed on release
+func open(t *testing.T, service *Service, uid, sessionID string) (*os.File, error) {
+	if err := service.reserveHandle(); err != nil {
+		return nil, err
+	}
+
+	o := newOpener(t, uid)
+	fd, err := service.fds.acquire(fdKey{layerDigest: "sha256:layer", path: "/" + uid}, o.open(uid))
+	require.NoError(t, err)
+
+	err = service.addHandle(uid, fileHandle{
+		ImageDigest:  "sha256:image",
+		RelativePath: "/" + uid,
+		FD:           fd,
+		SessionID:    sessionID,
+		OpenedAt:     time.Now(),
+	})
+	if err != nil {
+		service.fds.release(fd)
+		service.unreserveHandle()
+		return fd.file, err
+	}
+	return fd.file, nil
+}
+
+func TestSessionExpiresWithoutHeartbeat(t *testing.T) {
+	service := newSessionService(t, 0)
+
+	alive, interval := service.OpenSession("")
+	assert.Equal(t, time.Minute/heartbeatsPerTimeout, interval)
+	dead, _ := service.OpenSession("")
+	assert.NotEqual(t, alive, dead)
+
+	aliveFile, err := open(t, service, "alive", alive)
+	require.NoError(t, err)
+	deadFile, err := open(t, service, "dead", dead)
+	require.NoError(t, err)
+
+	service.sessions[alive].lastHeartbeatAt = time.Now().Add(-2 * time.Minute)
+	service.sessions[dead].lastHeartbeatAt = time.Now().Add(-2 * time.Minute)
+	require.NoError(t, service.Heartbeat(alive))
+
+	service.expireSessions()
+
+	assert.ErrorIs(t, service.Heartbeat(dead), types.ErrSessionNotFound)
+	_, err = deadFile.Stat()
+	assert.ErrorIs(t, err, os.ErrClosed)
+	assert.Error(t, service.ReleaseFile("dead"))
+
+	_, err = aliveFile.Stat()
+	assert.NoError(t, err)
+	require.NoError(t, service.ReleaseFile("alive"))
+
+	// an expired session cannot be resumed
+	resumed, _ := service.OpenSession(dead)
+	assert.NotEqual(t, dead, resumed)
+	resumed, _ = service.OpenSession(alive)
+	assert.Equal(t, alive, resumed)
+}
+
+func TestCloseSessionReleasesHandles(t *testing.T) {
+	service := newSessionService(t, 0)
+
+	sessionID, _ := service.OpenSession("")
+	first, err := open(t, service, "first", sessionID)
+	require.NoError(t, err)
+	_, err = open(t, service, "second", sessionID)
+	require.NoError(t, err)
+	otherID, _ := service.OpenSession("")
+	_, err = open(t, service, "other", otherID)
+	require.NoError(t, err)
+
+	sessions := service.ListSessions("")
+	require.Len(t, sessions, 2)
+	current := sessions[0]
+	if current.ID != sessionID {
+		current = sessions[1]
+	}
+	assert.Equal(t, sessionID, current.ID)
+	assert.False(t, current.LastHeartbeatAt.IsZero())
+	require.Len(t, current.Handles, 2)
+	assert.Equal(t, "first", current.Handles[0].Uid)
+	assert.Equal(t, "/first", current.Handles[0].Path)
+	assert.Equal(t, "second", current.Handles[1].Uid)
+
+	sessions = service.ListSessions(sessionID)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, sessionID, sessions[0].ID)
+
+	service.CloseSession(sessionID)
+	_, err = first.Stat()
+	assert.ErrorIs(t, err, os.ErrClosed)
+
+	sessions = service.ListSessions("")
+	require.Len(t, sessions, 1)
+	assert.Equal(t, otherID, sessions[0].ID)
+	require.Len(t, sessions[0].Handles, 1)
+	assert.Equal(t, "other", sessions[0].Handles[0].Uid)
+	assert.Equal(t, 1, service.openHandles)
+}
+
+func TestOpenFileRequiresASession(t *testing.T) {
+	service := newSessionService(t, 0)
+
+	_, err := service.OpenFile(context.Background(), types.OpenFileParams{ImageDigest: "sha256:image", Path: "/file"})
+	assert.ErrorIs(t, err, types.ErrSessionRequired)
+	assert.Zero(t, service.openHandles, "the rejected open holds no handle")
+	assert.Empty(t, service.ListSessions(""))
+}
+
+func TestOpenRefusesUnknownSessions(t *testing.T) {
+	service := newSessionService(t, 0)
+
+	// the session is held by another server, or expired, nothing heartbeats it here
+	file, err := open(t, service, "elsewhere", "unknown")
+	assert.ErrorIs(t, err, types.ErrSessionNotFound)
+	_, err = file.Stat()
+	assert.ErrorIs(t, err, os.ErrClosed, "the descriptor of the refused handle is released")
+	assert.Zero(t, service.openHandles)
+	assert.Empty(t, service.ListSessions(""))
+
+	expired, _ := service.OpenSession("")
+	service.CloseSession(expired)
+	_, err = open(t, service, "expired", expired)
+	assert.ErrorIs(t, err, types.ErrSessionNotFound)
+}
+
+func TestOpenFilesAreCapped(t *testing.T) {
+	service := newSessionService(t, 2)
+
+	sessionID, _ := service.OpenSession("")
+	_, err := open(t, service, "first", sessionID)
+	require.NoError(t, err)
+	_, err = open(t, service, "second", sessionID)
+	require.NoError(t, err)
+
+	_, err = open(t, service, "third", sessionID)
+	assert.ErrorIs(t, err, types.ErrTooManyOpenFiles)
+
+	require.NoError(t, service.ReleaseFile("first"))
+	_, err = open(t, service, "third", sessionID)
+	assert.NoError(t, err)
+}
@@ -2,6 +2,7 @@ package fsindexservice
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"log/slog"
@@ -19,18 +20,29 @@ type Service struct {
 	layerDigestToFSIndex *haxmap.Map[string, *fsindex.Index]
 	imageDigestToFSIndex *haxmap.Map[string, *fsindex.Index]
 
-	db     *gorm.DB
-	logger *slog.Logger
+	db              *gorm.DB
+	progressService types.ImageProgressService
+	markImageFailed func(imageDigest string, cause error) // set by the image service
+	logger          *slog.Logger
 }
 
 // NewService creates a new fsindex service.
-func NewService(db *gorm.DB) *Service {
-	return &Service{
+func NewService(db *gorm.DB, progressSvc types.ImageProgressService) *Service {
+	s := &Service{
 		db:                   db,
+		progressService:      progressSvc,
 		logger:               slog.New(slog.NewTextHandler(log.Writer(), nil)).With("service", "fsindex"),
 		layerDigestToFSIndex: haxmap.New[string, *fsindex.Index](),
 		imageDigestToFSIndex: haxmap.New[string, *fsindex.Index](),
 	}
+	s.markImageFailed = func(imageDigest string, cause error) {
+		s.logger.Error("image failed", slog.String("image_digest", imageDigest), slog.Any("error", cause))
+	}
+	return s
+}
+
+func (s *Service) OnImageFailed(markImageFailed func(imageDigest string, cause error)) {
+	s.markImageFailed = markImageFailed
 }
 
 func (s *Service) CreateImageIndexChannel(imageDigest string) chan<- types.FileSystemIndexLayer {
@@ -40,14 +52,37 @@ func (s *Service) CreateImageIndexChannel(imageDigest string) chan<- types.FileS
 		var imageFSIndex *fsindex.Index
 		firstTimeJoin := true
 		now := time.Now()
+		// fail drops the partial index before reporting the failure, and lets the producer finish without blocking
+		fail := func(err error) {
+			s.imageDigestToFSIndex.Del(imageDigest)
+			s.markImageFailed(imageDigest, err)
+			for range layersChan {
+			}
+		}
+
 		for layer := range layersChan {
+			if layer.Err != nil {
+				fail(layer.Err)
+				return
+			}
+
 			nowLayer := time.Now()
 			var currentFsIndex *fsindex.Index
 			layerFSIndex, ok := s.layerDigestToFSIndex.Get(layer.Digest)
 			if ok {
-				currentFsIndex = layerFSIndex
+				// the layer index is shared by the images using the layer, the join must not change it
+				currentFsIndex = layerFSIndex.Clone()
 			} else {
-				currentFsIndex, _ = fsindex.Deserialize(layer.SerializedData, false)
+				var err error
+				currentFsIndex, err = fsindex.Deserialize(layer.SerializedData, false)
+				if err != nil {
+					s.logger.Error("failed to deserialize layer fs index",
+						slog.String("image_digest", imageDigest),
+						slog.String("layer_digest", layer.Digest),
+						slog.Any("error", err))
+					fail(fmt.Errorf("failed to deserialize fs index of layer %s: %w", layer.Digest, err))
+					return
+				}
 			}
 
 			if imageFSIndex == nil {
@@ -57,6 +92,7 @@ func (s *Service) CreateImageIndexChannel(imageDigest string) chan<- types.FileS
 				firstTimeJoin = false
 				imageFSIndex = currentFsIndex
 			}
+			imageFSIndex.AssignInodes()
 
 			slog.Info("layer indexed",
 				slog.String("image_digest", imageDigest),
@@ -68,21 +104,49 @@ func (s *Service) CreateImageIndexChannel(imageDigest string) chan<- types.FileS
 				imageFSIndex.IsComplete = true
 			}
 			s.imageDigestToFSIndex.Set(imageDigest, imageFSIndex)
+
+			s.progressService.Publish(types.ImageEvent{
+				ImageDigest:   imageDigest,
+				Kind:          types.ImageEventLayerJoined,
+				LayerDigest:   layer.Digest,
+				LayerPosition: int(layer.Position),
+			})
 		}
 
-		if imageFSIndex != nil {
-			serializeFSIndex, err := imageFSIndex.Serialize()
-			if err != nil {
-				s.logger.Error("failed to serialize image fs index", slog.String("image_digest", imageDigest), slog.Any("error", err))
-				return
-			}
+		if imageFSIndex == nil {
+			return
+		}
 
-			err = s.db.Model(&types.Image{}).Where("digest = ?", imageDigest).Update("fs_index", serializeFSIndex).Error
-			if err != nil {
-				s.logger.Error("failed to update image fs index", slog.String("image_digest", imageDigest), slog.Any("error", err))
-			}
-			s.logger.Info("entire image indexed", slog.String("image_digest", imageDigest), slog.Duration("duration", time.Since(now)))
+		// the channel was closed before the bottom layer, the preparation failed and the partial index must not be served
+		if !imageFSIndex.IsComplete {
+			s.imageDigestToFSIndex.Del(imageDigest)
+			s.logger.Warn("image indexing interrupted", slog.String("image_digest", imageDigest))
+			return
 		}
+
+		serializeFSIndex, err := imageFSIndex.Serialize()
+		if err != nil {
+			s.logger.Error("failed to serialize image fs index", slog.String("image_digest", imageDigest), slog.Any("error", err))
+			s.markImageFailed(imageDigest, fmt.Errorf("failed to serialize image fs index: %w", err))
+			return
+		}
+
+		err = s.db.Model(&types.Image{}).Where("digest = ?", imageDigest).Updates(map[string]any{
+			"fs_index":     serializeFSIndex,
+			"state":        types.ImageStateReady,
+			"state_reason": "",
+		}).Error
+		if err != nil {
+			s.logger.Error("failed to update image fs index", slog.String("image_digest", imageDigest), slog.Any("error", err))
+			s.markImageFailed(imageDigest, fmt.Errorf("failed to update image fs index: %w", err))
+			return
+		}
+		s.logger.Info("entire image indexed", slog.String("image_digest", imageDigest), slog.Duration("duration", time.Since(now)))
+
+		s.progressService.Publish(types.ImageEvent{
+			ImageDigest: imageDigest,
+			Kind:        types.ImageEventReady,
+		})
 	}()
 
 	return layersChan
@@ -100,20 +164,42 @@ func (s *Service) Ready(imageDigest string) bool {
 		return false
 	}
 
-	if imageModel.FsIndex != nil {
-		deserializeFSIndex, _ := fsindex.Deserialize(imageModel.FsIndex, true)
-		s.imageDigestToFSIndex.Set(imageDigest, deserializeFSIndex)
-		return true
+	if imageModel.State != types.ImageStateReady || imageModel.FsIndex == nil {
+		return false
 	}
 
-	return false
+	deserializeFSIndex, err := fsindex.Deserialize(imageModel.FsIndex, true)
+	if errors.Is(err, fsindex.ErrStaleIndex) {
+		// the next preparation of the image rebuilds the index from its layers
+		s.logger.Warn("stale image fs index", slog.String("image_digest", imageDigest))
+		return false
+	}
+	if err != nil {
+		// preparing the image again rebuilds the index from its layers
+		s.logger.Error("failed to deserialize image fs index", slog.String("image_digest", imageDigest), slog.Any("error", err))
+		s.markImageFailed(imageDigest, fmt.Errorf("failed to deserialize image fs index: %w", err))
+		return false
+	}
+	s.imageDigestToFSIndex.Set(imageDigest, deserializeFSIndex)
+
+	return true
 }
 
-func (s *Service) BuildImageIndex(img *types.Image, digestToPosition map[string]uint8) {
+func (s *Service) BuildImageIndex(img *types.Image, digestToPosition map[string]uint32) {
 	if img.FsIndex != nil {
-		fi, _ := fsindex.Deserialize(img.FsIndex, true)
-		s.imageDigestToFSIndex.Set(img.Digest, fi)
-		return
+		fi, err := fsindex.Deserialize(img.FsIndex, true)
+		if err == nil {
+			s.imageDigestToFSIndex.Set(img.Digest, fi)
+			return
+		}
+
+		if errors.Is(err, fsindex.ErrStaleIndex) {
+			s.logger.Warn("stale image fs index, rebuilding it from the layers", slog.String("image_digest", img.Digest))
+		} else {
+			s.logger.Error("failed to deserialize image fs index, rebuilding it from the layers",
+				slog.String("image_digest", img.Digest),
+				slog.Any("error", err))
+		}
 	}
 
 	indexer := s.CreateImageIndexChannel(img.Digest)
@@ -132,13 +218,8 @@ func (s *Service) BuildImageIndex(img *types.Image, digestToPosition map[string]
 	close(indexer)
 }
 
-func (s *Service) BuildLayerIndex(path, layerDigest string) ([]byte, error) {
-	index := fsindex.NewFSIndex()
-	err := index.BuildIndex(path)
-	if err != nil {
-		return nil, fmt.Errorf("failed to build file sytem index: %w", err)
-	}
-
+// RegisterLayerIndex keeps the index of a freshly extracted layer in memory and returns its serialized form
+func (s *Service) RegisterLayerIndex(layerDigest string, index *fsindex.Index) ([]byte, error) {
 	serializedFileSystemIndex, err := index.Serialize()
 	if err != nil {
 		return nil, fmt.Errorf("failed to serialize file system index: %w", err)
@@ -148,6 +229,14 @@ func (s *Service) BuildLayerIndex(path, layerDigest string) ([]byte, error) {
 	return serializedFileSystemIndex, nil
 }
 
+func (s *Service) RemoveImage(imageDigest string) {
+	s.imageDigestToFSIndex.Del(imageDigest)
+}
+
+func (s *Service) RemoveLayer(layerDigest string) {
+	s.layerDigestToFSIndex.Del(layerDigest)
+}
+
 // Lookup attempts to lookup a path in the filesystem index
 // and retries if the index is still being built, unless context is done
 func (s *Service) Lookup(ctx context.Context, imageDigest, path string) *fsindex.Node {
@@ -0,0 +1,122 @@
+package fsindexservice
+
+import (
+	"archive/tar"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/baepo-cloud/viscaufs-server/internal/config"
+	"github.com/baepo-cloud/viscaufs-server/internal/fxutil"
+	"github.com/baepo-cloud/viscaufs-server/internal/service/progressservice"
+	"github.com/baepo-cloud/viscaufs-server/internal/types"
+	"github.com/baepo-cloud/viscaufs/common/fsindex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestService(t *testing.T) (*Service, *progressservice.Service) {
+	t.Helper()
+
+	db, err := fxutil.ProvideGORM(&config.Config{SqliteDir: t.TempDir()})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		sqlDB, err := db.DB()
+		if err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	progress := progressservice.NewService()
+	return NewService(db, progress), progress
+}
+
+// registerLayer keeps the index of a layer made of the tar entries in memory, the names ending with a slash are
+// directories and the hardlinks map a name to the name it links to
+func registerLayer(t *testing.T, s *Service, digest string, names []string, hardlinks map[string]string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	dirInfo, err := os.Lstat(dir)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file"), nil, 0644))
+	fileInfo, err := os.Lstat(filepath.Join(dir, "file"))
+	require.NoError(t, err)
+
+	index := fsindex.NewFSIndex()
+	for _, name := range names {
+		switch target, ok := hardlinks[name]; {
+		case strings.HasSuffix(name, "/"):
+			index.AddTarEntry(&tar.Header{Typeflag: tar.TypeDir, Name: name, Mode: 0755}, dirInfo)
+		case ok:
+			index.AddTarEntry(&tar.Header{Typeflag: tar.TypeLink, Name: name, Linkname: target}, fileInfo)
+		default:
+			index.AddTarEntry(&tar.Header{Typeflag: tar.TypeReg, Name: name, Mode: 0644}, fileInfo)
+		}
+	}
+	_, err = s.RegisterLayerIndex(digest, index)
+	require.NoError(t, err)
+}
+
+// indexImage joins the layers, bottom first, into the index of the image and waits for it
+func indexImage(t *testing.T, s *Service, progress *progressservice.Service, imageDigest string, layerDigests ...string) {
+	t.Helper()
+
+	_, events, cancel := progress.Subscribe(imageDigest)
+	defer cancel()
+
+	indexer := s.CreateImageIndexChannel(imageDigest)
+	for position := len(layerDigests) - 1; position >= 0; position-- {
+		indexer <- types.FileSystemIndexLayer{Digest: layerDigests[position], Position: uint32(position)}
+	}
+	close(indexer)
+
+	for {
+		select {
+		case event := <-events:
+			require.NotEqual(t, types.ImageEventFailed, event.Kind, event.Error)
+			if event.Kind == types.ImageEventReady {
+				return
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("image %s was not indexed", imageDigest)
+		}
+	}
+}
+
+func TestImagesSharingALayerKeepTheirInodes(t *testing.T) {
+	s, progress := newTestService(t)
+	registerLayer(t, s, "sha256:base", []string{"/srv/", "/srv/base"}, nil)
+	registerLayer(t, s, "sha256:shared", []string{"/bin/", "/bin/a", "/bin/b", "/etc/", "/etc/hosts"},
+		map[string]string{"/bin/b": "/bin/a"})
+	registerLayer(t, s, "sha256:top", []string{"/bin/.wh.b", "/etc/.wh.hosts"}, nil)
+
+	// the shared layer is the bottom layer of the first image and the middle layer of the second one
+	indexImage(t, s, progress, "sha256:first", "sha256:shared")
+	paths := []string{"/bin", "/bin/a", "/bin/b", "/etc", "/etc/hosts"}
+	first := make(map[string]fsindex.Node)
+	for _, path := range paths {
+		node := s.Lookup(context.Background(), "sha256:first", path)
+		require.NotNil(t, node, path)
+		first[path] = *node
+	}
+	assert.Equal(t, first["/bin/a"].Attributes.Inode, first["/bin/b"].Attributes.Inode)
+	assert.Equal(t, uint64(2), first["/bin/a"].Attributes.Nlink)
+
+	indexImage(t, s, progress, "sha256:second", "sha256:base", "sha256:shared", "sha256:top")
+	a := s.Lookup(context.Background(), "sha256:second", "/bin/a")
+	require.NotNil(t, a)
+	assert.Equal(t, uint32(1), a.LayerPosition)
+	assert.Equal(t, uint64(1), a.Attributes.Nlink, "the whiteout of the top layer unlinks /bin/b")
+	assert.Nil(t, s.Lookup(context.Background(), "sha256:second", "/etc/hosts"))
+
+	// the second image renumbers and hides the files of the shared layer in its own index only
+	for _, path := range paths {
+		node := s.Lookup(context.Background(), "sha256:first", path)
+		require.NotNil(t, node, path)
+		assert.Equal(t, first[path], *node, path)
+	}
+}
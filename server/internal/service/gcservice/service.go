@@ -0,0 +1,486 @@
+package gcservice
+
+import (
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/baepo-cloud/viscaufs-server/internal/config"
+	"github.com/baepo-cloud/viscaufs-server/internal/types"
+	"gorm.io/gorm"
+)
+
+const (
+	// usageFlushInterval is the delay between two writes of the last use of the images
+	usageFlushInterval = 30 * time.Second
+	// orphanLayerGracePeriod protects the layers just stored by a preparation that did not link them to its image yet
+	orphanLayerGracePeriod = time.Hour
+)
+
+// collectableStates are the states of the images no preparation is working on
+var collectableStates = []types.ImageState{types.ImageStateReady, types.ImageStateFailed}
+
+type Service struct {
+	basePath  string
+	interval  time.Duration
+	retention time.Duration
+
+	db                 *gorm.DB
+	fsIndexService     types.FileSystemIndexService
+	fileHandlerService types.FileHandlerService
+	logger             *slog.Logger
+
+	usageMutex sync.Mutex
+	usage      map[string]time.Time
+
+	// collectMutex serializes the collections and the evictions, it guards the selections of the evictions not run
+	// yet
+	collectMutex   sync.Mutex
+	selectedImages map[string]struct{}
+	selectedLayers map[string]struct{}
+
+	// pinMutex is held while a layer is deleted, a layer is either pinned before its deletion starts or pinned once
+	// it is gone
+	pinMutex sync.Mutex
+	pinned   map[string]int
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewService creates a new garbage collector service
+func NewService(cfg *config.Config, db *gorm.DB, fsIndexSvc types.FileSystemIndexService, fhSvc types.FileHandlerService) *Service {
+	return &Service{
+		basePath:           cfg.ImageDir,
+		interval:           cfg.GCInterval,
+		retention:          time.Duration(cfg.ImageRetentionDays) * 24 * time.Hour,
+		db:                 db,
+		fsIndexService:     fsIndexSvc,
+		fileHandlerService: fhSvc,
+		logger:             slog.New(slog.NewTextHandler(log.Writer(), nil)).With("service", "gc"),
+		usage:              make(map[string]time.Time),
+		selectedImages:     make(map[string]struct{}),
+		selectedLayers:     make(map[string]struct{}),
+		pinned:             make(map[string]int),
+		stop:               make(chan struct{}),
+		done:               make(chan struct{}),
+	}
+}
+
+func (s *Service) MarkUsed(imageDigest string) {
+	if imageDigest == "" {
+		return
+	}
+
+	now := time.Now().UTC()
+	s.usageMutex.Lock()
+	s.usage[imageDigest] = now
+	s.usageMutex.Unlock()
+}
+
+// Start runs the usage flushes and, when an interval is configured, the collections in the background
+func (s *Service) Start() {
+	go s.run()
+}
+
+// Stop stops the background loop and writes the pending usages
+func (s *Service) Stop() {
+	close(s.stop)
+	<-s.done
+
+	if err := s.flushUsage(); err != nil {
+		s.logger.Error("failed to flush image usage", slog.Any("error", err))
+	}
+}
+
+func (s *Service) run() {
+	defer close(s.done)
+
+	flushTicker := time.NewTicker(usageFlushInterval)
+	defer flushTicker.Stop()
+
+	var collect <-chan time.Time
+	if s.interval > 0 {
+		collectTicker := time.NewTicker(s.interval)
+		defer collectTicker.Stop()
+		collect = collectTicker.C
+	}
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-flushTicker.C:
+			if err := s.flushUsage(); err != nil {
+				s.logger.Error("failed to flush image usage", slog.Any("error", err))
+			}
+		case <-collect:
+			if err := s.Collect(); err != nil {
+				s.logger.Error("garbage collection failed", slog.Any("error", err))
+			}
+		}
+	}
+}
+
+// flushUsage writes the last use of the images marked since the previous flush
+func (s *Service) flushUsage() error {
+	s.usageMutex.Lock()
+	usage := s.usage
+	s.usage = make(map[string]time.Time)
+	s.usageMutex.Unlock()
+
+	if len(usage) == 0 {
+		return nil
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		for digest, usedAt := range usage {
+			err := tx.Model(&types.Image{}).
+				Where("digest = ? and used_at < ?", digest, usedAt).
+				Update("used_at", usedAt).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		// keep the usages for the next flush, unless the image was used again in the meantime
+		s.usageMutex.Lock()
+		for digest, usedAt := range usage {
+			if current, ok := s.usage[digest]; !ok || current.Before(usedAt) {
+				s.usage[digest] = usedAt
+			}
+		}
+		s.usageMutex.Unlock()
+
+		return fmt.Errorf("failed to update used_at: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) PinLayer(layerDigest string) func() {
+	s.pinMutex.Lock()
+	s.pinned[layerDigest]++
+	s.pinMutex.Unlock()
+
+	return func() {
+		s.pinMutex.Lock()
+		defer s.pinMutex.Unlock()
+
+		if s.pinned[layerDigest]--; s.pinned[layerDigest] <= 0 {
+			delete(s.pinned, layerDigest)
+		}
+	}
+}
+
+// isPinned reports whether a preparation pinned the layer
+func (s *Service) isPinned(layerDigest string) bool {
+	s.pinMutex.Lock()
+	defer s.pinMutex.Unlock()
+
+	return s.pinned[layerDigest] > 0
+}
+
+// usedSince reports whether the image was marked used after cutoff and the usage is not flushed yet
+func (s *Service) usedSince(imageDigest string, cutoff time.Time) bool {
+	s.usageMutex.Lock()
+	defer s.usageMutex.Unlock()
+
+	usedAt, ok := s.usage[imageDigest]
+	return ok && usedAt.After(cutoff)
+}
+
+func (s *Service) Collect() error {
+	s.collectMutex.Lock()
+	defer s.collectMutex.Unlock()
+
+	if err := s.flushUsage(); err != nil {
+		return err
+	}
+
+	if err := s.collectImages(); err != nil {
+		return err
+	}
+
+	return s.collectLayers()
+}
+
+// collectImages deletes the prepared or failed images unused since the retention, images with open files are kept
+func (s *Service) collectImages() error {
+	cutoff := time.Now().UTC().Add(-s.retention)
+
+	var images []types.Image
+	err := s.db.Select("id", "digest", "used_at").
+		Where("state in ? and used_at < ?", collectableStates, cutoff).
+		Find(&images).Error
+	if err != nil {
+		return fmt.Errorf("failed to find unused images: %w", err)
+	}
+
+	for _, image := range images {
+		deleted, err := s.deleteImage(image)
+		if err != nil {
+			return err
+		}
+		if deleted {
+			s.logger.Info("unused image deleted", slog.String("digest", image.Digest))
+		}
+	}
+
+	return nil
+}
+
+// collectLayers deletes the layers no image references anymore along with their content
+func (s *Service) collectLayers() error {
+	var layers []types.Layer
+	err := s.db.Select("id", "digest", "created_at").
+		Where("id not in (?)", s.db.Model(&types.ImageLayer{}).Select("layer_id")).
+		Find(&layers).Error
+	if err != nil {
+		return fmt.Errorf("failed to find orphan layers: %w", err)
+	}
+
+	cutoff := time.Now().Add(-orphanLayerGracePeriod)
+	for _, layer := range layers {
+		if layer.CreatedAt.After(cutoff) {
+			continue
+		}
+
+		deleted, err := s.deleteLayer(layer)
+		if err != nil {
+			return err
+		}
+		if deleted {
+			s.logger.Info("orphan layer deleted", slog.String("layer_digest", layer.Digest))
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) SelectEviction(size int64, keepImageDigest string) (*types.Eviction, error) {
+	s.collectMutex.Lock()
+	defer s.collectMutex.Unlock()
+
+	// the recent usages decide which images are the least recently used
+	if err := s.flushUsage(); err != nil {
+		return nil, err
+	}
+
+	var keep types.Image
+	err := s.db.Select("layer_digests").Where("digest = ?", keepImageDigest).Limit(1).Find(&keep).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find image %s: %w", keepImageDigest, err)
+	}
+	keepLayers := make(map[string]struct{}, len(keep.LayerDigests))
+	for _, digest := range keep.LayerDigests {
+		keepLayers[digest] = struct{}{}
+	}
+
+	var layers []types.Layer
+	if err := s.db.Select("id", "digest", "size", "created_at").Order("created_at").Find(&layers).Error; err != nil {
+		return nil, fmt.Errorf("failed to find layers: %w", err)
+	}
+	var links []types.ImageLayer
+	if err := s.db.Select("image_id", "layer_id").Find(&links).Error; err != nil {
+		return nil, fmt.Errorf("failed to find image layers: %w", err)
+	}
+
+	// references counts the images referencing each layer, imageLayers holds the layers of each image
+	references := make(map[string]int, len(layers))
+	imageLayers := make(map[string][]string)
+	for _, link := range links {
+		references[link.LayerID]++
+		imageLayers[link.ImageID] = append(imageLayers[link.ImageID], link.LayerID)
+	}
+
+	eviction := &types.Eviction{}
+	evictable := func(layer types.Layer) bool {
+		_, kept := keepLayers[layer.Digest]
+		_, selected := s.selectedLayers[layer.Digest]
+		return !kept && !selected && !s.isPinned(layer.Digest)
+	}
+
+	// the orphan layers free space without losing any image
+	cutoff := time.Now().Add(-orphanLayerGracePeriod)
+	layersByID := make(map[string]types.Layer, len(layers))
+	for _, layer := range layers {
+		layersByID[layer.ID] = layer
+		if eviction.Size >= size || references[layer.ID] > 0 || layer.CreatedAt.After(cutoff) || !evictable(layer) {
+			continue
+		}
+		eviction.Layers = append(eviction.Layers, layer)
+		eviction.Size += layer.Size
+	}
+
+	if eviction.Size < size {
+		var images []types.Image
+		err = s.db.Select("id", "digest", "used_at").
+			Where("state in ? and digest != ?", collectableStates, keepImageDigest).
+			Order("used_at").
+			Find(&images).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to find images to evict: %w", err)
+		}
+
+		for _, image := range images {
+			if eviction.Size >= size {
+				break
+			}
+			if _, ok := s.selectedImages[image.Digest]; ok || s.fileHandlerService.HasOpenFiles(image.Digest) {
+				continue
+			}
+			eviction.Images = append(eviction.Images, image)
+
+			// the layers shared with other images stay
+			for _, layerID := range imageLayers[image.ID] {
+				references[layerID]--
+				layer, ok := layersByID[layerID]
+				if !ok || references[layerID] > 0 || !evictable(layer) {
+					continue
+				}
+				eviction.Layers = append(eviction.Layers, layer)
+				eviction.Size += layer.Size
+			}
+		}
+	}
+
+	if eviction.Size < size {
+		return nil, fmt.Errorf("%w: %d bytes needed, %d bytes could be freed", types.ErrDiskQuotaExceeded, size, eviction.Size)
+	}
+
+	for _, image := range eviction.Images {
+		s.selectedImages[image.Digest] = struct{}{}
+	}
+	for _, layer := range eviction.Layers {
+		s.selectedLayers[layer.Digest] = struct{}{}
+	}
+
+	return eviction, nil
+}
+
+func (s *Service) Evict(eviction *types.Eviction) (int64, error) {
+	s.collectMutex.Lock()
+	defer s.collectMutex.Unlock()
+
+	defer func() {
+		for _, image := range eviction.Images {
+			delete(s.selectedImages, image.Digest)
+		}
+		for _, layer := range eviction.Layers {
+			delete(s.selectedLayers, layer.Digest)
+		}
+	}()
+
+	for _, image := range eviction.Images {
+		deleted, err := s.deleteImage(image)
+		if err != nil {
+			return 0, err
+		}
+		if deleted {
+			s.logger.Info("image evicted", slog.String("digest", image.Digest))
+		}
+	}
+
+	// the layers of the images kept meanwhile are still referenced and stay
+	var freed int64
+	for _, layer := range eviction.Layers {
+		deleted, err := s.deleteLayer(layer)
+		if err != nil {
+			return freed, err
+		}
+		if deleted {
+			freed += layer.Size
+			s.logger.Info("layer evicted", slog.String("layer_digest", layer.Digest), slog.Int64("size", layer.Size))
+		}
+	}
+
+	return freed, nil
+}
+
+// deleteImage deletes the image unless files of it are open, it was used since it was selected or a preparation
+// started on it. It reports whether the image was deleted.
+func (s *Service) deleteImage(image types.Image) (bool, error) {
+	if s.usedSince(image.Digest, image.UsedAt) || s.fileHandlerService.HasOpenFiles(image.Digest) {
+		return false, nil
+	}
+
+	// drop the index first so no new file of the image can be opened, a file opened in the meantime keeps the image
+	s.fsIndexService.RemoveImage(image.Digest)
+	if s.usedSince(image.Digest, image.UsedAt) || s.fileHandlerService.HasOpenFiles(image.Digest) {
+		return false, nil
+	}
+
+	deleted := false
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		err := tx.Model(&types.Image{}).
+			Where("id = ? and state in ? and used_at <= ?", image.ID, collectableStates, image.UsedAt).
+			Count(&count).Error
+		if err != nil || count == 0 {
+			return err
+		}
+
+		if err := tx.Where("image_id = ?", image.ID).Delete(&types.ImageLayer{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("image_digest = ?", image.Digest).Delete(&types.FileStat{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("image_digest = ?", image.Digest).Delete(&types.StartupBlock{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("id = ?", image.ID).Delete(&types.Image{}).Error; err != nil {
+			return err
+		}
+
+		deleted = true
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete image %s: %w", image.Digest, err)
+	}
+
+	if deleted {
+		s.fileHandlerService.ForgetImage(image.Digest)
+	}
+
+	return deleted, nil
+}
+
+// deleteLayer deletes the layer and its content unless an image references it or a preparation pinned it, it reports
+// whether it was deleted
+func (s *Service) deleteLayer(layer types.Layer) (bool, error) {
+	// a preparation pinning the layer meanwhile waits for its content to be gone and stores it again
+	s.pinMutex.Lock()
+	defer s.pinMutex.Unlock()
+
+	if s.pinned[layer.Digest] > 0 {
+		return false, nil
+	}
+
+	// the row goes first, a preparation needing the layer again downloads it instead of using a removed directory
+	result := s.db.
+		Where("id = ? and id not in (?)", layer.ID, s.db.Model(&types.ImageLayer{}).Select("layer_id")).
+		Delete(&types.Layer{})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to delete layer %s: %w", layer.Digest, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	s.fsIndexService.RemoveLayer(layer.Digest)
+	s.fileHandlerService.CloseLayerFiles(layer.Digest)
+	if err := os.RemoveAll(filepath.Join(s.basePath, "layers", layer.Digest)); err != nil {
+		return false, fmt.Errorf("failed to remove layer %s: %w", layer.Digest, err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,318 @@
+package gcservice
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/baepo-cloud/viscaufs-server/internal/config"
+	"github.com/baepo-cloud/viscaufs-server/internal/fxutil"
+	"github.com/baepo-cloud/viscaufs-server/internal/service/fsindexservice"
+	"github.com/baepo-cloud/viscaufs-server/internal/service/progressservice"
+	"github.com/baepo-cloud/viscaufs-server/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// openFiles holds the images with open files
+type openFiles map[string]bool
+
+// fileHandler is a file handler service only reporting the images with open files, the collector uses no other
+// method of the service
+type fileHandler struct {
+	types.FileHandlerService
+	open openFiles
+}
+
+func (f fileHandler) HasOpenFiles(imageDigest string) bool { return f.open[imageDigest] }
+func (f fileHandler) CloseLayerFiles(string)               {}
+func (f fileHandler) ForgetImage(string)                   {}
+
+type harness struct {
+	service  *Service
+	db       *gorm.DB
+	imageDir string
+}
+
+func newHarness(t *testing.T, open openFiles) *harness {
+	t.Helper()
+
+	cfg := &config.Config{
+		SqliteDir:          t.TempDir(),
+		ImageDir:           t.TempDir(),
+		ImageRetentionDays: 7,
+	}
+
+	db, err := fxutil.ProvideGORM(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		sqlDB, err := db.DB()
+		if err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	fsIndex := fsindexservice.NewService(db, progressservice.NewService())
+	return &harness{
+		service:  NewService(cfg, db, fsIndex, fileHandler{open: open}),
+		db:       db,
+		imageDir: cfg.ImageDir,
+	}
+}
+
+// layer stores a layer with its content directory
+func (h *harness) layer(t *testing.T, digest string, createdAt time.Time) *types.Layer {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(h.imageDir, "layers", digest, "content"), 0755))
+
+	layer := &types.Layer{ID: "layer-" + digest, Digest: digest, CreatedAt: createdAt}
+	require.NoError(t, h.db.Create(layer).Error)
+	return layer
+}
+
+// image stores an image referencing the layers
+func (h *harness) image(t *testing.T, digest string, state types.ImageState, usedAt time.Time, layers ...*types.Layer) {
+	t.Helper()
+
+	image := &types.Image{ID: "image-" + digest, Digest: digest, State: state, UsedAt: usedAt}
+	require.NoError(t, h.db.Create(image).Error)
+
+	for position, layer := range layers {
+		require.NoError(t, h.db.Create(&types.ImageLayer{ImageID: image.ID, LayerID: layer.ID, Position: position}).Error)
+	}
+}
+
+func (h *harness) imageExists(t *testing.T, digest string) bool {
+	t.Helper()
+
+	var count int64
+	require.NoError(t, h.db.Model(&types.Image{}).Where("digest = ?", digest).Count(&count).Error)
+	return count > 0
+}
+
+func (h *harness) layerExists(t *testing.T, digest string) bool {
+	t.Helper()
+
+	var count int64
+	require.NoError(t, h.db.Model(&types.Layer{}).Where("digest = ?", digest).Count(&count).Error)
+
+	_, err := os.Stat(filepath.Join(h.imageDir, "layers", digest))
+	assert.Equal(t, count > 0, err == nil, "layer row and directory of %s disagree", digest)
+
+	return count > 0
+}
+
+func TestCollect(t *testing.T) {
+	now := time.Now().UTC()
+	old := now.Add(-30 * 24 * time.Hour)
+
+	h := newHarness(t, openFiles{"sha256:open": true})
+
+	shared := h.layer(t, "sha256:shared", old)
+	unused := h.layer(t, "sha256:unused", old)
+	opened := h.layer(t, "sha256:opened", old)
+	recent := h.layer(t, "sha256:recent", old)
+	h.layer(t, "sha256:orphan", old)
+	h.layer(t, "sha256:just-stored", now)
+
+	h.image(t, "sha256:old", types.ImageStateReady, old, shared, unused)
+	h.image(t, "sha256:failed", types.ImageStateFailed, old)
+	h.image(t, "sha256:open", types.ImageStateReady, old, opened)
+	h.image(t, "sha256:recent", types.ImageStateReady, now, shared, recent)
+	h.image(t, "sha256:marked", types.ImageStateReady, old)
+	h.image(t, "sha256:preparing", types.ImageStatePartial, old)
+
+	h.service.MarkUsed("sha256:marked")
+
+	stat := &types.FileStat{ImageDigest: "sha256:old", Path: "/bin/sh", FirstAccessedAt: old}
+	require.NoError(t, h.db.Create(stat).Error)
+
+	require.NoError(t, h.service.Collect())
+
+	assert.False(t, h.imageExists(t, "sha256:old"))
+	assert.False(t, h.imageExists(t, "sha256:failed"))
+	assert.True(t, h.imageExists(t, "sha256:open"), "images with open files are kept")
+	assert.True(t, h.imageExists(t, "sha256:recent"))
+	assert.True(t, h.imageExists(t, "sha256:marked"), "images marked used are kept")
+	assert.True(t, h.imageExists(t, "sha256:preparing"), "images being prepared are kept")
+
+	assert.False(t, h.layerExists(t, "sha256:unused"))
+	assert.False(t, h.layerExists(t, "sha256:orphan"))
+	assert.True(t, h.layerExists(t, "sha256:shared"), "layers still referenced are kept")
+	assert.True(t, h.layerExists(t, "sha256:opened"))
+	assert.True(t, h.layerExists(t, "sha256:recent"))
+	assert.True(t, h.layerExists(t, "sha256:just-stored"), "layers not linked yet are kept")
+
+	var links int64
+	require.NoError(t, h.db.Model(&types.ImageLayer{}).Where("image_id = ?", "image-sha256:old").Count(&links).Error)
+	assert.Zero(t, links)
+
+	var stats int64
+	require.NoError(t, h.db.Model(&types.FileStat{}).Where("image_digest = ?", "sha256:old").Count(&stats).Error)
+	assert.Zero(t, stats, "the file statistics go with the image")
+}
+
+func TestMarkUsedIsFlushed(t *testing.T) {
+	old := time.Now().UTC().Add(-30 * 24 * time.Hour)
+
+	h := newHarness(t, openFiles{})
+	h.image(t, "sha256:image", types.ImageStateReady, old)
+
+	before := time.Now().UTC()
+	h.service.MarkUsed("sha256:image")
+	h.service.MarkUsed("sha256:unknown")
+	require.NoError(t, h.service.flushUsage())
+
+	var image types.Image
+	require.NoError(t, h.db.Where("digest = ?", "sha256:image").First(&image).Error)
+	assert.False(t, image.UsedAt.Before(before.Truncate(time.Second)))
+	assert.Empty(t, h.service.usage)
+
+	require.NoError(t, h.service.Collect())
+	assert.True(t, h.imageExists(t, "sha256:image"))
+}
+
+func TestEvict(t *testing.T) {
+	now := time.Now().UTC()
+	old := now.Add(-30 * 24 * time.Hour)
+
+	h := newHarness(t, openFiles{"sha256:open": true})
+
+	sized := func(digest string, size int64) *types.Layer {
+		layer := h.layer(t, digest, old)
+		require.NoError(t, h.db.Model(layer).Update("size", size).Error)
+		layer.Size = size
+		return layer
+	}
+
+	shared := sized("sha256:shared", 1000)
+	h.image(t, "sha256:oldest", types.ImageStateReady, now.Add(-3*time.Hour), sized("sha256:oldest-layer", 100), shared)
+	h.image(t, "sha256:open", types.ImageStateReady, now.Add(-2*time.Hour), sized("sha256:open-layer", 100))
+	h.image(t, "sha256:older", types.ImageStateReady, now.Add(-time.Hour), sized("sha256:older-layer", 100))
+	h.image(t, "sha256:recent", types.ImageStateReady, now, sized("sha256:recent-layer", 100))
+	h.image(t, "sha256:keep", types.ImageStatePartial, old, shared)
+	sized("sha256:orphan", 10)
+
+	// evict selects and deletes an eviction of size bytes
+	evict := func(size int64) (int64, error) {
+		eviction, err := h.service.SelectEviction(size, "sha256:keep")
+		if err != nil {
+			return 0, err
+		}
+		return h.service.Evict(eviction)
+	}
+
+	// the orphan layer goes first, then the least recently used images without open files
+	freed, err := evict(150)
+	require.NoError(t, err)
+	assert.Equal(t, int64(210), freed)
+
+	assert.False(t, h.layerExists(t, "sha256:orphan"))
+	assert.False(t, h.imageExists(t, "sha256:oldest"))
+	assert.False(t, h.layerExists(t, "sha256:oldest-layer"))
+	assert.False(t, h.imageExists(t, "sha256:older"))
+	assert.True(t, h.imageExists(t, "sha256:open"))
+	assert.True(t, h.imageExists(t, "sha256:recent"))
+	assert.True(t, h.layerExists(t, "sha256:shared"), "layers of the kept image stay")
+
+	// nothing is deleted when the open and kept images would have to go
+	_, err = evict(1000)
+	assert.ErrorIs(t, err, types.ErrDiskQuotaExceeded)
+	assert.True(t, h.imageExists(t, "sha256:recent"))
+	assert.True(t, h.layerExists(t, "sha256:recent-layer"))
+	assert.True(t, h.imageExists(t, "sha256:open"))
+	assert.True(t, h.imageExists(t, "sha256:keep"))
+
+	freed, err = evict(100)
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), freed)
+	assert.False(t, h.imageExists(t, "sha256:recent"))
+}
+
+func TestSelectEvictionSkipsTheSelectedImages(t *testing.T) {
+	now := time.Now().UTC()
+	old := now.Add(-30 * 24 * time.Hour)
+
+	h := newHarness(t, openFiles{})
+	sized := func(digest string, size int64) *types.Layer {
+		layer := h.layer(t, digest, old)
+		require.NoError(t, h.db.Model(layer).Update("size", size).Error)
+		layer.Size = size
+		return layer
+	}
+
+	h.image(t, "sha256:oldest", types.ImageStateReady, now.Add(-2*time.Hour), sized("sha256:oldest-layer", 100))
+	h.image(t, "sha256:older", types.ImageStateReady, now.Add(-time.Hour), sized("sha256:older-layer", 100))
+
+	first, err := h.service.SelectEviction(100, "sha256:keep")
+	require.NoError(t, err)
+	require.Len(t, first.Images, 1)
+	assert.Equal(t, "sha256:oldest", first.Images[0].Digest)
+
+	// a concurrent reservation does not count on the space the first one is freeing
+	second, err := h.service.SelectEviction(100, "sha256:keep")
+	require.NoError(t, err)
+	require.Len(t, second.Images, 1)
+	assert.Equal(t, "sha256:older", second.Images[0].Digest)
+
+	_, err = h.service.SelectEviction(100, "sha256:keep")
+	assert.ErrorIs(t, err, types.ErrDiskQuotaExceeded)
+
+	// an image used since its selection is kept
+	h.service.MarkUsed("sha256:older")
+	freed, err := h.service.Evict(second)
+	require.NoError(t, err)
+	assert.Zero(t, freed)
+	assert.True(t, h.imageExists(t, "sha256:older"))
+	assert.True(t, h.layerExists(t, "sha256:older-layer"))
+
+	freed, err = h.service.Evict(first)
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), freed)
+	assert.False(t, h.imageExists(t, "sha256:oldest"))
+}
+
+func TestEvictKeepsPinnedLayers(t *testing.T) {
+	now := time.Now().UTC()
+	old := now.Add(-30 * 24 * time.Hour)
+
+	h := newHarness(t, openFiles{})
+	reused := h.layer(t, "sha256:reused", old)
+	require.NoError(t, h.db.Model(reused).Update("size", 10).Error)
+	h.image(t, "sha256:evicted", types.ImageStateReady, old, reused)
+	h.image(t, "sha256:preparing", types.ImageStatePartial, now)
+
+	// the preparation found the stored layer, the eviction was selected before it pinned it
+	eviction, err := h.service.SelectEviction(10, "sha256:other")
+	require.NoError(t, err)
+	unpin := h.service.PinLayer(reused.Digest)
+	_, err = h.service.Evict(eviction)
+	require.NoError(t, err)
+
+	assert.False(t, h.imageExists(t, "sha256:evicted"))
+	assert.True(t, h.layerExists(t, "sha256:reused"), "pinned layers are kept")
+
+	require.NoError(t, h.db.Create(&types.ImageLayer{ImageID: "image-sha256:preparing", LayerID: reused.ID}).Error)
+	unpin()
+
+	_, err = h.service.Evict(&types.Eviction{Layers: []types.Layer{*reused}})
+	require.NoError(t, err)
+	assert.True(t, h.layerExists(t, "sha256:reused"), "the layer is referenced by the prepared image")
+
+	// pinned layers are not selected, once released a layer no image references is selected again
+	orphan := h.layer(t, "sha256:orphan", old)
+	require.NoError(t, h.db.Model(orphan).Update("size", 10).Error)
+	unpin = h.service.PinLayer(orphan.Digest)
+	_, err = h.service.SelectEviction(10, "sha256:other")
+	assert.ErrorIs(t, err, types.ErrDiskQuotaExceeded)
+	unpin()
+
+	eviction, err = h.service.SelectEviction(10, "sha256:other")
+	require.NoError(t, err)
+	_, err = h.service.Evict(eviction)
+	require.NoError(t, err)
+	assert.False(t, h.layerExists(t, "sha256:orphan"))
+}
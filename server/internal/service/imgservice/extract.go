@@ -0,0 +1,290 @@
+package imgservice
+
+import (
+	"archive/tar"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/baepo-cloud/viscaufs-server/internal/types"
+	"github.com/baepo-cloud/viscaufs/common/fsindex"
+	"golang.org/x/sys/unix"
+)
+
+// extractLayer streams an uncompressed layer tar into contentPath and builds its filesystem index in the same pass.
+//
+// The index holds the exact metadata of every entry (taken from the tar headers), the extracted copy only needs to
+// provide the file contents: it always keeps owner access so the server can read it without root privileges,
+// ownership is only reproduced on disk when running as root.
+func extractLayer(r io.Reader, contentPath string) (*fsindex.Index, error) {
+	var (
+		index   = fsindex.NewFSIndex()
+		tr      = tar.NewReader(r)
+		dirs    []*tar.Header
+		indexed = map[string]struct{}{}
+	)
+
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read layer entry: %w", err)
+		}
+
+		relPath, err := sanitizeEntryPath(hdr.Name)
+		if err != nil {
+			return nil, err
+		}
+		if relPath == "/" {
+			continue
+		}
+		hdr.Name = relPath
+
+		// never write through a symlink extracted earlier, it could point anywhere on the host
+		if err := ensureNoSymlinkParents(contentPath, relPath); err != nil {
+			return nil, err
+		}
+
+		target := filepath.Join(contentPath, relPath)
+		if err := extractEntry(tr, hdr, contentPath, target); err != nil {
+			return nil, fmt.Errorf("failed to extract %s: %w", relPath, err)
+		}
+
+		info, err := os.Lstat(target)
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat extracted %s: %w", relPath, err)
+		}
+
+		index.AddTarEntry(hdr, info)
+		indexed[relPath] = struct{}{}
+
+		if hdr.Typeflag == tar.TypeDir {
+			dirs = append(dirs, hdr)
+		}
+	}
+
+	// directories created implicitly by their children have no tar entry, index them from disk
+	for relPath := range indexed {
+		for parent := filepath.Dir(relPath); parent != "/"; parent = filepath.Dir(parent) {
+			if _, ok := indexed[parent]; ok {
+				break
+			}
+
+			info, err := os.Lstat(filepath.Join(contentPath, parent))
+			if err != nil {
+				return nil, fmt.Errorf("failed to stat implicit directory %s: %w", parent, err)
+			}
+			index.AddFileInfo(parent, info)
+			indexed[parent] = struct{}{}
+		}
+	}
+
+	// restore directory permissions and timestamps last, extracting their children modified them
+	for i := len(dirs) - 1; i >= 0; i-- {
+		target := filepath.Join(contentPath, dirs[i].Name)
+
+		// a later entry may have replaced the directory, chmod follows symlinks and must never reach the host
+		info, err := os.Lstat(target)
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat %s: %w", dirs[i].Name, err)
+		}
+		if !info.IsDir() {
+			continue
+		}
+
+		if err := os.Chmod(target, diskMode(dirs[i])); err != nil {
+			return nil, fmt.Errorf("failed to chmod %s: %w", dirs[i].Name, err)
+		}
+		if err := setTimes(target, dirs[i]); err != nil {
+			return nil, fmt.Errorf("failed to set times of %s: %w", dirs[i].Name, err)
+		}
+	}
+
+	return index, nil
+}
+
+// extractEntry writes a single tar entry at target
+func extractEntry(tr *tar.Reader, hdr *tar.Header, contentPath, target string) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	// a later entry replaces whatever an earlier entry of the same layer created, except directories that merge
+	if info, err := os.Lstat(target); err == nil && !(info.IsDir() && hdr.Typeflag == tar.TypeDir) {
+		if err := os.RemoveAll(target); err != nil {
+			return err
+		}
+	}
+
+	switch hdr.Typeflag {
+	case tar.TypeDir:
+		// keep the directory writable until its children are extracted
+		if err := os.MkdirAll(target, 0700); err != nil {
+			return err
+		}
+		return setXattrs(target, hdr)
+
+	case tar.TypeReg, tar.TypeRegA:
+		f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC|unix.O_NOFOLLOW, 0600)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(f, tr); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+
+	case tar.TypeSymlink:
+		if err := os.Symlink(hdr.Linkname, target); err != nil {
+			return err
+		}
+
+	case tar.TypeLink:
+		linkPath, err := sanitizeEntryPath(hdr.Linkname)
+		if err != nil {
+			return err
+		}
+		if err := ensureNoSymlinkParents(contentPath, linkPath); err != nil {
+			return err
+		}
+
+		// link(2) does not follow a symlink as last component, the link can only target a file of the layer
+		if err := os.Link(filepath.Join(contentPath, linkPath), target); err != nil {
+			return err
+		}
+		// the link shares the inode of its target, which already has its metadata
+		return nil
+
+	default:
+		// device nodes, fifos and unsupported entries only exist in the index, they are materialized as empty
+		// files so a client can never make the server open a host device or block on a fifo
+		f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC|unix.O_NOFOLLOW, 0600)
+		if err != nil {
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+
+	if hdr.Typeflag != tar.TypeSymlink {
+		if err := os.Chmod(target, diskMode(hdr)); err != nil {
+			return err
+		}
+	}
+
+	if err := lchown(target, hdr); err != nil {
+		return err
+	}
+
+	if err := setXattrs(target, hdr); err != nil {
+		return err
+	}
+
+	return setTimes(target, hdr)
+}
+
+// sanitizeEntryPath returns the entry path relative to the layer root (with a leading slash),
+// entries containing ".." are rejected whether or not they would escape the root
+func sanitizeEntryPath(name string) (string, error) {
+	for _, part := range strings.Split(filepath.ToSlash(name), "/") {
+		if part == ".." {
+			return "", fmt.Errorf("%w: %q contains \"..\"", types.ErrUnsafeLayerEntry, name)
+		}
+	}
+
+	return filepath.Clean("/" + name), nil
+}
+
+// ensureNoSymlinkParents checks that every existing parent directory of relPath inside contentPath is a real directory
+func ensureNoSymlinkParents(contentPath, relPath string) error {
+	current := contentPath
+	for _, part := range strings.Split(strings.Trim(filepath.Dir(relPath), "/"), "/") {
+		if part == "" {
+			continue
+		}
+
+		current = filepath.Join(current, part)
+		info, err := os.Lstat(current)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if info.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("%w: %q goes through the symlink %q", types.ErrUnsafeLayerEntry, relPath, strings.TrimPrefix(current, contentPath))
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("%w: %q has a parent that is not a directory", types.ErrUnsafeLayerEntry, relPath)
+		}
+	}
+
+	return nil
+}
+
+// diskMode is the mode of the extracted copy: the permission bits of the entry with owner access always granted,
+// set-id and sticky bits are dropped since they are only meaningful inside the container
+func diskMode(hdr *tar.Header) os.FileMode {
+	mode := os.FileMode(hdr.Mode) & os.ModePerm
+	if hdr.Typeflag == tar.TypeDir {
+		return mode | 0700
+	}
+	return mode | 0600
+}
+
+// lchown reproduces the entry ownership on disk when running as root
+func lchown(target string, hdr *tar.Header) error {
+	if os.Geteuid() != 0 {
+		return nil
+	}
+	return os.Lchown(target, hdr.Uid, hdr.Gid)
+}
+
+// setXattrs reproduces the PAX extended attributes on disk, attributes the filesystem or our privileges
+// do not allow are skipped
+func setXattrs(target string, hdr *tar.Header) error {
+	for key, value := range hdr.PAXRecords {
+		name, ok := strings.CutPrefix(key, fsindex.XattrPAXPrefix)
+		if !ok {
+			continue
+		}
+
+		err := unix.Lsetxattr(target, name, []byte(value), 0)
+		if err != nil && !errors.Is(err, unix.ENOTSUP) && !errors.Is(err, unix.EPERM) && !errors.Is(err, unix.EACCES) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setTimes sets the access and modification times of the entry without following symlinks
+func setTimes(target string, hdr *tar.Header) error {
+	atime := hdr.AccessTime
+	if atime.IsZero() {
+		atime = hdr.ModTime
+	}
+
+	return unix.UtimesNanoAt(unix.AT_FDCWD, target, []unix.Timespec{
+		unix.NsecToTimespec(timeToNsec(atime)),
+		unix.NsecToTimespec(timeToNsec(hdr.ModTime)),
+	}, unix.AT_SYMLINK_NOFOLLOW)
+}
+
+func timeToNsec(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
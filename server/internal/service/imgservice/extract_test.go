@@ -0,0 +1,209 @@
+package imgservice
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/baepo-cloud/viscaufs-server/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type tarEntry struct {
+	header  tar.Header
+	content string
+}
+
+func buildLayerTar(t *testing.T, entries []tarEntry) *bytes.Buffer {
+	t.Helper()
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, entry := range entries {
+		hdr := entry.header
+		hdr.Size = int64(len(entry.content))
+		if hdr.Format == tar.FormatUnknown {
+			hdr.Format = tar.FormatPAX
+		}
+		require.NoError(t, tw.WriteHeader(&hdr))
+		if entry.content != "" {
+			_, err := tw.Write([]byte(entry.content))
+			require.NoError(t, err)
+		}
+	}
+	require.NoError(t, tw.Close())
+
+	return &buf
+}
+
+func TestExtractLayer(t *testing.T) {
+	mtime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	layer := buildLayerTar(t, []tarEntry{
+		{header: tar.Header{Typeflag: tar.TypeDir, Name: "etc/", Mode: 0555, Uid: 0, Gid: 0, ModTime: mtime}},
+		{header: tar.Header{Typeflag: tar.TypeReg, Name: "etc/passwd", Mode: 0400, Uid: 0, Gid: 42, ModTime: mtime}, content: "root:x:0:0"},
+		{header: tar.Header{Typeflag: tar.TypeLink, Name: "etc/passwd-", Linkname: "etc/passwd", ModTime: mtime}},
+		{header: tar.Header{Typeflag: tar.TypeSymlink, Name: "etc/localtime", Linkname: "/usr/share/zoneinfo/UTC", ModTime: mtime}},
+		{header: tar.Header{Typeflag: tar.TypeReg, Name: "usr/bin/ping", Mode: 04755, Uid: 1000, Gid: 1000, ModTime: mtime,
+			PAXRecords: map[string]string{"SCHILY.xattr.user.viscaufs": "1"}}, content: "\x7fELF"},
+		{header: tar.Header{Typeflag: tar.TypeChar, Name: "dev/null", Mode: 0666, Devmajor: 1, Devminor: 3, ModTime: mtime}},
+		{header: tar.Header{Typeflag: tar.TypeFifo, Name: "run/fifo", Mode: 0644, ModTime: mtime}},
+	})
+
+	contentPath := t.TempDir()
+	index, err := extractLayer(layer, contentPath)
+	require.NoError(t, err)
+
+	passwd, err := index.LookupPath("/etc/passwd")
+	require.NoError(t, err)
+	assert.Equal(t, uint32(syscall.S_IFREG|0400), passwd.Attributes.Mode)
+	assert.Equal(t, uint32(42), passwd.Attributes.Owner.Gid)
+	assert.Equal(t, int64(10), passwd.Attributes.Size)
+	assert.Equal(t, mtime.Unix(), passwd.Attributes.Mtime)
+
+	content, err := os.ReadFile(filepath.Join(contentPath, "etc/passwd"))
+	require.NoError(t, err)
+	assert.Equal(t, "root:x:0:0", string(content))
+
+	passwdLink, err := index.LookupPath("/etc/passwd-")
+	require.NoError(t, err)
+	assert.Equal(t, passwd.Attributes.Mode, passwdLink.Attributes.Mode)
+	assert.Equal(t, passwd.Attributes.Inode, passwdLink.Attributes.Inode)
+	assert.Equal(t, uint64(2), passwdLink.Attributes.Nlink)
+	assert.Equal(t, "/etc/passwd", passwdLink.HardlinkGroup)
+	assert.Equal(t, "/etc/passwd", passwd.HardlinkGroup)
+
+	localtime, err := index.LookupPath("/etc/localtime")
+	require.NoError(t, err)
+	assert.True(t, localtime.IsSymlink())
+	require.NotNil(t, localtime.SymlinkTarget)
+	assert.Equal(t, "/usr/share/zoneinfo/UTC", *localtime.SymlinkTarget)
+
+	ping, err := index.LookupPath("/usr/bin/ping")
+	require.NoError(t, err)
+	assert.Equal(t, uint32(syscall.S_IFREG|04755), ping.Attributes.Mode)
+	assert.Equal(t, uint32(1000), ping.Attributes.Owner.Uid)
+	assert.Equal(t, map[string][]byte{"user.viscaufs": []byte("1")}, ping.Xattrs)
+
+	// implicit parent directories are indexed
+	usrBin, err := index.LookupPath("/usr/bin")
+	require.NoError(t, err)
+	assert.True(t, usrBin.IsDirectory())
+
+	devNull, err := index.LookupPath("/dev/null")
+	require.NoError(t, err)
+	assert.Equal(t, uint32(syscall.S_IFCHR|0666), devNull.Attributes.Mode)
+	assert.Equal(t, uint64(0x103), devNull.Attributes.Rdev)
+
+	fifo, err := index.LookupPath("/run/fifo")
+	require.NoError(t, err)
+	assert.Equal(t, uint32(syscall.S_IFIFO|0644), fifo.Attributes.Mode)
+
+	etc, err := index.LookupPath("/etc")
+	require.NoError(t, err)
+	assert.Equal(t, uint32(syscall.S_IFDIR|0555), etc.Attributes.Mode)
+
+	etcInfo, err := os.Stat(filepath.Join(contentPath, "etc"))
+	require.NoError(t, err)
+	assert.Equal(t, mtime, etcInfo.ModTime().UTC())
+}
+
+func TestExtractLayerRejectsUnsafeEntries(t *testing.T) {
+	testCases := []struct {
+		name    string
+		entries []tarEntry
+		// valid layers are extracted, they must only never modify the host
+		valid bool
+	}{
+		{
+			name: "parent traversal",
+			entries: []tarEntry{
+				{header: tar.Header{Typeflag: tar.TypeReg, Name: "../../escaped", Mode: 0644}, content: "x"},
+			},
+		},
+		{
+			name: "nested parent traversal",
+			entries: []tarEntry{
+				{header: tar.Header{Typeflag: tar.TypeReg, Name: "usr/../../escaped", Mode: 0644}, content: "x"},
+			},
+		},
+		{
+			name: "write through absolute symlink",
+			entries: []tarEntry{
+				{header: tar.Header{Typeflag: tar.TypeSymlink, Name: "escape", Linkname: "/"}},
+				{header: tar.Header{Typeflag: tar.TypeReg, Name: "escape/escaped", Mode: 0644}, content: "x"},
+			},
+		},
+		{
+			name: "write through relative symlink",
+			entries: []tarEntry{
+				{header: tar.Header{Typeflag: tar.TypeSymlink, Name: "escape", Linkname: "../../../../../../"}},
+				{header: tar.Header{Typeflag: tar.TypeDir, Name: "escape/dir", Mode: 0755}},
+			},
+		},
+		{
+			name: "hard link traversal",
+			entries: []tarEntry{
+				{header: tar.Header{Typeflag: tar.TypeLink, Name: "passwd", Linkname: "../../../../etc/passwd"}},
+			},
+		},
+		{
+			name: "hard link through symlink",
+			entries: []tarEntry{
+				{header: tar.Header{Typeflag: tar.TypeSymlink, Name: "hostetc", Linkname: "/etc"}},
+				{header: tar.Header{Typeflag: tar.TypeLink, Name: "passwd", Linkname: "hostetc/passwd"}},
+			},
+		},
+		{
+			name: "directory replaced by a symlink",
+			entries: []tarEntry{
+				{header: tar.Header{Typeflag: tar.TypeDir, Name: "etc/", Mode: 0777}},
+				{header: tar.Header{Typeflag: tar.TypeSymlink, Name: "etc", Linkname: "../../host"}},
+			},
+			valid: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			root := t.TempDir()
+			contentPath := filepath.Join(root, "layer", "content")
+			require.NoError(t, os.MkdirAll(contentPath, 0755))
+			host := filepath.Join(root, "host")
+			require.NoError(t, os.Mkdir(host, 0750))
+
+			_, err := extractLayer(buildLayerTar(t, tc.entries), contentPath)
+			if tc.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, types.ErrUnsafeLayerEntry)
+			}
+
+			_, err = os.Lstat(filepath.Join(root, "escaped"))
+			assert.ErrorIs(t, err, os.ErrNotExist, "nothing must be written outside of the layer root")
+
+			info, err := os.Lstat(host)
+			require.NoError(t, err)
+			assert.Equal(t, os.ModeDir|0750, info.Mode(), "the host directories must keep their mode")
+		})
+	}
+}
+
+func TestExtractLayerAllowsSymlinksPointingOutside(t *testing.T) {
+	// symlinks are resolved by the client inside the image, they are kept as is but never followed by the server
+	contentPath := t.TempDir()
+	index, err := extractLayer(buildLayerTar(t, []tarEntry{
+		{header: tar.Header{Typeflag: tar.TypeSymlink, Name: "lib", Linkname: "/usr/lib"}},
+		{header: tar.Header{Typeflag: tar.TypeSymlink, Name: "up", Linkname: "../../../.."}},
+	}), contentPath)
+	require.NoError(t, err)
+
+	lib, err := index.LookupPath("/lib")
+	require.NoError(t, err)
+	assert.True(t, lib.IsSymlink())
+}
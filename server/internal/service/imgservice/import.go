@@ -0,0 +1,264 @@
+package imgservice
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/baepo-cloud/viscaufs-server/internal/types"
+	"github.com/google/go-containerregistry/pkg/name"
+	img "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+)
+
+const (
+	// importRepositoryPrefix is the synthetic repository under which imported images are recorded
+	importRepositoryPrefix = "viscaufs.local/import/"
+	ociLayoutFile          = "oci-layout"
+
+	annotationRefName        = "org.opencontainers.image.ref.name"
+	annotationContainerdName = "io.containerd.image.name"
+)
+
+var invalidRepositoryChars = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+// Import imports an image from an OCI image layout directory or a docker save archive located on the server host
+// and prepares it exactly like a pulled image
+func (s *Service) Import(params types.ImportImageParams) (string, error) {
+	path, err := s.resolveImportPath(params.Path)
+	if err != nil {
+		return "", err
+	}
+
+	platform, err := s.resolvePlatform(params.Platform)
+	if err != nil {
+		return "", err
+	}
+
+	image, err := s.buildImportedImageWrapper(path, params.Tag, platform)
+	if err != nil {
+		return "", fmt.Errorf("failed to import image: %w", err)
+	}
+
+	return s.prepare(image)
+}
+
+// resolveImportPath resolves path (following symlinks) and checks it is located inside the import directory
+func (s *Service) resolveImportPath(path string) (string, error) {
+	if s.importDir == "" {
+		return "", fmt.Errorf("%w: imports are disabled", types.ErrImportPathNotAllowed)
+	}
+
+	importDir, err := filepath.EvalSymlinks(s.importDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve import directory: %w", err)
+	}
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(importDir, path)
+	}
+
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve import path: %w", err)
+	}
+
+	rel, err := filepath.Rel(importDir, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%w: %s is outside of the import directory", types.ErrImportPathNotAllowed, path)
+	}
+
+	return resolved, nil
+}
+
+// buildImportedImageWrapper opens the image stored at path, an OCI image layout when path is a directory
+// and a docker save archive otherwise
+func (s *Service) buildImportedImageWrapper(path, tag string, platform img.Platform) (*ImageWrapper, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	reference, err := importReference(path, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		image, err := readDockerArchive(path, tag)
+		if err != nil {
+			return nil, err
+		}
+
+		return newImageWrapper(reference, image, "", imagePlatform(image, platform))
+	}
+
+	if _, err := os.Stat(filepath.Join(path, ociLayoutFile)); err != nil {
+		return nil, fmt.Errorf("%s is not an OCI image layout: %w", path, err)
+	}
+
+	index, err := layout.ImageIndexFromPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OCI image layout: %w", err)
+	}
+
+	image, indexDigest, err := selectLayoutImage(index, tag, platform)
+	if err != nil {
+		return nil, err
+	}
+
+	return newImageWrapper(reference, image, indexDigest, imagePlatform(image, platform))
+}
+
+func readDockerArchive(path, tag string) (img.Image, error) {
+	var repoTag *name.Tag
+	if tag != "" {
+		t, err := name.NewTag(tag)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tag %q: %w", tag, err)
+		}
+		repoTag = &t
+	}
+
+	image, err := tarball.ImageFromPath(path, repoTag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read docker archive: %w", err)
+	}
+
+	return image, nil
+}
+
+// selectLayoutImage selects the image of the layout matching the tag (from the ref name annotations) and the
+// platform, it returns the digest of the image index it was resolved from if any
+func selectLayoutImage(index img.ImageIndex, tag string, platform img.Platform) (img.Image, string, error) {
+	manifest, err := index.IndexManifest()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read index manifest: %w", err)
+	}
+
+	candidates := manifest.Manifests
+	if tag != "" {
+		candidates = nil
+		for _, descriptor := range manifest.Manifests {
+			if layoutDescriptorMatchesTag(descriptor, tag) {
+				candidates = append(candidates, descriptor)
+			}
+		}
+	}
+
+	descriptor, err := selectDescriptor(candidates, platform)
+	if err != nil {
+		return nil, "", fmt.Errorf("tag %q: %w", tag, err)
+	}
+
+	if !descriptor.MediaType.IsIndex() {
+		image, err := index.Image(descriptor.Digest)
+		return image, "", err
+	}
+
+	child, err := index.ImageIndex(descriptor.Digest)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read image index %s: %w", descriptor.Digest, err)
+	}
+
+	childManifest, err := child.IndexManifest()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read index manifest %s: %w", descriptor.Digest, err)
+	}
+
+	// unlike the layout entries, the children of an index must always match the platform
+	var platformCandidates []img.Descriptor
+	for _, d := range childManifest.Manifests {
+		if d.MediaType.IsImage() && d.Platform != nil && d.Platform.Satisfies(platform) {
+			platformCandidates = append(platformCandidates, d)
+		}
+	}
+
+	imageDescriptor, err := selectDescriptor(platformCandidates, platform)
+	if err != nil {
+		return nil, "", fmt.Errorf("index %s for platform %s: %w", descriptor.Digest, platform.String(), err)
+	}
+
+	image, err := child.Image(imageDescriptor.Digest)
+	return image, descriptor.Digest.String(), err
+}
+
+// selectDescriptor returns the only candidate, or the only one matching the platform when there are several
+func selectDescriptor(candidates []img.Descriptor, platform img.Platform) (img.Descriptor, error) {
+	if len(candidates) == 1 {
+		return candidates[0], nil
+	}
+	if len(candidates) == 0 {
+		return img.Descriptor{}, fmt.Errorf("no matching manifest")
+	}
+
+	var matching []img.Descriptor
+	for _, candidate := range candidates {
+		if candidate.Platform != nil && candidate.Platform.Satisfies(platform) {
+			matching = append(matching, candidate)
+		}
+	}
+
+	if len(matching) != 1 {
+		return img.Descriptor{}, fmt.Errorf("%d manifests match platform %s", len(matching), platform.String())
+	}
+
+	return matching[0], nil
+}
+
+func layoutDescriptorMatchesTag(descriptor img.Descriptor, tag string) bool {
+	for _, annotation := range []string{annotationRefName, annotationContainerdName} {
+		value := descriptor.Annotations[annotation]
+		if value == "" {
+			continue
+		}
+		if value == tag {
+			return true
+		}
+
+		// compare full references, "alpine:3" matches "docker.io/library/alpine:3"
+		valueRef, err := name.ParseReference(value)
+		if err != nil {
+			continue
+		}
+		tagRef, err := name.ParseReference(tag)
+		if err == nil && valueRef.Name() == tagRef.Name() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// importReference builds the synthetic reference an imported image is recorded under: the repository and tag come
+// from the requested tag when it names a repository, from the file name of the image otherwise
+func importReference(path, tag string) (name.Reference, error) {
+	repository := sanitizeRepository(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)))
+	identifier := name.DefaultTag
+
+	if tag != "" {
+		if strings.ContainsAny(tag, ":/") {
+			tagRef, err := name.NewTag(tag)
+			if err != nil {
+				return nil, fmt.Errorf("invalid tag %q: %w", tag, err)
+			}
+			repository = tagRef.RepositoryStr()
+			identifier = tagRef.TagStr()
+		} else {
+			identifier = tag
+		}
+	}
+
+	return name.NewTag(importRepositoryPrefix+repository+":"+identifier, name.StrictValidation)
+}
+
+func sanitizeRepository(repository string) string {
+	repository = strings.Trim(invalidRepositoryChars.ReplaceAllString(strings.ToLower(repository), "-"), "-._")
+	if repository == "" {
+		return "image"
+	}
+	return repository
+}
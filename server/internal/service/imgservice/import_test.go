@@ -0,0 +1,177 @@
+package imgservice
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/baepo-cloud/viscaufs-server/internal/config"
+	"github.com/baepo-cloud/viscaufs-server/internal/types"
+	"github.com/google/go-containerregistry/pkg/name"
+	img "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func imageDigest(t *testing.T, image img.Image) string {
+	t.Helper()
+
+	digest, err := image.Digest()
+	require.NoError(t, err)
+	return digest.String()
+}
+
+func TestBuildImportedImageWrapperOCILayout(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "ci-build")
+	path, err := layout.Write(dir, empty.Index)
+	require.NoError(t, err)
+
+	v1Image, err := random.Image(256, 2)
+	require.NoError(t, err)
+	require.NoError(t, path.AppendImage(v1Image, layout.WithAnnotations(map[string]string{
+		annotationRefName: "v1",
+	})))
+
+	v2Image, err := random.Image(256, 3)
+	require.NoError(t, err)
+	require.NoError(t, path.AppendImage(v2Image, layout.WithAnnotations(map[string]string{
+		annotationContainerdName: "docker.io/library/app:v2",
+	})))
+
+	var index img.ImageIndex = empty.Index
+	arm64Image, err := random.Image(256, 1)
+	require.NoError(t, err)
+	amd64Image, err := random.Image(256, 1)
+	require.NoError(t, err)
+	for platform, image := range map[string]img.Image{"linux/arm64": arm64Image, "linux/amd64": amd64Image} {
+		p, err := img.ParsePlatform(platform)
+		require.NoError(t, err)
+		index = mutate.AppendManifests(index, mutate.IndexAddendum{Add: image, Descriptor: img.Descriptor{Platform: p}})
+	}
+	require.NoError(t, path.AppendIndex(index, layout.WithAnnotations(map[string]string{
+		annotationRefName: "multi",
+	})))
+	indexDigest, err := index.Digest()
+	require.NoError(t, err)
+
+	s := newTestService(t, &config.Config{})
+
+	t.Run("select by ref name", func(t *testing.T) {
+		wrapper, err := s.buildImportedImageWrapper(dir, "v1", s.defaultPlatform)
+		require.NoError(t, err)
+		assert.Equal(t, imageDigest(t, v1Image), wrapper.Digest)
+		assert.Len(t, wrapper.Layers, 2)
+		assert.Equal(t, "viscaufs.local/import/ci-build:v1", wrapper.Reference.String())
+		assert.Empty(t, wrapper.IndexDigest)
+	})
+
+	t.Run("select by containerd image name", func(t *testing.T) {
+		wrapper, err := s.buildImportedImageWrapper(dir, "app:v2", s.defaultPlatform)
+		require.NoError(t, err)
+		assert.Equal(t, imageDigest(t, v2Image), wrapper.Digest)
+		assert.Equal(t, "viscaufs.local/import/library/app:v2", wrapper.Reference.String())
+	})
+
+	t.Run("select platform of a nested index", func(t *testing.T) {
+		wrapper, err := s.buildImportedImageWrapper(dir, "multi", img.Platform{OS: "linux", Architecture: "arm64"})
+		require.NoError(t, err)
+		assert.Equal(t, imageDigest(t, arm64Image), wrapper.Digest)
+		assert.Equal(t, indexDigest.String(), wrapper.IndexDigest)
+
+		_, err = s.buildImportedImageWrapper(dir, "multi", img.Platform{OS: "linux", Architecture: "s390x"})
+		assert.Error(t, err)
+	})
+
+	t.Run("ambiguous without tag", func(t *testing.T) {
+		_, err := s.buildImportedImageWrapper(dir, "", s.defaultPlatform)
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown tag", func(t *testing.T) {
+		_, err := s.buildImportedImageWrapper(dir, "v3", s.defaultPlatform)
+		assert.Error(t, err)
+	})
+}
+
+func TestBuildImportedImageWrapperDockerArchive(t *testing.T) {
+	dir := t.TempDir()
+
+	appImage, err := random.Image(256, 2)
+	require.NoError(t, err)
+	toolsImage, err := random.Image(256, 1)
+	require.NoError(t, err)
+
+	appTag, err := name.NewTag("registry.example.com/team/app:ci-42")
+	require.NoError(t, err)
+	toolsTag, err := name.NewTag("tools:latest")
+	require.NoError(t, err)
+
+	single := filepath.Join(dir, "App_Image.tar")
+	require.NoError(t, tarball.WriteToFile(single, appTag, appImage))
+
+	multi := filepath.Join(dir, "multi.tar")
+	require.NoError(t, tarball.MultiWriteToFile(multi, map[name.Tag]img.Image{appTag: appImage, toolsTag: toolsImage}))
+
+	s := newTestService(t, &config.Config{})
+
+	wrapper, err := s.buildImportedImageWrapper(single, "", s.defaultPlatform)
+	require.NoError(t, err)
+	assert.Equal(t, imageDigest(t, appImage), wrapper.Digest)
+	assert.Len(t, wrapper.Layers, 2)
+	assert.Equal(t, "viscaufs.local/import/app_image:latest", wrapper.Reference.String())
+
+	wrapper, err = s.buildImportedImageWrapper(multi, "tools:latest", s.defaultPlatform)
+	require.NoError(t, err)
+	assert.Equal(t, imageDigest(t, toolsImage), wrapper.Digest)
+	assert.Equal(t, "viscaufs.local/import/library/tools:latest", wrapper.Reference.String())
+
+	_, err = s.buildImportedImageWrapper(multi, "", s.defaultPlatform)
+	assert.Error(t, err)
+}
+
+func TestResolveImportPath(t *testing.T) {
+	importDir := t.TempDir()
+	outside := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(importDir, "image.tar"), nil, 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(outside, "secret.tar"), nil, 0600))
+	require.NoError(t, os.Symlink(filepath.Join(outside, "secret.tar"), filepath.Join(importDir, "escape.tar")))
+
+	s := &Service{importDir: importDir}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr error
+	}{
+		{name: "relative path", path: "image.tar"},
+		{name: "absolute path", path: filepath.Join(importDir, "image.tar")},
+		{name: "dot dot", path: "../" + filepath.Base(outside) + "/secret.tar", wantErr: types.ErrImportPathNotAllowed},
+		{name: "absolute path outside", path: filepath.Join(outside, "secret.tar"), wantErr: types.ErrImportPathNotAllowed},
+		{name: "symlink outside", path: "escape.tar", wantErr: types.ErrImportPathNotAllowed},
+		{name: "missing", path: "missing.tar", wantErr: os.ErrNotExist},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved, err := s.resolveImportPath(tt.path)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, filepath.Join(importDir, "image.tar"), resolved)
+		})
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		_, err := (&Service{}).resolveImportPath("image.tar")
+		assert.ErrorIs(t, err, types.ErrImportPathNotAllowed)
+	})
+}
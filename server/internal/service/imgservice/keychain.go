@@ -0,0 +1,103 @@
+package imgservice
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/baepo-cloud/viscaufs-server/internal/config"
+	dockerconfig "github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+// newKeychain builds the keychain used to authenticate against registries.
+// Static credentials are tried first, then the docker config file (which also handles credential helpers).
+func newKeychain(cfg *config.Config) (authn.Keychain, error) {
+	keychains := []authn.Keychain{staticKeychain(cfg.RegistryCredentials)}
+
+	if cfg.RegistryConfigFile != "" {
+		configFileKeychain, err := newConfigFileKeychain(cfg.RegistryConfigFile)
+		if err != nil {
+			return nil, err
+		}
+		keychains = append(keychains, configFileKeychain)
+	} else {
+		keychains = append(keychains, authn.DefaultKeychain)
+	}
+
+	return authn.NewMultiKeychain(keychains...), nil
+}
+
+// staticKeychain resolves credentials configured on the server by registry host
+type staticKeychain map[string]config.RegistryCredential
+
+func (k staticKeychain) Resolve(target authn.Resource) (authn.Authenticator, error) {
+	credential, ok := k[target.RegistryStr()]
+	if !ok {
+		return authn.Anonymous, nil
+	}
+
+	return authn.FromConfig(authn.AuthConfig{
+		Username: credential.Username,
+		Password: credential.Password,
+	}), nil
+}
+
+// configFileKeychain resolves credentials from a docker config.json located at an arbitrary path
+type configFileKeychain struct {
+	configFile *configfile.ConfigFile
+}
+
+func newConfigFileKeychain(path string) (*configFileKeychain, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open registry config file: %w", err)
+	}
+	defer f.Close()
+
+	cf, err := dockerconfig.LoadFromReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load registry config file: %w", err)
+	}
+	cf.Filename = path
+
+	return &configFileKeychain{configFile: cf}, nil
+}
+
+func (k *configFileKeychain) Resolve(target authn.Resource) (authn.Authenticator, error) {
+	for _, key := range []string{target.String(), target.RegistryStr()} {
+		if key == name.DefaultRegistry {
+			key = authn.DefaultAuthKey
+		}
+
+		cfg, err := k.configFile.GetAuthConfig(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get auth config for %s: %w", key, err)
+		}
+
+		if cfg.Username == "" && cfg.Password == "" && cfg.Auth == "" && cfg.IdentityToken == "" && cfg.RegistryToken == "" {
+			continue
+		}
+
+		return authn.FromConfig(authn.AuthConfig{
+			Username:      cfg.Username,
+			Password:      cfg.Password,
+			Auth:          cfg.Auth,
+			IdentityToken: cfg.IdentityToken,
+			RegistryToken: cfg.RegistryToken,
+		}), nil
+	}
+
+	return authn.Anonymous, nil
+}
+
+// remoteAuthOption returns the remote option authenticating a pull, a per-request token wins over the keychain
+func (s *Service) remoteAuthOption(registryToken string) remote.Option {
+	if registryToken != "" {
+		return remote.WithAuth(&authn.Bearer{Token: registryToken})
+	}
+
+	return remote.WithAuthFromKeychain(s.keychain)
+}
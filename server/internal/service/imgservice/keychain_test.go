@@ -0,0 +1,153 @@
+package imgservice
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/baepo-cloud/viscaufs-server/internal/config"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	img "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testRegistryUser     = "viscaufs"
+	testRegistryPassword = "s3cr3t"
+	testRegistryToken    = "t0k3n"
+)
+
+// newAuthRegistry starts an in-process registry protected by basic auth (or a static bearer token)
+// and pushes a random image to it, returning the registry host and the image reference.
+func newAuthRegistry(t *testing.T) (string, string) {
+	t.Helper()
+
+	handler := registry.New()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "Bearer "+testRegistryToken {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		user, password, ok := r.BasicAuth()
+		if !ok || user != testRegistryUser || password != testRegistryPassword {
+			w.Header().Set("WWW-Authenticate", `Basic realm="viscaufs"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	ref, err := name.ParseReference(host + "/test/image:latest")
+	require.NoError(t, err)
+
+	image, err := random.Image(1024, 2)
+	require.NoError(t, err)
+
+	err = remote.Write(ref, image, remote.WithAuth(&authn.Basic{
+		Username: testRegistryUser,
+		Password: testRegistryPassword,
+	}))
+	require.NoError(t, err)
+
+	return host, ref.String()
+}
+
+func newTestService(t *testing.T, cfg *config.Config) *Service {
+	t.Helper()
+
+	// isolate the test from the docker configuration of the host
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("DOCKER_CONFIG", "")
+
+	keychain, err := newKeychain(cfg)
+	require.NoError(t, err)
+
+	mirrors, err := normalizeMirrors(cfg.RegistryMirrors)
+	require.NoError(t, err)
+
+	return &Service{
+		keychain: keychain,
+		mirrors:  mirrors,
+		logger:   slog.New(slog.NewTextHandler(io.Discard, nil)),
+
+		defaultPlatform: img.Platform{OS: "linux", Architecture: "amd64"},
+	}
+}
+
+func TestBuildImageWrapperAuthentication(t *testing.T) {
+	host, ref := newAuthRegistry(t)
+
+	t.Run("anonymous pull is rejected", func(t *testing.T) {
+		s := newTestService(t, &config.Config{})
+
+		_, err := s.buildImageWrapper(ref, "", s.defaultPlatform)
+		assert.Error(t, err)
+	})
+
+	t.Run("static credentials", func(t *testing.T) {
+		s := newTestService(t, &config.Config{
+			RegistryCredentials: map[string]config.RegistryCredential{
+				host: {Username: testRegistryUser, Password: testRegistryPassword},
+			},
+		})
+
+		wrapper, err := s.buildImageWrapper(ref, "", s.defaultPlatform)
+		require.NoError(t, err)
+		assert.Len(t, wrapper.Layers, 2)
+	})
+
+	t.Run("docker config file", func(t *testing.T) {
+		auth := base64.StdEncoding.EncodeToString([]byte(testRegistryUser + ":" + testRegistryPassword))
+		configFile, err := json.Marshal(map[string]any{
+			"auths": map[string]any{
+				host: map[string]string{"auth": auth},
+			},
+		})
+		require.NoError(t, err)
+
+		configPath := filepath.Join(t.TempDir(), "config.json")
+		require.NoError(t, os.WriteFile(configPath, configFile, 0600))
+
+		s := newTestService(t, &config.Config{RegistryConfigFile: configPath})
+
+		wrapper, err := s.buildImageWrapper(ref, "", s.defaultPlatform)
+		require.NoError(t, err)
+		assert.Len(t, wrapper.Layers, 2)
+	})
+
+	t.Run("per request token", func(t *testing.T) {
+		s := newTestService(t, &config.Config{})
+
+		wrapper, err := s.buildImageWrapper(ref, testRegistryToken, s.defaultPlatform)
+		require.NoError(t, err)
+		assert.Len(t, wrapper.Layers, 2)
+		assert.NotContains(t, wrapper.Reference.String(), testRegistryToken)
+	})
+
+	t.Run("wrong static credentials", func(t *testing.T) {
+		s := newTestService(t, &config.Config{
+			RegistryCredentials: map[string]config.RegistryCredential{
+				host: {Username: testRegistryUser, Password: "wrong"},
+			},
+		})
+
+		_, err := s.buildImageWrapper(ref, "", s.defaultPlatform)
+		assert.Error(t, err)
+	})
+}
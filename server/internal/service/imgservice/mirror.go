@@ -0,0 +1,84 @@
+package imgservice
+
+import (
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/baepo-cloud/viscaufs-server/internal/config"
+	"github.com/google/go-containerregistry/pkg/name"
+	img "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+// normalizeMirrors keys the configured mirrors by canonical registry name (e.g. docker.io -> index.docker.io)
+func normalizeMirrors(mirrors map[string][]config.RegistryMirror) (map[string][]config.RegistryMirror, error) {
+	normalized := make(map[string][]config.RegistryMirror, len(mirrors))
+	for registry, registryMirrors := range mirrors {
+		reg, err := name.NewRegistry(registry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mirrored registry %q: %w", registry, err)
+		}
+		normalized[reg.RegistryStr()] = append(normalized[reg.RegistryStr()], registryMirrors...)
+	}
+
+	return normalized, nil
+}
+
+// fetchDescriptor fetches the image descriptor from the configured mirrors of its registry in order and falls back
+// to the upstream registry. The per-request registry token is only sent to the upstream registry.
+func (s *Service) fetchDescriptor(reference name.Reference, registryToken string, platform img.Platform) (*remote.Descriptor, error) {
+	for _, mirror := range s.mirrors[reference.Context().RegistryStr()] {
+		mirrorReference, err := mirrorReference(reference, mirror)
+		if err != nil {
+			s.logger.Warn("invalid mirror reference",
+				slog.String("reference", reference.String()),
+				slog.String("mirror", mirror.Host),
+				slog.String("error", err.Error()))
+			continue
+		}
+
+		options := []remote.Option{remote.WithAuthFromKeychain(s.keychain), remote.WithPlatform(platform)}
+		if mirror.Insecure {
+			options = append(options, remote.WithTransport(insecureTransport()))
+		}
+
+		descriptor, err := remote.Get(mirrorReference, options...)
+		if err != nil {
+			s.logger.Warn("failed to fetch image from mirror, trying next",
+				slog.String("reference", reference.String()),
+				slog.String("mirror", mirror.Host),
+				slog.String("error", err.Error()))
+			continue
+		}
+
+		s.logger.Info("image fetched from mirror",
+			slog.String("reference", reference.String()),
+			slog.String("mirror", mirror.Host))
+		return descriptor, nil
+	}
+
+	return remote.Get(reference, s.remoteAuthOption(registryToken), remote.WithPlatform(platform))
+}
+
+// mirrorReference rewrites the reference to point to the same repository on the mirror
+func mirrorReference(reference name.Reference, mirror config.RegistryMirror) (name.Reference, error) {
+	var options []name.Option
+	if mirror.PlainHTTP {
+		options = append(options, name.Insecure)
+	}
+
+	repository := mirror.Host + "/" + reference.Context().RepositoryStr()
+	if _, ok := reference.(name.Digest); ok {
+		return name.NewDigest(repository+"@"+reference.Identifier(), options...)
+	}
+
+	return name.NewTag(repository+":"+reference.Identifier(), options...)
+}
+
+func insecureTransport() http.RoundTripper {
+	transport := remote.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	return transport
+}
@@ -0,0 +1,69 @@
+package imgservice
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/baepo-cloud/viscaufs-server/internal/config"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildImageWrapperMirrors(t *testing.T) {
+	server := httptest.NewServer(registry.New())
+	t.Cleanup(server.Close)
+	mirrorHost := strings.TrimPrefix(server.URL, "http://")
+
+	mirrored, err := name.ParseReference(mirrorHost + "/team/app:v1")
+	require.NoError(t, err)
+	image, err := random.Image(512, 1)
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(mirrored, image))
+
+	expectedDigest, err := image.Digest()
+	require.NoError(t, err)
+
+	t.Run("falls back to the next mirror in order", func(t *testing.T) {
+		s := newTestService(t, &config.Config{
+			RegistryMirrors: map[string][]config.RegistryMirror{
+				"upstream.invalid": {
+					{Host: "127.0.0.1:1", PlainHTTP: true},
+					{Host: mirrorHost, PlainHTTP: true},
+				},
+			},
+		})
+
+		wrapper, err := s.buildImageWrapper("upstream.invalid/team/app:v1", "", s.defaultPlatform)
+		require.NoError(t, err)
+		assert.Equal(t, expectedDigest.String(), wrapper.Digest)
+		assert.Equal(t, "upstream.invalid/team/app", wrapper.Reference.Context().Name())
+	})
+
+	t.Run("docker hub alias", func(t *testing.T) {
+		s := newTestService(t, &config.Config{
+			RegistryMirrors: map[string][]config.RegistryMirror{
+				"docker.io": {{Host: mirrorHost, PlainHTTP: true}},
+			},
+		})
+
+		_, err := s.buildImageWrapper("team/app:v1", "", s.defaultPlatform)
+		require.NoError(t, err)
+	})
+
+	t.Run("unreachable mirrors fall back to upstream", func(t *testing.T) {
+		s := newTestService(t, &config.Config{
+			RegistryMirrors: map[string][]config.RegistryMirror{
+				mirrorHost: {{Host: "127.0.0.1:1", PlainHTTP: true}},
+			},
+		})
+
+		wrapper, err := s.buildImageWrapper(mirrored.String(), "", s.defaultPlatform)
+		require.NoError(t, err)
+		assert.Equal(t, expectedDigest.String(), wrapper.Digest)
+	})
+}
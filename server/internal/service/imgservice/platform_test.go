@@ -0,0 +1,103 @@
+package imgservice
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/baepo-cloud/viscaufs-server/internal/config"
+	"github.com/baepo-cloud/viscaufs-server/internal/types"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	img "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildImageWrapperPlatform(t *testing.T) {
+	server := httptest.NewServer(registry.New())
+	t.Cleanup(server.Close)
+
+	ref, err := name.ParseReference(strings.TrimPrefix(server.URL, "http://") + "/multi/arch:latest")
+	require.NoError(t, err)
+
+	platforms := []img.Platform{
+		{OS: "linux", Architecture: "amd64"},
+		{OS: "linux", Architecture: "arm64", Variant: "v8"},
+	}
+
+	var index img.ImageIndex = empty.Index
+	digestByArch := map[string]string{}
+	for _, platform := range platforms {
+		image, err := random.Image(256, 1)
+		require.NoError(t, err)
+		configFile, err := image.ConfigFile()
+		require.NoError(t, err)
+		configFile.OS, configFile.Architecture, configFile.Variant = platform.OS, platform.Architecture, platform.Variant
+		image, err = mutate.ConfigFile(image, configFile)
+		require.NoError(t, err)
+
+		digest, err := image.Digest()
+		require.NoError(t, err)
+		digestByArch[platform.Architecture] = digest.String()
+
+		index = mutate.AppendManifests(index, mutate.IndexAddendum{
+			Add:        image,
+			Descriptor: img.Descriptor{Platform: &platform},
+		})
+	}
+	require.NoError(t, remote.WriteIndex(ref, index))
+
+	indexDigest, err := index.Digest()
+	require.NoError(t, err)
+
+	s := newTestService(t, &config.Config{})
+
+	defaultPlatform, err := s.resolvePlatform(nil)
+	require.NoError(t, err)
+	amd64, err := s.buildImageWrapper(ref.String(), "", defaultPlatform)
+	require.NoError(t, err)
+	assert.Equal(t, digestByArch["amd64"], amd64.Digest)
+	assert.Equal(t, indexDigest.String(), amd64.IndexDigest)
+	assert.Equal(t, "linux/amd64", amd64.Platform.String())
+
+	// the platform of the image config is recorded, not the requested one
+	arm64Platform, err := s.resolvePlatform(&types.Platform{OS: "linux", Architecture: "arm64"})
+	require.NoError(t, err)
+	arm64, err := s.buildImageWrapper(ref.String(), "", arm64Platform)
+	require.NoError(t, err)
+	assert.Equal(t, digestByArch["arm64"], arm64.Digest)
+	assert.Equal(t, indexDigest.String(), arm64.IndexDigest)
+	assert.Equal(t, "linux/arm64/v8", arm64.Platform.String())
+	assert.NotEqual(t, amd64.Digest, arm64.Digest)
+
+	s390x, err := s.resolvePlatform(&types.Platform{OS: "linux", Architecture: "s390x"})
+	require.NoError(t, err)
+	_, err = s.buildImageWrapper(ref.String(), "", s390x)
+	assert.Error(t, err)
+}
+
+func TestResolvePlatform(t *testing.T) {
+	s := newTestService(t, &config.Config{})
+
+	platform, err := s.resolvePlatform(&types.Platform{})
+	require.NoError(t, err)
+	assert.Equal(t, s.defaultPlatform, platform)
+
+	platform, err = s.resolvePlatform(&types.Platform{OS: "linux", Architecture: "arm64", Variant: "v8"})
+	require.NoError(t, err)
+	assert.Equal(t, "linux/arm64/v8", platform.String())
+
+	// a partial platform is not completed with the default one
+	for _, partial := range []types.Platform{{Architecture: "arm64"}, {OS: "linux"}, {OS: "linux", Variant: "v8"}} {
+		_, err := s.resolvePlatform(&partial)
+		assert.ErrorIs(t, err, types.ErrInvalidPlatform, partial)
+	}
+
+	_, err = s.Download(types.DownloadImageParams{ImageRef: "registry.invalid/app:latest", Platform: &types.Platform{Architecture: "arm64"}})
+	assert.ErrorIs(t, err, types.ErrInvalidPlatform)
+}
@@ -0,0 +1,58 @@
+package imgservice
+
+import (
+	"context"
+
+	"github.com/baepo-cloud/viscaufs-server/internal/types"
+)
+
+// layerPool runs the layer downloads of every image on a bounded number of workers, in submission order
+type layerPool struct {
+	jobs chan layerJob
+}
+
+type layerJob struct {
+	ctx    context.Context
+	result chan<- layerResult
+	run    func() (*types.Layer, error)
+}
+
+type layerResult struct {
+	layer *types.Layer
+	err   error
+}
+
+func newLayerPool(workers int) *layerPool {
+	if workers < 1 {
+		workers = 1
+	}
+
+	p := &layerPool{jobs: make(chan layerJob)}
+	for i := 0; i < workers; i++ {
+		go p.work()
+	}
+
+	return p
+}
+
+// submit blocks until a worker picks the job, its result is sent to result which must be buffered. The job is
+// skipped, with the context error as result, when ctx is done before it runs.
+func (p *layerPool) submit(ctx context.Context, result chan<- layerResult, run func() (*types.Layer, error)) {
+	select {
+	case p.jobs <- layerJob{ctx: ctx, result: result, run: run}:
+	case <-ctx.Done():
+		result <- layerResult{err: ctx.Err()}
+	}
+}
+
+func (p *layerPool) work() {
+	for job := range p.jobs {
+		if err := job.ctx.Err(); err != nil {
+			job.result <- layerResult{err: err}
+			continue
+		}
+
+		layer, err := job.run()
+		job.result <- layerResult{layer: layer, err: err}
+	}
+}
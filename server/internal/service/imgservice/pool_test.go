@@ -0,0 +1,98 @@
+package imgservice
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/baepo-cloud/viscaufs-server/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLayerPoolBoundsConcurrency(t *testing.T) {
+	const workers = 3
+	pool := newLayerPool(workers)
+
+	var (
+		running, maxRunning atomic.Int32
+		results             []chan layerResult
+	)
+	for i := 0; i < 20; i++ {
+		result := make(chan layerResult, 1)
+		results = append(results, result)
+		go pool.submit(context.Background(), result, func() (*types.Layer, error) {
+			current := running.Add(1)
+			for {
+				max := maxRunning.Load()
+				if current <= max || maxRunning.CompareAndSwap(max, current) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			running.Add(-1)
+			return &types.Layer{}, nil
+		})
+	}
+
+	for _, result := range results {
+		r := <-result
+		require.NoError(t, r.err)
+		assert.NotNil(t, r.layer)
+	}
+	assert.Equal(t, int32(workers), maxRunning.Load())
+}
+
+func TestLayerPoolRunsInSubmissionOrder(t *testing.T) {
+	pool := newLayerPool(1)
+
+	var (
+		mu    sync.Mutex
+		order []int
+	)
+	results := make([]chan layerResult, 5)
+	for i := range results {
+		results[i] = make(chan layerResult, 1)
+		pool.submit(context.Background(), results[i], func() (*types.Layer, error) {
+			mu.Lock()
+			order = append(order, i)
+			mu.Unlock()
+			return nil, nil
+		})
+	}
+	for _, result := range results {
+		<-result
+	}
+
+	assert.Equal(t, []int{0, 1, 2, 3, 4}, order)
+}
+
+func TestLayerPoolSkipsCancelledJobs(t *testing.T) {
+	pool := newLayerPool(1)
+
+	// keep the only worker busy
+	release := make(chan struct{})
+	busy := make(chan layerResult, 1)
+	pool.submit(context.Background(), busy, func() (*types.Layer, error) {
+		<-release
+		return nil, nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	skipped := make(chan layerResult, 1)
+	var ran atomic.Bool
+	go pool.submit(ctx, skipped, func() (*types.Layer, error) {
+		ran.Store(true)
+		return nil, nil
+	})
+
+	cancel()
+	close(release)
+	<-busy
+
+	result := <-skipped
+	assert.ErrorIs(t, result.err, context.Canceled)
+	assert.False(t, ran.Load())
+}
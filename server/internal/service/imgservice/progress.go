@@ -0,0 +1,57 @@
+package imgservice
+
+import (
+	"io"
+	"time"
+
+	img "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/partial"
+)
+
+// downloadProgressInterval is the minimal delay between two download progress reports of a layer
+const downloadProgressInterval = 250 * time.Millisecond
+
+// uncompressedWithProgress opens the uncompressed stream of the layer, report is called with the number of
+// compressed bytes read so far at most every downloadProgressInterval and once the stream is fully read
+func uncompressedWithProgress(layer img.Layer, report func(read int64)) (io.ReadCloser, error) {
+	counted, err := partial.CompressedToLayer(&progressLayer{Layer: layer, report: report})
+	if err != nil {
+		return nil, err
+	}
+
+	return counted.Uncompressed()
+}
+
+// progressLayer counts the compressed bytes read from a layer
+type progressLayer struct {
+	img.Layer
+	report func(read int64)
+}
+
+func (l *progressLayer) Compressed() (io.ReadCloser, error) {
+	rc, err := l.Layer.Compressed()
+	if err != nil {
+		return nil, err
+	}
+
+	return &progressReader{ReadCloser: rc, report: l.report}, nil
+}
+
+type progressReader struct {
+	io.ReadCloser
+	report     func(read int64)
+	read       int64
+	lastReport time.Time
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.read += int64(n)
+
+	if err == io.EOF || time.Since(r.lastReport) >= downloadProgressInterval {
+		r.lastReport = time.Now()
+		r.report(r.read)
+	}
+
+	return n, err
+}
@@ -0,0 +1,39 @@
+package imgservice
+
+import (
+	"io"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUncompressedWithProgress(t *testing.T) {
+	layer, err := random.Layer(64*1024, types.DockerLayer)
+	require.NoError(t, err)
+
+	size, err := layer.Size()
+	require.NoError(t, err)
+
+	var reports []int64
+	r, err := uncompressedWithProgress(layer, func(read int64) {
+		reports = append(reports, read)
+	})
+	require.NoError(t, err)
+
+	uncompressed, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+
+	expected, err := layer.Uncompressed()
+	require.NoError(t, err)
+	expectedBytes, err := io.ReadAll(expected)
+	require.NoError(t, err)
+
+	assert.Equal(t, expectedBytes, uncompressed)
+	require.NotEmpty(t, reports)
+	assert.Equal(t, size, reports[len(reports)-1], "the last report covers the whole compressed layer")
+	assert.IsNonDecreasing(t, reports)
+}
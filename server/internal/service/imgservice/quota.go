@@ -0,0 +1,195 @@
+package imgservice
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sync"
+	"syscall"
+
+	"github.com/baepo-cloud/viscaufs-server/internal/types"
+	img "github.com/google/go-containerregistry/pkg/v1"
+	ggcrtypes "github.com/google/go-containerregistry/pkg/v1/types"
+)
+
+// extractedSizeFactor is the ratio between the extracted and the compressed size of a layer assumed until it is stored,
+// gzip usually shrinks the layers two to four times
+const extractedSizeFactor = 3
+
+// diskQuota accounts the space of the layers being stored against the budget of the image store, the space of the
+// stored layers is recorded in the layers table
+type diskQuota struct {
+	limit int64 // no limit when zero
+
+	mutex    sync.Mutex
+	reserved int64
+	evicting int64 // bytes selected for eviction and not deleted yet, they are counted as free
+}
+
+// reserveSpace reserves size bytes for a layer of the image, evicting the least recently used images when the
+// budget would be exceeded. The release function must be called once the layer is stored or failed.
+func (s *Service) reserveSpace(imageDigest string, size int64) (func(), error) {
+	if s.quota.limit <= 0 {
+		return func() {}, nil
+	}
+
+	release := func() {
+		s.quota.mutex.Lock()
+		s.quota.reserved -= size
+		s.quota.mutex.Unlock()
+	}
+
+	// the eviction is selected under the lock and deleted once it is released, the other reservations do not wait
+	// for the deletions
+	s.quota.mutex.Lock()
+	over, err := s.overQuota(size)
+	if err != nil {
+		s.quota.mutex.Unlock()
+		return nil, err
+	}
+	if over <= 0 {
+		s.quota.reserved += size
+		s.quota.mutex.Unlock()
+		return release, nil
+	}
+
+	eviction, err := s.gcService.SelectEviction(over, imageDigest)
+	if err != nil {
+		s.quota.mutex.Unlock()
+		return nil, err
+	}
+	s.quota.reserved += size
+	s.quota.evicting += eviction.Size
+	s.quota.mutex.Unlock()
+
+	_, evictErr := s.gcService.Evict(eviction)
+
+	s.quota.mutex.Lock()
+	defer s.quota.mutex.Unlock()
+
+	s.quota.evicting -= eviction.Size
+	if evictErr != nil {
+		s.quota.reserved -= size
+		return nil, fmt.Errorf("failed to evict images: %w", evictErr)
+	}
+
+	// the images used since their selection are kept, the reservation only stands when the budget still holds it
+	over, err = s.overQuota(0)
+	if err != nil {
+		s.quota.reserved -= size
+		return nil, err
+	}
+	if over > 0 {
+		s.quota.reserved -= size
+		return nil, fmt.Errorf("%w: %d bytes could not be freed", types.ErrDiskQuotaExceeded, over)
+	}
+
+	return release, nil
+}
+
+// overQuota returns the bytes by which storing size more bytes would exceed the budget, it must be called with the
+// quota mutex held
+func (s *Service) overQuota(size int64) (int64, error) {
+	var stored int64
+	if err := s.db.Model(&types.Layer{}).Select("coalesce(sum(size), 0)").Scan(&stored).Error; err != nil {
+		return 0, fmt.Errorf("failed to compute the stored layers size: %w", err)
+	}
+
+	return stored + s.quota.reserved - s.quota.evicting + size - s.quota.limit, nil
+}
+
+// ensureSpace makes room for the layers of the image that are not stored yet
+func (s *Service) ensureSpace(image *ImageWrapper) error {
+	if s.quota.limit <= 0 {
+		return nil
+	}
+
+	var storedDigests []string
+	err := s.db.Model(&types.Layer{}).Where("digest in ?", image.LayersDigests).Pluck("digest", &storedDigests).Error
+	if err != nil {
+		return fmt.Errorf("failed to find stored layers: %w", err)
+	}
+
+	stored := make(map[string]struct{}, len(storedDigests))
+	for _, digest := range storedDigests {
+		stored[digest] = struct{}{}
+	}
+
+	var missing int64
+	for position, layer := range image.Layers {
+		if _, ok := stored[image.LayersDigests[position]]; ok {
+			continue
+		}
+		size, err := extractedSizeEstimate(layer)
+		if err != nil {
+			return fmt.Errorf("failed to get size of layer %s: %w", image.LayersDigests[position], err)
+		}
+		missing += size
+	}
+
+	release, err := s.reserveSpace(image.Digest, missing)
+	if err != nil {
+		return err
+	}
+	release()
+
+	return nil
+}
+
+// extractedSizeEstimate estimates the space the layer takes once extracted from its compressed size, the layers
+// stored uncompressed take their own size
+func extractedSizeEstimate(layer img.Layer) (int64, error) {
+	size, err := layer.Size()
+	if err != nil {
+		return 0, err
+	}
+
+	mediaType, err := layer.MediaType()
+	if err != nil {
+		return 0, err
+	}
+
+	switch mediaType {
+	case ggcrtypes.DockerUncompressedLayer, ggcrtypes.OCIUncompressedLayer, ggcrtypes.OCIUncompressedRestrictedLayer:
+		return size, nil
+	default:
+		return size * extractedSizeFactor, nil
+	}
+}
+
+// diskUsage returns the bytes allocated on disk under path, files hard linked together are counted once
+func diskUsage(path string) (int64, error) {
+	var (
+		usage int64
+		seen  = map[uint64]struct{}{}
+	)
+
+	err := filepath.WalkDir(path, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		stat, ok := info.Sys().(*syscall.Stat_t)
+		if !ok {
+			usage += info.Size()
+			return nil
+		}
+
+		if stat.Nlink > 1 && !info.IsDir() {
+			if _, ok := seen[stat.Ino]; ok {
+				return nil
+			}
+			seen[stat.Ino] = struct{}{}
+		}
+
+		usage += stat.Blocks * 512
+		return nil
+	})
+
+	return usage, err
+}
@@ -0,0 +1,115 @@
+package imgservice
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"testing"
+
+	"github.com/baepo-cloud/viscaufs-server/internal/config"
+	"github.com/baepo-cloud/viscaufs-server/internal/types"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiskQuotaEvictsLeastRecentlyUsedImages(t *testing.T) {
+	host := newFlakyRegistry(t, &sync.Map{})
+	push := func(repository string, layers int64) (string, string) {
+		ref, err := name.ParseReference(host + "/quota/" + repository + ":latest")
+		require.NoError(t, err)
+		image, err := random.Image(1024, layers)
+		require.NoError(t, err)
+		require.NoError(t, remote.Write(ref, image))
+		return ref.String(), imageDigest(t, image)
+	}
+
+	first, _ := push("first", 2)
+	second, _ := push("second", 2)
+	third, thirdDigest := push("third", 1)
+
+	imageDir := t.TempDir()
+	h := newStoreHarnessWith(t, &config.Config{
+		SqliteDir:              t.TempDir(),
+		ImageDir:               imageDir,
+		DefaultPlatform:        "linux/amd64",
+		ImageServiceNumWorkers: 4,
+	})
+
+	prepare := func(ref string) (string, error) {
+		digest, err := h.service.Download(types.DownloadImageParams{ImageRef: ref})
+		if err != nil {
+			return digest, err
+		}
+		require.Equal(t, types.ImageEventReady, h.waitTerminal(t, digest).Kind)
+		return digest, nil
+	}
+
+	firstDigest, err := prepare(first)
+	require.NoError(t, err)
+
+	firstImage := h.image(t, firstDigest)
+	for _, layer := range firstImage.Layers {
+		usage, err := diskUsage(filepath.Join(imageDir, "layers", layer.Digest))
+		require.NoError(t, err)
+		assert.Equal(t, usage, layer.Size)
+		assert.Positive(t, layer.Size)
+	}
+
+	// the store only has room for the first image, preparing the second evicts it
+	h.service.quota.limit = layersSize(firstImage)
+	secondDigest, err := prepare(second)
+	require.NoError(t, err)
+
+	_, err = h.service.FindImage(firstDigest)
+	assert.ErrorIs(t, err, types.ErrImageNotFound)
+	for _, layer := range firstImage.Layers {
+		_, err := os.Stat(filepath.Join(imageDir, "layers", layer.Digest))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	}
+	assert.Equal(t, types.ImageStateReady, h.image(t, secondDigest).State)
+
+	// a file of the second image is open, it cannot be evicted for the third one
+	nodes := h.fsIndex.LookupByPrefix(context.Background(), secondDigest, "/")
+	require.NotEmpty(t, nodes)
+	var path string
+	for _, node := range nodes {
+		if node.Attributes.Mode&syscall.S_IFMT == syscall.S_IFREG {
+			path = node.Path
+		}
+	}
+	require.NotEmpty(t, path)
+	sessionID, _ := h.fileHandler.OpenSession("")
+	uid, err := h.fileHandler.OpenFile(context.Background(), types.OpenFileParams{ImageDigest: secondDigest, Path: path, SessionID: sessionID})
+	require.NoError(t, err)
+
+	secondImage := h.image(t, secondDigest)
+	h.service.quota.limit = layersSize(secondImage)
+	_, err = prepare(third)
+	assert.ErrorIs(t, err, types.ErrDiskQuotaExceeded)
+
+	failed := h.image(t, thirdDigest)
+	assert.Equal(t, types.ImageStateFailed, failed.State)
+	assert.Contains(t, failed.StateReason, types.ErrDiskQuotaExceeded.Error())
+	assert.Equal(t, types.ImageStateReady, h.image(t, secondDigest).State)
+
+	// once the file is released, the second image makes room for the third one
+	require.NoError(t, h.fileHandler.ReleaseFile(uid))
+	_, err = prepare(third)
+	require.NoError(t, err)
+
+	_, err = h.service.FindImage(secondDigest)
+	assert.ErrorIs(t, err, types.ErrImageNotFound)
+}
+
+func layersSize(image *types.Image) int64 {
+	var size int64
+	for _, layer := range image.Layers {
+		size += layer.Size
+	}
+	return size
+}
@@ -0,0 +1,139 @@
+package imgservice
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/baepo-cloud/viscaufs-server/internal/types"
+	img "github.com/google/go-containerregistry/pkg/v1"
+)
+
+// ResumeInterrupted recovers from a server stopped in the middle of image preparations. The layer directories
+// without a stored layer were being extracted, they are removed before returning. The preparations of the pending
+// and partial images are then resumed in the background, pulling the images by digest so a moved tag cannot change
+// them; the layers already stored are reused. The images pulled with a per-request registry token are marked failed
+// instead, the token is not persisted.
+func (s *Service) ResumeInterrupted() error {
+	if err := s.removeIncompleteLayers(); err != nil {
+		return err
+	}
+	if err := s.measureUnsizedLayers(); err != nil {
+		return err
+	}
+
+	var images []types.Image
+	err := s.db.
+		Where("state in ?", []types.ImageState{types.ImageStatePending, types.ImageStatePartial}).
+		Find(&images).Error
+	if err != nil {
+		return fmt.Errorf("failed to find interrupted images: %w", err)
+	}
+
+	if len(images) == 0 {
+		return nil
+	}
+
+	s.logger.Info("resuming interrupted image preparations", slog.Int("count", len(images)))
+	go func() {
+		for _, image := range images {
+			s.resumeImage(image)
+		}
+	}()
+
+	return nil
+}
+
+func (s *Service) resumeImage(image types.Image) {
+	logger := s.logger.With(slog.String("digest", image.Digest), slog.String("repository", image.Repository))
+
+	// the source of an import is not recorded, it cannot be read again
+	if strings.HasPrefix(image.Repository, importRepositoryPrefix) {
+		logger.Warn("interrupted import cannot be resumed")
+		s.markImageFailed(image.Digest, errors.New("import interrupted by a server restart, import the image again"))
+		return
+	}
+
+	// the registry token the image was pulled with is not persisted
+	if image.RegistryTokenRequired {
+		logger.Warn("interrupted preparation needs the registry token of its request, it cannot be resumed")
+		s.markImageFailed(image.Digest, errors.New("preparation interrupted by a server restart, credentials required, prepare the image again"))
+		return
+	}
+
+	platform := s.defaultPlatform
+	if image.Platform != "" {
+		if p, err := img.ParsePlatform(image.Platform); err == nil {
+			platform = *p
+		}
+	}
+
+	wrapper, err := s.buildImageWrapper(image.Repository+"@"+image.Digest, "", platform)
+	if err == nil {
+		_, err = s.prepare(wrapper)
+	}
+	if err != nil && !errors.Is(err, types.ErrImageDownloadAlreadyAcquired) && !errors.Is(err, types.ErrImageAlreadyPresent) {
+		logger.Error("failed to resume image preparation", slog.String("error", err.Error()))
+		s.markImageFailed(image.Digest, fmt.Errorf("failed to resume image preparation: %w", err))
+		return
+	}
+
+	logger.Info("image preparation resumed", slog.String("state", string(image.State)))
+}
+
+// removeIncompleteLayers removes the layer directories that have no stored layer, their extraction was interrupted
+func (s *Service) removeIncompleteLayers() error {
+	layersPath := filepath.Join(s.basePath, "layers")
+	entries, err := os.ReadDir(layersPath)
+	if err != nil {
+		return fmt.Errorf("failed to list layers: %w", err)
+	}
+
+	var storedDigests []string
+	if err := s.db.Model(&types.Layer{}).Pluck("digest", &storedDigests).Error; err != nil {
+		return fmt.Errorf("failed to list stored layers: %w", err)
+	}
+
+	stored := make(map[string]struct{}, len(storedDigests))
+	for _, digest := range storedDigests {
+		stored[digest] = struct{}{}
+	}
+
+	for _, entry := range entries {
+		if _, ok := stored[entry.Name()]; ok {
+			continue
+		}
+
+		s.logger.Info("removing incomplete layer", slog.String("layer_digest", entry.Name()))
+		if err := os.RemoveAll(filepath.Join(layersPath, entry.Name())); err != nil {
+			return fmt.Errorf("failed to remove incomplete layer %s: %w", entry.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+// measureUnsizedLayers records the size of the layers stored before the sizes were accounted
+func (s *Service) measureUnsizedLayers() error {
+	var layers []types.Layer
+	if err := s.db.Select("id", "digest").Where("size = 0").Find(&layers).Error; err != nil {
+		return fmt.Errorf("failed to list unsized layers: %w", err)
+	}
+
+	for _, layer := range layers {
+		size, err := diskUsage(filepath.Join(s.basePath, "layers", layer.Digest))
+		if err != nil {
+			s.logger.Warn("failed to measure layer size", slog.String("layer_digest", layer.Digest), slog.String("error", err.Error()))
+			continue
+		}
+
+		if err := s.db.Model(&types.Layer{}).Where("id = ?", layer.ID).Update("size", size).Error; err != nil {
+			return fmt.Errorf("failed to record size of layer %s: %w", layer.Digest, err)
+		}
+	}
+
+	return nil
+}
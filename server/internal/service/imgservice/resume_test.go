@@ -0,0 +1,232 @@
+package imgservice
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/baepo-cloud/viscaufs-server/internal/types"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	img "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	ggcrtypes "github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	crashHelperEnv    = "VISCAUFS_TEST_CRASH_HELPER"
+	crashSqliteDirEnv = "VISCAUFS_TEST_SQLITE_DIR"
+	crashImageDirEnv  = "VISCAUFS_TEST_IMAGE_DIR"
+	crashHomeDirEnv   = "VISCAUFS_TEST_HOME_DIR"
+	crashImageRefEnv  = "VISCAUFS_TEST_IMAGE_REF"
+)
+
+// newStallingRegistry starts an in-process registry that, while stalling is set, sends half of the stalled blob
+// and then hangs, stalled is closed once that happened
+func newStallingRegistry(t *testing.T, stalledDigest *atomic.Value, stalling *atomic.Bool) (string, <-chan struct{}) {
+	t.Helper()
+
+	var (
+		handler     = registry.New()
+		stalled     = make(chan struct{})
+		stalledOnce sync.Once
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		digest, _ := stalledDigest.Load().(string)
+		if !stalling.Load() || r.Method != http.MethodGet || !strings.HasSuffix(r.URL.Path, "/blobs/"+digest) {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, r)
+		body := recorder.Body.Bytes()
+
+		w.Header().Set("Content-Length", fmt.Sprint(len(body)))
+		w.WriteHeader(recorder.Code)
+		w.Write(body[:len(body)/2])
+		w.(http.Flusher).Flush()
+
+		stalledOnce.Do(func() { close(stalled) })
+		<-r.Context().Done()
+	}))
+	t.Cleanup(server.Close)
+
+	return strings.TrimPrefix(server.URL, "http://"), stalled
+}
+
+// newManyFilesLayer builds a layer of incompressible files so that half of its blob extracts only part of them
+func newManyFilesLayer(t *testing.T, files int) img.Layer {
+	t.Helper()
+
+	var entries []tarEntry
+	for i := 0; i < files; i++ {
+		content := make([]byte, 128*1024)
+		_, err := rand.Read(content)
+		require.NoError(t, err)
+
+		entries = append(entries, tarEntry{
+			header:  tar.Header{Name: fmt.Sprintf("file-%02d", i), Typeflag: tar.TypeReg, Mode: 0644},
+			content: string(content),
+		})
+	}
+
+	data := buildLayerTar(t, entries).Bytes()
+	layer, err := tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	})
+	require.NoError(t, err)
+
+	return layer
+}
+
+// runCrashHelper is the server process killed by TestResumeInterruptedDownload, it prepares the image and waits. Its
+// directories are created by the test, the cleanups of the killed process never run.
+func runCrashHelper(t *testing.T) {
+	cfg := storeConfig(os.Getenv(crashSqliteDirEnv), os.Getenv(crashImageDirEnv))
+	h := newStoreHarnessHome(t, cfg, os.Getenv(crashHomeDirEnv))
+
+	_, err := h.service.Download(types.DownloadImageParams{ImageRef: os.Getenv(crashImageRefEnv)})
+	require.NoError(t, err)
+
+	time.Sleep(time.Minute)
+}
+
+func TestResumeInterruptedDownload(t *testing.T) {
+	if os.Getenv(crashHelperEnv) != "" {
+		runCrashHelper(t)
+		return
+	}
+
+	var (
+		stalledDigest atomic.Value
+		stalling      atomic.Bool
+	)
+	host, stalled := newStallingRegistry(t, &stalledDigest, &stalling)
+
+	bottom, err := random.Layer(1024, ggcrtypes.DockerLayer)
+	require.NoError(t, err)
+	middle := newManyFilesLayer(t, 16)
+	top, err := random.Layer(1024, ggcrtypes.DockerLayer)
+	require.NoError(t, err)
+
+	image, err := mutate.AppendLayers(empty.Image, bottom, middle, top)
+	require.NoError(t, err)
+	ref, err := name.ParseReference(host + "/resume/image:latest")
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(ref, image))
+
+	imageDigest, err := image.Digest()
+	require.NoError(t, err)
+	middleDigest, err := middle.Digest()
+	require.NoError(t, err)
+
+	// the middle layer is killed half extracted
+	stalledDigest.Store(middleDigest.String())
+	stalling.Store(true)
+
+	sqliteDir, imageDir, homeDir := t.TempDir(), t.TempDir(), t.TempDir()
+	var output bytes.Buffer
+	cmd := exec.Command(os.Args[0], "-test.run=^TestResumeInterruptedDownload$")
+	cmd.Env = append(os.Environ(),
+		crashHelperEnv+"=1",
+		crashSqliteDirEnv+"="+sqliteDir,
+		crashImageDirEnv+"="+imageDir,
+		crashHomeDirEnv+"="+homeDir,
+		crashImageRefEnv+"="+ref.String())
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	require.NoError(t, cmd.Start())
+
+	middleContent := filepath.Join(imageDir, "layers", middleDigest.String(), "content")
+	require.Eventually(t, func() bool {
+		select {
+		case <-stalled:
+		default:
+			return false
+		}
+		entries, err := os.ReadDir(middleContent)
+		return err == nil && len(entries) > 0
+	}, 30*time.Second, 10*time.Millisecond, "the middle layer was never partially extracted: %s", &output)
+
+	require.NoError(t, cmd.Process.Kill())
+	_ = cmd.Wait()
+	stalling.Store(false)
+
+	h := newStoreHarnessIn(t, sqliteDir, imageDir)
+
+	interrupted := h.image(t, imageDigest.String())
+	assert.Contains(t, []types.ImageState{types.ImageStatePending, types.ImageStatePartial}, interrupted.State)
+	assert.Nil(t, interrupted.FindLayerByDigest(middleDigest.String()))
+
+	extracted, err := os.ReadDir(middleContent)
+	require.NoError(t, err)
+	require.Less(t, len(extracted), 16, "the middle layer must be half extracted when killed")
+
+	sentinel := filepath.Join(middleContent, "left-by-the-crash")
+	require.NoError(t, os.WriteFile(sentinel, nil, 0600))
+
+	require.NoError(t, h.service.ResumeInterrupted())
+	_, err = os.Stat(sentinel)
+	assert.ErrorIs(t, err, os.ErrNotExist, "the half extracted layer is removed before resuming")
+
+	event := h.waitTerminal(t, imageDigest.String())
+	require.Equal(t, types.ImageEventReady, event.Kind, event.Error)
+
+	ready := h.image(t, imageDigest.String())
+	assert.Equal(t, types.ImageStateReady, ready.State)
+	assert.Len(t, ready.Layers, 3)
+
+	for i := 0; i < 16; i++ {
+		path := fmt.Sprintf("/file-%02d", i)
+		assert.NotNil(t, h.fsIndex.Lookup(context.Background(), imageDigest.String(), path), path)
+
+		info, err := os.Stat(filepath.Join(middleContent, path))
+		require.NoError(t, err)
+		assert.Equal(t, int64(128*1024), info.Size())
+	}
+}
+
+func TestResumeSkipsTheImagesPulledWithARegistryToken(t *testing.T) {
+	_, ref := newAuthRegistry(t)
+	h := newStoreHarness(t)
+
+	digest, err := h.service.Download(types.DownloadImageParams{ImageRef: ref, RegistryToken: testRegistryToken})
+	require.NoError(t, err)
+	require.Equal(t, types.ImageEventReady, h.waitTerminal(t, digest).Kind)
+	assert.True(t, h.image(t, digest).RegistryTokenRequired)
+
+	// the server stopped in the middle of the preparation, the token of the request is gone
+	require.NoError(t, h.db.Model(&types.Image{}).Where("digest = ?", digest).Update("state", types.ImageStatePartial).Error)
+	require.NoError(t, h.service.ResumeInterrupted())
+
+	require.Eventually(t, func() bool {
+		return h.image(t, digest).State == types.ImageStateFailed
+	}, 10*time.Second, 10*time.Millisecond)
+	assert.Contains(t, h.image(t, digest).StateReason, "credentials required")
+
+	// preparing the image again with its token resumes it
+	_, err = h.service.Download(types.DownloadImageParams{ImageRef: ref, RegistryToken: testRegistryToken})
+	require.NoError(t, err)
+	require.Equal(t, types.ImageEventReady, h.waitTerminal(t, digest).Kind)
+}
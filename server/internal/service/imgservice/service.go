@@ -1,25 +1,24 @@
 package imgservice
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"log"
 	"log/slog"
 	"os"
-	"os/exec"
 	"path/filepath"
-	"sync"
+	"time"
 
 	"github.com/alphadose/haxmap"
 	"github.com/baepo-cloud/viscaufs-server/internal/config"
-	"github.com/baepo-cloud/viscaufs-server/internal/helper"
 	"github.com/baepo-cloud/viscaufs-server/internal/types"
+	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 	img "github.com/google/go-containerregistry/pkg/v1"
-	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/nrednav/cuid2"
+	"golang.org/x/sync/singleflight"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
@@ -27,27 +26,62 @@ import (
 // Service handles container image operations
 type Service struct {
 	basePath        string
+	importDir       string // directory images can be imported from, imports are disabled when empty
 	db              *gorm.DB
 	fsIndexService  types.FileSystemIndexService
+	progressService types.ImageProgressService
+	gcService       types.GarbageCollectorService
+	quota           diskQuota
 	pendingDownload *haxmap.Map[string, struct{}] // set of image digest
+	layerPool       *layerPool
+	layerDownloads  singleflight.Group // layer downloads by layer digest
+	keychain        authn.Keychain
+	mirrors         map[string][]config.RegistryMirror // mirrors by canonical registry name
+	defaultPlatform img.Platform
 	logger          *slog.Logger
 }
 
 var _ types.ImageService = (*Service)(nil)
 
 // NewService creates a new image service
-func NewService(cfg *config.Config, db *gorm.DB, fsIndexSvc types.FileSystemIndexService) (*Service, error) {
+func NewService(cfg *config.Config, db *gorm.DB, fsIndexSvc types.FileSystemIndexService, progressSvc types.ImageProgressService, gcSvc types.GarbageCollectorService) (*Service, error) {
 	if err := os.MkdirAll(filepath.Join(cfg.ImageDir, "layers"), 0755); err != nil {
 		return nil, err
 	}
 
-	return &Service{
+	keychain, err := newKeychain(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create registry keychain: %w", err)
+	}
+
+	mirrors, err := normalizeMirrors(cfg.RegistryMirrors)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse registry mirrors: %w", err)
+	}
+
+	defaultPlatform, err := img.ParsePlatform(cfg.DefaultPlatform)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse default platform: %w", err)
+	}
+
+	s := &Service{
 		basePath:        cfg.ImageDir,
+		importDir:       cfg.ImageImportDir,
 		db:              db,
 		fsIndexService:  fsIndexSvc,
+		progressService: progressSvc,
+		gcService:       gcSvc,
+		quota:           diskQuota{limit: cfg.ImageDiskQuota},
 		pendingDownload: haxmap.New[string, struct{}](),
+		layerPool:       newLayerPool(cfg.ImageServiceNumWorkers),
+		keychain:        keychain,
+		mirrors:         mirrors,
+		defaultPlatform: *defaultPlatform,
 		logger:          slog.New(slog.NewTextHandler(log.Writer(), nil)).With("service", "image"),
-	}, nil
+	}
+	fsIndexSvc.OnImageFailed(s.markImageFailed)
+
+	return s, nil
 }
 
 type ImageWrapper struct {
@@ -55,19 +89,34 @@ type ImageWrapper struct {
 	Image          img.Image
 	Manifest       *img.Manifest
 	Digest         string
+	IndexDigest    string
+	Platform       img.Platform
 	Layers         []img.Layer
 	LayersDigests  []string
 	ExistingLayers []types.Layer
 	ImageModel     *types.Image
+	// RegistryToken reports whether the image is pulled with a per-request registry token
+	RegistryToken bool
 }
 
 // Download downloads an image and its layers
-func (s *Service) Download(refId string) (string, error) {
-	image, err := s.buildImageWrapper(refId)
+func (s *Service) Download(params types.DownloadImageParams) (string, error) {
+	platform, err := s.resolvePlatform(params.Platform)
+	if err != nil {
+		return "", err
+	}
+
+	image, err := s.buildImageWrapper(params.ImageRef, params.RegistryToken, platform)
 	if err != nil {
 		return "", fmt.Errorf("failed to retrieve image: %w", err)
 	}
+	image.RegistryToken = params.RegistryToken != ""
+
+	return s.prepare(image)
+}
 
+// prepare records the image and starts downloading its layers in the background, unless it is already present
+func (s *Service) prepare(image *ImageWrapper) (string, error) {
 	logger := s.logger.With(
 		"reference", image.Reference.String(),
 		"digest", image.Digest,
@@ -79,8 +128,11 @@ func (s *Service) Download(refId string) (string, error) {
 		return "", fmt.Errorf("failed to check image existence: %w", err)
 	}
 
-	if imageModel != nil && len(image.Layers) == len(image.LayersDigests) {
+	if imageModel != nil && imageModel.State == types.ImageStateReady {
 		logger.Info("image already exists in local storage")
+		if _, downloading := s.pendingDownload.Get(image.Digest); !downloading {
+			s.progressService.Start(image.Digest)
+		}
 		s.fsIndexService.BuildImageIndex(imageModel, s.createDigestToPositionMap(image.LayersDigests))
 		return imageModel.Digest, types.ErrImageAlreadyPresent
 	}
@@ -99,6 +151,58 @@ func (s *Service) Download(refId string) (string, error) {
 		return "", err
 	}
 
+	if err := s.ensureSpace(image); err != nil {
+		s.pendingDownload.Del(image.Digest)
+		if errors.Is(err, types.ErrDiskQuotaExceeded) {
+			s.progressService.Start(image.Digest)
+			s.markImageFailed(image.Digest, err)
+		}
+		return "", err
+	}
+
+	// a failed preparation starts over, the layers already stored are reused
+	if imageModel.State == types.ImageStateFailed {
+		logger.Info("retrying image preparation",
+			slog.String("state", string(imageModel.State)),
+			slog.String("reason", imageModel.StateReason))
+
+		state := types.ImageStatePending
+		if len(imageModel.Layers) > 0 {
+			state = types.ImageStatePartial
+		}
+		if err := s.setImageState(image.Digest, state, ""); err != nil {
+			s.pendingDownload.Del(image.Digest)
+			return "", err
+		}
+		imageModel.State = state
+		imageModel.StateReason = ""
+	}
+
+	// the preparation of an image prepared again with or without a registry token follows the last one
+	if imageModel.RegistryTokenRequired != image.RegistryToken {
+		err := s.db.Model(&types.Image{}).
+			Where("digest = ?", image.Digest).
+			Update("registry_token_required", image.RegistryToken).Error
+		if err != nil {
+			s.pendingDownload.Del(image.Digest)
+			return "", fmt.Errorf("failed to update image: %w", err)
+		}
+		imageModel.RegistryTokenRequired = image.RegistryToken
+	}
+
+	// publish the layers before returning so that a watcher subscribing right after sees the preparation
+	s.progressService.Start(image.Digest)
+	for position, layer := range image.Layers {
+		size, _ := layer.Size()
+		s.progressService.Publish(types.ImageEvent{
+			ImageDigest:   image.Digest,
+			Kind:          types.ImageEventLayerResolved,
+			LayerDigest:   image.LayersDigests[position],
+			LayerPosition: position,
+			BytesTotal:    size,
+		})
+	}
+
 	// todo: this is dangerous
 	go func() {
 		s.downloadLayersReverseOrder(image, imageModel, logger)
@@ -118,62 +222,62 @@ func (s *Service) tryAcquireDownload(image *ImageWrapper, logger *slog.Logger) e
 	return nil
 }
 
+// downloadLayersReverseOrder queues every layer of the image on the layer pool and hands them to the indexer top down,
+// a layer is handed once every layer above it is
 func (s *Service) downloadLayersReverseOrder(imgWrapper *ImageWrapper, imageModel *types.Image, logger *slog.Logger) {
 	filesystemIndexer := s.fsIndexService.CreateImageIndexChannel(imgWrapper.Digest)
+	defer func() {
+		close(filesystemIndexer)
+		s.pendingDownload.Del(imgWrapper.Digest)
+	}()
 
-	for _, items := range helper.BackwardByBatches(imgWrapper.Layers, 1) {
-		var (
-			wg                        sync.WaitGroup
-			serializedFSIndexByDigest = haxmap.New[string, []byte]()
-		)
-
-		for _, layer := range items {
-			wg.Add(1)
-			go func(layerIndex int) {
-				defer wg.Done()
-				layerDigest := imgWrapper.LayersDigests[layerIndex]
-
-				layerModel, err := s.downloadLayer(filepath.Join(s.basePath, "layers"), layerIndex, imgWrapper, imageModel)
-				if err != nil {
-					logger.Error("failed to download layer",
-						slog.String("layer_digest", layerDigest),
-						slog.String("error", err.Error()))
-				}
-
-				serializedFSIndexByDigest.Set(layerDigest, layerModel.FsIndex)
-			}(layer.Index)
-		}
+	// the layers still queued are skipped once the image failed
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-		wg.Wait()
+	results := make([]chan layerResult, len(imgWrapper.Layers))
+	for position := range results {
+		results[position] = make(chan layerResult, 1)
+	}
 
-		for _, layer := range items {
-			position := uint8(layer.Index)
-			digest := imgWrapper.LayersDigests[layer.Index]
-			serializedFSIndex, _ := serializedFSIndexByDigest.Get(digest)
+	go func() {
+		for position := len(imgWrapper.Layers) - 1; position >= 0; position-- {
+			s.layerPool.submit(ctx, results[position], func() (*types.Layer, error) {
+				return s.downloadLayer(position, imgWrapper, imageModel)
+			})
+		}
+	}()
+
+	for position := len(imgWrapper.Layers) - 1; position >= 0; position-- {
+		digest := imgWrapper.LayersDigests[position]
 
+		result := <-results[position]
+		if result.err != nil {
+			logger.Error("failed to download layer",
+				slog.String("layer_digest", digest),
+				slog.String("error", result.err.Error()))
+			// the indexer drops the partial image index and records the failure
 			filesystemIndexer <- types.FileSystemIndexLayer{
-				Digest:         digest,
-				Position:       position,
-				SerializedData: serializedFSIndex,
+				Digest:   digest,
+				Position: uint32(position),
+				Err:      fmt.Errorf("failed to download layer %s: %w", digest, result.err),
 			}
+			return
+		}
+
+		filesystemIndexer <- types.FileSystemIndexLayer{
+			Digest:         digest,
+			Position:       uint32(position),
+			SerializedData: result.layer.FsIndex,
 		}
 	}
 
 	logger.Info("image download completed")
-	close(filesystemIndexer)
-	s.pendingDownload.Del(imgWrapper.Digest)
 }
 
-// downloadLayer downloads and extracts a single layer
-func (s *Service) downloadLayer(basePath string, position int, imgWrapper *ImageWrapper, model *types.Image) (*types.Layer, error) {
+// downloadLayer stores a single layer, unless it already is, and links it to the image
+func (s *Service) downloadLayer(position int, imgWrapper *ImageWrapper, model *types.Image) (*types.Layer, error) {
 	digest := imgWrapper.LayersDigests[position]
-	layer := imgWrapper.Layers[position]
-
-	layerPath := filepath.Join(basePath, digest)
-	contentPath := filepath.Join(layerPath, "content")
-	if err := os.MkdirAll(contentPath, 0755); err != nil {
-		return nil, err
-	}
 
 	layerModel := imgWrapper.ImageModel.FindLayerByDigest(digest)
 	if layerModel != nil {
@@ -181,53 +285,186 @@ func (s *Service) downloadLayer(basePath string, position int, imgWrapper *Image
 		return layerModel, nil
 	}
 
-	// Download the layer as tar file
-	tarFilePath := filepath.Join(contentPath, cuid2.Generate()+".tar")
-	r, err := layer.Uncompressed()
+	// a layer stored for another image must not be evicted until it is linked to this one
+	unpin := s.gcService.PinLayer(digest)
+	defer unpin()
+
+	// a layer shared by images prepared concurrently is only stored once
+	stored, err, _ := s.layerDownloads.Do(digest, func() (any, error) {
+		return s.storeLayer(position, imgWrapper)
+	})
 	if err != nil {
 		return nil, err
 	}
-	defer r.Close()
+	layerModel = stored.(*types.Layer)
+
+	if err := createImageLayer(s.db, *model, layerModel, position); err != nil {
+		return nil, err
+	}
+	s.publishLayerEvent(imgWrapper, position, types.ImageEventLayerIndexed)
+
+	if err := s.db.Model(&types.Image{}).
+		Where("digest = ? and state = ?", imgWrapper.Digest, types.ImageStatePending).
+		Update("state", types.ImageStatePartial).Error; err != nil {
+		return nil, fmt.Errorf("failed to update image state: %w", err)
+	}
+
+	return layerModel, nil
+}
 
-	file, err := os.Create(tarFilePath)
+// storeLayer downloads, extracts and indexes the layer, unless it is already stored for another image
+func (s *Service) storeLayer(position int, imgWrapper *ImageWrapper) (*types.Layer, error) {
+	digest := imgWrapper.LayersDigests[position]
+	layer := imgWrapper.Layers[position]
+	contentPath := filepath.Join(s.basePath, "layers", digest, "content")
+
+	layerModel, err := s.findLayerByDigest(digest)
 	if err != nil {
 		return nil, err
 	}
+	if layerModel != nil {
+		return layerModel, nil
+	}
 
-	// Copy contents to file
-	if _, err = io.Copy(file, r); err != nil {
-		file.Close()
+	estimate, err := extractedSizeEstimate(layer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get size of layer: %w", err)
+	}
+	release, err := s.reserveSpace(imgWrapper.Digest, estimate)
+	if err != nil {
 		return nil, err
 	}
+	defer release()
 
-	// Close the file before extraction
-	file.Close()
+	total, _ := layer.Size()
+
+	// drop what a previous failed attempt left behind
+	if err := os.RemoveAll(contentPath); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(contentPath, 0755); err != nil {
+		return nil, err
+	}
+
+	r, err := uncompressedWithProgress(layer, func(read int64) {
+		s.progressService.Publish(types.ImageEvent{
+			ImageDigest:     imgWrapper.Digest,
+			Kind:            types.ImageEventLayerDownloading,
+			LayerDigest:     digest,
+			LayerPosition:   position,
+			BytesDownloaded: read,
+			BytesTotal:      total,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
 
-	// Extract the tar file using the tar command
-	cmd := exec.Command("tar", "-xf", tarFilePath, "-C", contentPath)
-	if err := cmd.Run(); err != nil {
+	// extract and index the layer in a single streaming pass
+	layerFSIndex, err := extractLayer(r, contentPath)
+	if err != nil {
 		return nil, fmt.Errorf("failed to extract layer: %w", err)
 	}
 
 	slog.Info("layer extracted", slog.String("layer_digest", digest))
+	s.publishLayerEvent(imgWrapper, position, types.ImageEventLayerExtracted)
 
-	// Delete the tar file after extraction
-	if err := os.Remove(tarFilePath); err != nil {
-		return nil, fmt.Errorf("failed to remove tar file: %w", err)
+	serializedFSIndex, err := s.fsIndexService.RegisterLayerIndex(digest, layerFSIndex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register layer index: %w", err)
 	}
 
-	// build the layer file system index
-	serializedFSIndex, err := s.fsIndexService.BuildLayerIndex(contentPath, digest)
+	size, err := diskUsage(filepath.Dir(contentPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to measure layer size: %w", err)
+	}
 
-	// Upsert the layer into the database
-	layerModel, err = s.upsertLayer(*model, layer, serializedFSIndex, position)
+	layerModel, err = s.insertLayer(digest, serializedFSIndex, size)
 	if err != nil {
-		return nil, fmt.Errorf("failed to upsert layer: %w", err)
+		return nil, fmt.Errorf("failed to insert layer: %w", err)
 	}
 
 	return layerModel, nil
 }
 
+func (s *Service) publishLayerEvent(imgWrapper *ImageWrapper, position int, kind types.ImageEventKind) {
+	s.progressService.Publish(types.ImageEvent{
+		ImageDigest:   imgWrapper.Digest,
+		Kind:          kind,
+		LayerDigest:   imgWrapper.LayersDigests[position],
+		LayerPosition: position,
+	})
+}
+
+// findLayerByDigest returns the stored layer with the digest, nil when there is none
+func (s *Service) findLayerByDigest(digest string) (*types.Layer, error) {
+	var layer types.Layer
+	err := s.db.Where("digest = ?", digest).First(&layer).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find layer: %w", err)
+	}
+
+	return &layer, nil
+}
+
+// createImageLayer associates a stored layer with the image, it does nothing when they already are
+func createImageLayer(db *gorm.DB, i types.Image, layer *types.Layer, position int) error {
+	err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&types.ImageLayer{
+		ImageID:  i.ID,
+		LayerID:  layer.ID,
+		Position: position,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to upsert layer image relation: %w", err)
+	}
+
+	return nil
+}
+
+// setImageState persists the state of the image
+func (s *Service) setImageState(imageDigest string, state types.ImageState, reason string) error {
+	err := s.db.Model(&types.Image{}).Where("digest = ?", imageDigest).Updates(map[string]any{
+		"state":        state,
+		"state_reason": reason,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to update image state: %w", err)
+	}
+
+	return nil
+}
+
+// markImageFailed records the failure of the image preparation and notifies its watchers, the index service reports
+// its failures through it
+func (s *Service) markImageFailed(imageDigest string, cause error) {
+	if err := s.setImageState(imageDigest, types.ImageStateFailed, cause.Error()); err != nil {
+		s.logger.Error("failed to mark image as failed", slog.String("image_digest", imageDigest), slog.String("error", err.Error()))
+	}
+
+	s.progressService.Publish(types.ImageEvent{
+		ImageDigest: imageDigest,
+		Kind:        types.ImageEventFailed,
+		Error:       cause.Error(),
+	})
+}
+
+// FindImage returns the image recorded for the digest
+func (s *Service) FindImage(imageDigest string) (*types.Image, error) {
+	image, err := s.findImageByDigestID(imageDigest)
+	if err != nil {
+		return nil, err
+	}
+	if image == nil {
+		return nil, types.ErrImageNotFound
+	}
+
+	return image, nil
+}
+
 // findImageByDigestID checks if the image findImageByDigestID in the local storage and if all layers are present and valid
 func (s *Service) findImageByDigestID(digestID string) (*types.Image, error) {
 	var image types.Image
@@ -258,6 +495,12 @@ func (s *Service) upsertImage(imgWrapper *ImageWrapper) (*types.Image, error) {
 		LayerDigests: imgWrapper.LayersDigests,
 		Manifest:     string(manifestBytes),
 		Digest:       imgWrapper.Digest,
+		IndexDigest:  imgWrapper.IndexDigest,
+		Platform:     imgWrapper.Platform.String(),
+		State:        types.ImageStatePending,
+		UsedAt:       time.Now().UTC(),
+
+		RegistryTokenRequired: imgWrapper.RegistryToken,
 	}
 
 	if err := s.db.Clauses(clause.OnConflict{
@@ -270,57 +513,55 @@ func (s *Service) upsertImage(imgWrapper *ImageWrapper) (*types.Image, error) {
 	return &imageModel, nil
 }
 
-func (s *Service) upsertLayer(i types.Image, layer img.Layer, fsIndex []byte, position int) (*types.Layer, error) {
-	digest, err := layer.Digest()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get layer digest: %w", err)
-	}
-
+func (s *Service) insertLayer(digest string, fsIndex []byte, size int64) (*types.Layer, error) {
 	layerModel := types.Layer{
 		ID:      cuid2.Generate(),
-		Digest:  digest.String(),
+		Digest:  digest,
 		FsIndex: fsIndex,
+		Size:    size,
 	}
 
-	err = s.db.Transaction(func(tx *gorm.DB) error {
-		if err := s.db.Clauses(clause.OnConflict{
-			Columns:   []clause.Column{{Name: "digest"}},
-			DoNothing: true,
-		}).Create(&layerModel).Error; err != nil {
-			return fmt.Errorf("failed to upsert layer: %w", err)
-		}
-
-		err = s.db.Create(&types.ImageLayer{
-			ImageID:  i.ID,
-			LayerID:  layerModel.ID,
-			Position: position,
-		}).Error
-
-		if err != nil {
-			return fmt.Errorf("failed to upsert layer image relation: %w", err)
-		}
-
-		return nil
-	})
+	if err := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "digest"}},
+		DoNothing: true,
+	}).Create(&layerModel).Error; err != nil {
+		return nil, fmt.Errorf("failed to upsert layer: %w", err)
+	}
 
-	if err != nil {
-		return nil, fmt.Errorf("failed to associate layer with image: %w", err)
+	// the layer may have been stored by a previous server run, return the stored one
+	if err := s.db.Where("digest = ?", digest).First(&layerModel).Error; err != nil {
+		return nil, fmt.Errorf("failed to find layer: %w", err)
 	}
 
 	return &layerModel, nil
 }
 
-func (s *Service) buildImageWrapper(id string) (*ImageWrapper, error) {
+func (s *Service) buildImageWrapper(id, registryToken string, platform img.Platform) (*ImageWrapper, error) {
 	reference, err := name.ParseReference(id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse reference: %w", err)
 	}
 
-	image, err := remote.Image(reference)
+	descriptor, err := s.fetchDescriptor(reference, registryToken, platform)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch image: %w", err)
 	}
 
+	// for an index, Image resolves the child manifest matching the platform
+	image, err := descriptor.Image()
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve image for platform %s: %w", platform.String(), err)
+	}
+
+	var indexDigest string
+	if descriptor.MediaType.IsIndex() {
+		indexDigest = descriptor.Digest.String()
+	}
+
+	return newImageWrapper(reference, image, indexDigest, imagePlatform(image, platform))
+}
+
+func newImageWrapper(reference name.Reference, image img.Image, indexDigest string, platform img.Platform) (*ImageWrapper, error) {
 	layers, err := image.Layers()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get layers: %w", err)
@@ -352,13 +593,42 @@ func (s *Service) buildImageWrapper(id string) (*ImageWrapper, error) {
 		LayersDigests: layersDigests,
 		Manifest:      manifest,
 		Digest:        digest.String(),
+		IndexDigest:   indexDigest,
+		Platform:      platform,
+	}, nil
+}
+
+// imagePlatform returns the platform recorded in the image config, or fallback when the config does not have one
+func imagePlatform(image img.Image, fallback img.Platform) img.Platform {
+	configFile, err := image.ConfigFile()
+	if err != nil || configFile.OS == "" {
+		return fallback
+	}
+
+	return img.Platform{OS: configFile.OS, Architecture: configFile.Architecture, Variant: configFile.Variant}
+}
+
+// resolvePlatform returns the requested platform or the server default one when none is requested, a platform
+// missing its os or architecture is rejected rather than completed with the default one
+func (s *Service) resolvePlatform(platform *types.Platform) (img.Platform, error) {
+	if platform == nil || *platform == (types.Platform{}) {
+		return s.defaultPlatform, nil
+	}
+	if platform.OS == "" || platform.Architecture == "" {
+		return img.Platform{}, fmt.Errorf("%w: os and architecture are both required", types.ErrInvalidPlatform)
+	}
+
+	return img.Platform{
+		OS:           platform.OS,
+		Architecture: platform.Architecture,
+		Variant:      platform.Variant,
 	}, nil
 }
 
-func (s *Service) createDigestToPositionMap(layers []string) map[string]uint8 {
-	digestToPosition := make(map[string]uint8)
+func (s *Service) createDigestToPositionMap(layers []string) map[string]uint32 {
+	digestToPosition := make(map[string]uint32)
 	for i, digest := range layers {
-		digestToPosition[digest] = uint8(i)
+		digestToPosition[digest] = uint32(i)
 	}
 	return digestToPosition
 }
@@ -0,0 +1,306 @@
+package imgservice
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/baepo-cloud/viscaufs-server/internal/config"
+	"github.com/baepo-cloud/viscaufs-server/internal/fxutil"
+	"github.com/baepo-cloud/viscaufs-server/internal/service/filehandlerservice"
+	"github.com/baepo-cloud/viscaufs-server/internal/service/fsindexservice"
+	"github.com/baepo-cloud/viscaufs-server/internal/service/gcservice"
+	"github.com/baepo-cloud/viscaufs-server/internal/service/progressservice"
+	"github.com/baepo-cloud/viscaufs-server/internal/service/statsservice"
+	"github.com/baepo-cloud/viscaufs-server/internal/types"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	ggcrtypes "github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type storeHarness struct {
+	service     *Service
+	db          *gorm.DB
+	fsIndex     *fsindexservice.Service
+	progress    *progressservice.Service
+	fileHandler *filehandlerservice.Service
+}
+
+// newStoreHarness wires the image service with a real database, index and progress service
+func newStoreHarness(t *testing.T) *storeHarness {
+	t.Helper()
+
+	return newStoreHarnessIn(t, t.TempDir(), t.TempDir())
+}
+
+// newStoreHarnessIn is newStoreHarness storing the database and the images in the given directories
+func newStoreHarnessIn(t *testing.T, sqliteDir, imageDir string) *storeHarness {
+	t.Helper()
+
+	return newStoreHarnessWith(t, storeConfig(sqliteDir, imageDir))
+}
+
+func storeConfig(sqliteDir, imageDir string) *config.Config {
+	return &config.Config{
+		SqliteDir:       sqliteDir,
+		ImageDir:        imageDir,
+		DefaultPlatform: "linux/amd64",
+
+		ImageServiceNumWorkers: 4,
+	}
+}
+
+// newStoreHarnessWith is newStoreHarness with the given configuration
+func newStoreHarnessWith(t *testing.T, cfg *config.Config) *storeHarness {
+	t.Helper()
+
+	return newStoreHarnessHome(t, cfg, t.TempDir())
+}
+
+// newStoreHarnessHome is newStoreHarnessWith with the given home directory, so that no registry credentials of the
+// host are used. A process killed by its test creates no directory of its own, they would never be cleaned up.
+func newStoreHarnessHome(t *testing.T, cfg *config.Config, home string) *storeHarness {
+	t.Helper()
+
+	t.Setenv("HOME", home)
+	t.Setenv("DOCKER_CONFIG", "")
+
+	db, err := fxutil.ProvideGORM(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		sqlDB, err := db.DB()
+		if err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	progress := progressservice.NewService()
+	fsIndex := fsindexservice.NewService(db, progress)
+	fileHandler, err := filehandlerservice.NewService(cfg, db, fsIndex, statsservice.NewService(db))
+	require.NoError(t, err)
+	gc := gcservice.NewService(cfg, db, fsIndex, fileHandler)
+	service, err := NewService(cfg, db, fsIndex, progress, gc)
+	require.NoError(t, err)
+
+	return &storeHarness{service: service, db: db, fsIndex: fsIndex, progress: progress, fileHandler: fileHandler}
+}
+
+// waitTerminal waits for the ready or failed event of the image, the outcome of a preparation that already ended is
+// read from the image state like the watchers do
+func (h *storeHarness) waitTerminal(t *testing.T, imageDigest string) types.ImageEvent {
+	t.Helper()
+
+	history, events, cancel := h.progress.Subscribe(imageDigest)
+	defer cancel()
+
+	if len(history) == 0 {
+		switch image := h.image(t, imageDigest); image.State {
+		case types.ImageStateReady:
+			return types.ImageEvent{ImageDigest: imageDigest, Kind: types.ImageEventReady}
+		case types.ImageStateFailed:
+			return types.ImageEvent{ImageDigest: imageDigest, Kind: types.ImageEventFailed, Error: image.StateReason}
+		}
+	}
+
+	timeout := time.After(30 * time.Second)
+	for {
+		select {
+		case event, ok := <-events:
+			require.True(t, ok, "subscription dropped")
+			if event.Kind.IsTerminal() {
+				return event
+			}
+		case <-timeout:
+			t.Fatalf("image %s did not reach a terminal state", imageDigest)
+		}
+	}
+}
+
+func (h *storeHarness) image(t *testing.T, imageDigest string) *types.Image {
+	t.Helper()
+
+	image, err := h.service.FindImage(imageDigest)
+	require.NoError(t, err)
+	return image
+}
+
+// newFlakyRegistry starts an in-process registry answering 404 to the downloads of the failing blobs
+func newFlakyRegistry(t *testing.T, failing *sync.Map) string {
+	t.Helper()
+
+	handler := registry.New()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, digest, ok := strings.Cut(r.URL.Path, "/blobs/"); ok && r.Method == http.MethodGet {
+			if _, fail := failing.Load(digest); fail {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	return strings.TrimPrefix(server.URL, "http://")
+}
+
+func TestFailedImageCanBePreparedAgain(t *testing.T) {
+	failing := &sync.Map{}
+	host := newFlakyRegistry(t, failing)
+
+	ref, err := name.ParseReference(host + "/state/image:latest")
+	require.NoError(t, err)
+	image, err := random.Image(1024, 3)
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(ref, image))
+
+	layers, err := image.Layers()
+	require.NoError(t, err)
+	bottomDigest, err := layers[0].Digest()
+	require.NoError(t, err)
+
+	h := newStoreHarness(t)
+
+	// the bottom layer is downloaded last, the top layers are stored before the failure
+	failing.Store(bottomDigest.String(), struct{}{})
+	digest, err := h.service.Download(types.DownloadImageParams{ImageRef: ref.String()})
+	require.NoError(t, err)
+
+	event := h.waitTerminal(t, digest)
+	assert.Equal(t, types.ImageEventFailed, event.Kind)
+	assert.Contains(t, event.Error, bottomDigest.String())
+
+	failed := h.image(t, digest)
+	assert.Equal(t, types.ImageStateFailed, failed.State)
+	assert.Contains(t, failed.StateReason, bottomDigest.String())
+	assert.Len(t, failed.Layers, 2)
+	assert.Nil(t, failed.FsIndex)
+	assert.False(t, h.fsIndex.Ready(digest))
+
+	// preparing the failed image again retries it
+	failing.Delete(bottomDigest.String())
+	retryDigest, err := h.service.Download(types.DownloadImageParams{ImageRef: ref.String()})
+	require.NoError(t, err)
+	assert.Equal(t, digest, retryDigest)
+
+	event = h.waitTerminal(t, digest)
+	assert.Equal(t, types.ImageEventReady, event.Kind)
+
+	ready := h.image(t, digest)
+	assert.Equal(t, types.ImageStateReady, ready.State)
+	assert.Empty(t, ready.StateReason)
+	assert.Len(t, ready.Layers, 3)
+	assert.NotNil(t, ready.FsIndex)
+	assert.True(t, h.fsIndex.Ready(digest))
+
+	_, err = h.service.Download(types.DownloadImageParams{ImageRef: ref.String()})
+	assert.ErrorIs(t, err, types.ErrImageAlreadyPresent)
+}
+
+func TestLayersAreSharedBetweenImages(t *testing.T) {
+	failing := &sync.Map{}
+	host := newFlakyRegistry(t, failing)
+
+	base, err := random.Image(1024, 2)
+	require.NoError(t, err)
+	baseRef, err := name.ParseReference(host + "/state/base:latest")
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(baseRef, base))
+
+	extra, err := random.Layer(512, ggcrtypes.DockerLayer)
+	require.NoError(t, err)
+	child, err := mutate.AppendLayers(base, extra)
+	require.NoError(t, err)
+	childRef, err := name.ParseReference(host + "/state/child:latest")
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(childRef, child))
+
+	h := newStoreHarness(t)
+
+	baseDigest, err := h.service.Download(types.DownloadImageParams{ImageRef: baseRef.String()})
+	require.NoError(t, err)
+	require.Equal(t, types.ImageEventReady, h.waitTerminal(t, baseDigest).Kind)
+
+	// the layers of base are never downloaded again
+	baseLayers, err := base.Layers()
+	require.NoError(t, err)
+	for _, layer := range baseLayers {
+		digest, err := layer.Digest()
+		require.NoError(t, err)
+		failing.Store(digest.String(), struct{}{})
+	}
+
+	childDigest, err := h.service.Download(types.DownloadImageParams{ImageRef: childRef.String()})
+	require.NoError(t, err)
+	require.Equal(t, types.ImageEventReady, h.waitTerminal(t, childDigest).Kind)
+
+	var count int64
+	require.NoError(t, h.db.Model(&types.Layer{}).Count(&count).Error)
+	assert.Equal(t, int64(3), count)
+	assert.Len(t, h.image(t, childDigest).Layers, 3)
+}
+
+func TestConcurrentImagesShareLayerDownloads(t *testing.T) {
+	var downloads sync.Map // layer digest -> *atomic.Int32
+	handler := registry.New()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, digest, ok := strings.Cut(r.URL.Path, "/blobs/"); ok && r.Method == http.MethodGet {
+			counter, _ := downloads.LoadOrStore(digest, &atomic.Int32{})
+			counter.(*atomic.Int32).Add(1)
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	base, err := random.Image(64*1024, 3)
+	require.NoError(t, err)
+
+	var refs []string
+	for i := 0; i < 4; i++ {
+		extra, err := random.Layer(1024, ggcrtypes.DockerLayer)
+		require.NoError(t, err)
+		image, err := mutate.AppendLayers(base, extra)
+		require.NoError(t, err)
+
+		ref, err := name.ParseReference(fmt.Sprintf("%s/concurrent/image-%d:latest", host, i))
+		require.NoError(t, err)
+		require.NoError(t, remote.Write(ref, image))
+		refs = append(refs, ref.String())
+	}
+	downloads.Clear()
+
+	h := newStoreHarness(t)
+
+	var digests []string
+	for _, ref := range refs {
+		digest, err := h.service.Download(types.DownloadImageParams{ImageRef: ref})
+		require.NoError(t, err)
+		digests = append(digests, digest)
+	}
+
+	for _, digest := range digests {
+		event := h.waitTerminal(t, digest)
+		require.Equal(t, types.ImageEventReady, event.Kind, event.Error)
+		assert.Len(t, h.image(t, digest).Layers, 4)
+	}
+
+	var count int64
+	require.NoError(t, h.db.Model(&types.Layer{}).Count(&count).Error)
+	assert.Equal(t, int64(3+4), count)
+
+	downloads.Range(func(digest, counter any) bool {
+		assert.Equal(t, int32(1), counter.(*atomic.Int32).Load(), "layer %s downloaded more than once", digest)
+		return true
+	})
+}
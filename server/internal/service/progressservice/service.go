@@ -0,0 +1,110 @@
+package progressservice
+
+import (
+	"sync"
+
+	"github.com/baepo-cloud/viscaufs-server/internal/types"
+)
+
+// subscriberBufferSize is the number of events a subscriber can lag behind before being dropped
+const subscriberBufferSize = 64
+
+// Service fans out image preparation events to the watchers of each image
+type Service struct {
+	mu          sync.Mutex
+	history     map[string][]types.ImageEvent // events of the preparations in progress by image digest
+	subscribers map[string]map[*subscriber]struct{}
+}
+
+type subscriber struct {
+	events chan types.ImageEvent
+	closed bool
+}
+
+var _ types.ImageProgressService = (*Service)(nil)
+
+// NewService creates a new progress service
+func NewService() *Service {
+	return &Service{
+		history:     make(map[string][]types.ImageEvent),
+		subscribers: make(map[string]map[*subscriber]struct{}),
+	}
+}
+
+func (s *Service) Start(imageDigest string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.history, imageDigest)
+}
+
+// Publish records the event and sends it to the subscribers of the image without blocking: download progress is
+// skipped for a subscriber whose buffer is full, any other event drops the subscriber since it would miss it. The
+// history of an image is dropped once its preparation ends, its outcome is then read from the image state.
+func (s *Service) Publish(event types.ImageEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if event.Kind.IsTerminal() {
+		delete(s.history, event.ImageDigest)
+	} else {
+		s.history[event.ImageDigest] = appendEvent(s.history[event.ImageDigest], event)
+	}
+
+	for sub := range s.subscribers[event.ImageDigest] {
+		select {
+		case sub.events <- event:
+		default:
+			if event.Kind != types.ImageEventLayerDownloading {
+				s.removeLocked(event.ImageDigest, sub)
+			}
+		}
+	}
+}
+
+func (s *Service) Subscribe(imageDigest string) ([]types.ImageEvent, <-chan types.ImageEvent, func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub := &subscriber{events: make(chan types.ImageEvent, subscriberBufferSize)}
+	if s.subscribers[imageDigest] == nil {
+		s.subscribers[imageDigest] = make(map[*subscriber]struct{})
+	}
+	s.subscribers[imageDigest][sub] = struct{}{}
+
+	history := make([]types.ImageEvent, len(s.history[imageDigest]))
+	copy(history, s.history[imageDigest])
+
+	return history, sub.events, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.removeLocked(imageDigest, sub)
+	}
+}
+
+func (s *Service) removeLocked(imageDigest string, sub *subscriber) {
+	if sub.closed {
+		return
+	}
+
+	sub.closed = true
+	close(sub.events)
+	delete(s.subscribers[imageDigest], sub)
+	if len(s.subscribers[imageDigest]) == 0 {
+		delete(s.subscribers, imageDigest)
+	}
+}
+
+// appendEvent appends the event to the history, only the latest download progress of a layer is kept
+func appendEvent(history []types.ImageEvent, event types.ImageEvent) []types.ImageEvent {
+	if event.Kind == types.ImageEventLayerDownloading {
+		for i := len(history) - 1; i >= 0; i-- {
+			if history[i].Kind == types.ImageEventLayerDownloading && history[i].LayerDigest == event.LayerDigest {
+				history[i] = event
+				return history
+			}
+		}
+	}
+
+	return append(history, event)
+}
@@ -0,0 +1,101 @@
+package progressservice
+
+import (
+	"testing"
+
+	"github.com/baepo-cloud/viscaufs-server/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDigest = "sha256:image"
+
+func downloading(read int64) types.ImageEvent {
+	return types.ImageEvent{
+		ImageDigest:     testDigest,
+		Kind:            types.ImageEventLayerDownloading,
+		LayerDigest:     "sha256:layer",
+		BytesDownloaded: read,
+		BytesTotal:      100,
+	}
+}
+
+func TestSubscribeReplaysHistory(t *testing.T) {
+	s := NewService()
+	s.Start(testDigest)
+
+	s.Publish(types.ImageEvent{ImageDigest: testDigest, Kind: types.ImageEventLayerResolved, LayerDigest: "sha256:layer"})
+	s.Publish(downloading(10))
+	s.Publish(downloading(60))
+	s.Publish(types.ImageEvent{ImageDigest: "sha256:other", Kind: types.ImageEventReady})
+
+	history, events, cancel := s.Subscribe(testDigest)
+	defer cancel()
+
+	require.Len(t, history, 2, "download progress of a layer is coalesced")
+	assert.Equal(t, types.ImageEventLayerResolved, history[0].Kind)
+	assert.Equal(t, int64(60), history[1].BytesDownloaded)
+
+	s.Publish(types.ImageEvent{ImageDigest: testDigest, Kind: types.ImageEventReady})
+	event := <-events
+	assert.Equal(t, types.ImageEventReady, event.Kind)
+	assert.True(t, event.Kind.IsTerminal())
+
+	s.Start(testDigest)
+	history, _, cancelAfterStart := s.Subscribe(testDigest)
+	defer cancelAfterStart()
+	assert.Empty(t, history)
+}
+
+func TestHistoryIsDroppedAtTheEnd(t *testing.T) {
+	s := NewService()
+	s.Start(testDigest)
+
+	s.Publish(types.ImageEvent{ImageDigest: testDigest, Kind: types.ImageEventLayerResolved, LayerDigest: "sha256:layer"})
+	s.Publish(downloading(10))
+	_, events, cancel := s.Subscribe(testDigest)
+	defer cancel()
+
+	s.Publish(types.ImageEvent{ImageDigest: testDigest, Kind: types.ImageEventFailed, Error: "boom"})
+	event := <-events
+	assert.Equal(t, types.ImageEventFailed, event.Kind, "the subscribers still receive the terminal event")
+
+	assert.Empty(t, s.history)
+	history, _, cancelAfterEnd := s.Subscribe(testDigest)
+	defer cancelAfterEnd()
+	assert.Empty(t, history)
+}
+
+func TestSlowSubscriber(t *testing.T) {
+	s := NewService()
+	_, events, cancel := s.Subscribe(testDigest)
+	defer cancel()
+
+	// download progress never drops the subscriber
+	for i := 0; i < subscriberBufferSize*2; i++ {
+		s.Publish(downloading(int64(i)))
+	}
+	assert.Len(t, events, subscriberBufferSize)
+
+	// an event the subscriber would miss drops it
+	s.Publish(types.ImageEvent{ImageDigest: testDigest, Kind: types.ImageEventReady})
+	for range events {
+	}
+	_, ok := <-events
+	assert.False(t, ok)
+}
+
+func TestCancel(t *testing.T) {
+	s := NewService()
+	_, events, cancel := s.Subscribe(testDigest)
+
+	cancel()
+	cancel()
+
+	_, ok := <-events
+	assert.False(t, ok)
+	assert.NotPanics(t, func() {
+		s.Publish(types.ImageEvent{ImageDigest: testDigest, Kind: types.ImageEventReady})
+	})
+	assert.Empty(t, s.subscribers)
+}
@@ -0,0 +1,184 @@
+package statsservice
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/baepo-cloud/viscaufs-server/internal/types"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// startupWindow is the part of a run whose reads make the prefetch profile
+	startupWindow = 10 * time.Second
+	// prefetchBlockSize is the granularity of the recorded reads
+	prefetchBlockSize = 128 << 10
+	// maxPrefetchBytes bounds the ranges of a profile, the files accessed last are only listed past it
+	maxPrefetchBytes = 64 << 20
+)
+
+type startupKey struct {
+	path  string
+	block int64
+}
+
+// startupRun records the blocks a run of an image read during its startup
+type startupRun struct {
+	imageDigest string
+	start       time.Time
+	// offsets holds the delay between the start and the first read of each block
+	offsets map[startupKey]time.Duration
+}
+
+// StartRun starts recording the startup of a run of the image, a run already recording is kept: the runs of an
+// image starting together are one startup
+func (s *Service) StartRun(imageDigest string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if run, ok := s.runs[imageDigest]; ok {
+		if time.Since(run.start) < startupWindow {
+			return
+		}
+		s.ended = append(s.ended, run)
+	}
+
+	s.runs[imageDigest] = &startupRun{
+		imageDigest: imageDigest,
+		start:       time.Now(),
+		offsets:     make(map[startupKey]time.Duration),
+	}
+}
+
+// recordStartup records the blocks read by the run of the image, if it is in its startup, the caller holds the mutex
+func (s *Service) recordStartup(imageDigest, path string, offset, bytes int64) {
+	run, ok := s.runs[imageDigest]
+	if !ok {
+		return
+	}
+
+	elapsed := time.Since(run.start)
+	if elapsed >= startupWindow {
+		return
+	}
+
+	first, last := int64(-1), int64(-1)
+	if bytes > 0 {
+		first, last = offset/prefetchBlockSize, (offset+bytes-1)/prefetchBlockSize
+	}
+
+	for block := first; block <= last; block++ {
+		key := startupKey{path: path, block: block}
+		if _, ok := run.offsets[key]; !ok {
+			run.offsets[key] = elapsed
+		}
+	}
+}
+
+// flushStartups adds the runs past their startup window to the stored startup blocks
+func (s *Service) flushStartups() error {
+	s.mutex.Lock()
+	for imageDigest, run := range s.runs {
+		if time.Since(run.start) >= startupWindow {
+			s.ended = append(s.ended, run)
+			delete(s.runs, imageDigest)
+		}
+	}
+	ended := s.ended
+	s.ended = nil
+	s.mutex.Unlock()
+
+	if len(ended) == 0 {
+		return nil
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		for _, run := range ended {
+			if len(run.offsets) == 0 {
+				continue
+			}
+
+			blocks := make([]types.StartupBlock, 0, len(run.offsets))
+			for key, offset := range run.offsets {
+				blocks = append(blocks, types.StartupBlock{
+					ImageDigest: run.imageDigest,
+					Path:        key.path,
+					Block:       key.block,
+					Runs:        1,
+					OffsetMsSum: offset.Milliseconds(),
+				})
+			}
+
+			err := tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "image_digest"}, {Name: "path"}, {Name: "block"}},
+				DoUpdates: clause.Assignments(map[string]any{
+					"runs":          gorm.Expr("runs + excluded.runs"),
+					"offset_ms_sum": gorm.Expr("offset_ms_sum + excluded.offset_ms_sum"),
+				}),
+			}).CreateInBatches(blocks, 500).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s.mutex.Lock()
+		s.ended = append(ended, s.ended...)
+		s.mutex.Unlock()
+
+		return fmt.Errorf("failed to store startup blocks: %w", err)
+	}
+
+	return nil
+}
+
+// PrefetchProfile builds the profile of the image from the startups of its previous runs: the files come in the
+// order of their average first access, and their blocks are merged into ranges
+func (s *Service) PrefetchProfile(imageDigest string) (*types.PrefetchProfile, error) {
+	var blocks []types.StartupBlock
+	err := s.db.Where("image_digest = ?", imageDigest).
+		Order("offset_ms_sum * 1.0 / runs, path, block").
+		Find(&blocks).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find startup blocks: %w", err)
+	}
+
+	var (
+		files      []types.PrefetchFile
+		positions  = make(map[string]int)
+		fileBlocks = make(map[string][]int64)
+		budget     = int64(maxPrefetchBytes)
+	)
+	for _, block := range blocks {
+		if _, ok := positions[block.Path]; !ok {
+			positions[block.Path] = len(files)
+			files = append(files, types.PrefetchFile{Path: block.Path})
+		}
+
+		if block.Block >= 0 && budget >= prefetchBlockSize {
+			fileBlocks[block.Path] = append(fileBlocks[block.Path], block.Block)
+			budget -= prefetchBlockSize
+		}
+	}
+
+	for path, indexes := range fileBlocks {
+		slices.Sort(indexes)
+
+		var ranges []types.PrefetchRange
+		for _, index := range indexes {
+			offset := index * prefetchBlockSize
+			if n := len(ranges); n > 0 && ranges[n-1].Offset+ranges[n-1].Length == offset {
+				ranges[n-1].Length += prefetchBlockSize
+				continue
+			}
+			ranges = append(ranges, types.PrefetchRange{Offset: offset, Length: prefetchBlockSize})
+		}
+
+		files[positions[path]].Ranges = ranges
+	}
+
+	return &types.PrefetchProfile{Files: files, BlockSize: prefetchBlockSize}, nil
+}
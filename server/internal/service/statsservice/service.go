@@ -0,0 +1,209 @@
+package statsservice
+
+import (
+	"fmt"
+	"log"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/baepo-cloud/viscaufs-server/internal/types"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// flushInterval is the delay between two writes of the aggregated accesses
+const flushInterval = 30 * time.Second
+
+type fileKey struct {
+	imageDigest string
+	path        string
+}
+
+type Service struct {
+	db     *gorm.DB
+	logger *slog.Logger
+
+	mutex   sync.Mutex
+	pending map[fileKey]*types.FileStat // accesses since the last flush
+	runs    map[string]*startupRun      // runs in their startup window by image digest
+	ended   []*startupRun               // runs past their startup window, not flushed yet
+
+	// flushMutex keeps the flushes in order, a file first accessed in a flush is inserted before it is updated
+	flushMutex sync.Mutex
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+var _ types.FileStatsService = (*Service)(nil)
+
+// NewService creates a new file statistics service
+func NewService(db *gorm.DB) *Service {
+	return &Service{
+		db:      db,
+		logger:  slog.New(slog.NewTextHandler(log.Writer(), nil)).With("service", "stats"),
+		pending: make(map[fileKey]*types.FileStat),
+		runs:    make(map[string]*startupRun),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+func (s *Service) RecordOpen(imageDigest, layerDigest, path string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.record(imageDigest, layerDigest, path, 1, 0)
+	s.recordStartup(imageDigest, path, -1, 0)
+}
+
+func (s *Service) RecordRead(imageDigest, layerDigest, path string, offset int64, bytes int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.record(imageDigest, layerDigest, path, 0, int64(bytes))
+	s.recordStartup(imageDigest, path, offset, int64(bytes))
+}
+
+// record aggregates an access to a file, the caller holds the mutex
+func (s *Service) record(imageDigest, layerDigest, path string, opens, bytes int64) {
+	key := fileKey{imageDigest: imageDigest, path: path}
+
+	stat, ok := s.pending[key]
+	if !ok {
+		stat = &types.FileStat{
+			ImageDigest:     imageDigest,
+			Path:            path,
+			FirstAccessedAt: time.Now().UTC(),
+		}
+		s.pending[key] = stat
+	}
+
+	stat.LayerDigest = layerDigest
+	stat.OpenCount += opens
+	stat.BytesRead += bytes
+}
+
+// Start flushes the aggregated accesses periodically in the background
+func (s *Service) Start() {
+	go func() {
+		defer close(s.done)
+
+		ticker := time.NewTicker(flushInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				if err := s.flush(); err != nil {
+					s.logger.Error("failed to flush file statistics", slog.Any("error", err))
+				}
+			}
+		}
+	}()
+}
+
+// Stop stops the background flushes and writes the pending accesses
+func (s *Service) Stop() {
+	close(s.stop)
+	<-s.done
+
+	if err := s.flush(); err != nil {
+		s.logger.Error("failed to flush file statistics", slog.Any("error", err))
+	}
+}
+
+// flush adds the accesses aggregated since the previous flush to the stored statistics
+func (s *Service) flush() error {
+	s.flushMutex.Lock()
+	defer s.flushMutex.Unlock()
+
+	if err := s.flushStartups(); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	pending := s.pending
+	s.pending = make(map[fileKey]*types.FileStat)
+	s.mutex.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	stats := make([]*types.FileStat, 0, len(pending))
+	for _, stat := range pending {
+		stats = append(stats, stat)
+	}
+
+	// the first access time of a stored file is kept
+	err := s.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "image_digest"}, {Name: "path"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"layer_digest": gorm.Expr("excluded.layer_digest"),
+			"open_count":   gorm.Expr("open_count + excluded.open_count"),
+			"bytes_read":   gorm.Expr("bytes_read + excluded.bytes_read"),
+		}),
+	}).CreateInBatches(stats, 500).Error
+	if err != nil {
+		// keep the accesses for the next flush
+		s.mutex.Lock()
+		for key, stat := range pending {
+			if current, ok := s.pending[key]; ok {
+				stat.LayerDigest = current.LayerDigest
+				stat.OpenCount += current.OpenCount
+				stat.BytesRead += current.BytesRead
+			}
+			s.pending[key] = stat
+		}
+		s.mutex.Unlock()
+
+		return fmt.Errorf("failed to store file statistics: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) GetImageStats(imageDigest string, limit int) (*types.ImageStats, error) {
+	// the statistics include the accesses not flushed yet
+	if err := s.flush(); err != nil {
+		return nil, err
+	}
+
+	stats := &types.ImageStats{}
+
+	err := s.db.Where("image_digest = ?", imageDigest).
+		Order("bytes_read desc, open_count desc, path").
+		Limit(limit).
+		Find(&stats.HotFiles).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find hot files: %w", err)
+	}
+
+	err = s.db.Where("image_digest = ?", imageDigest).
+		Order("first_accessed_at, path").
+		Limit(limit).
+		Find(&stats.AccessOrder).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find access order: %w", err)
+	}
+
+	var totals struct {
+		BytesRead int64
+		OpenCount int64
+	}
+	err = s.db.Model(&types.FileStat{}).
+		Select("coalesce(sum(bytes_read), 0) as bytes_read, coalesce(sum(open_count), 0) as open_count").
+		Where("image_digest = ?", imageDigest).
+		Scan(&totals).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum file statistics: %w", err)
+	}
+
+	stats.TotalBytesRead = totals.BytesRead
+	stats.TotalOpenCount = totals.OpenCount
+	return stats, nil
+}
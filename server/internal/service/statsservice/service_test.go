@@ -0,0 +1,136 @@
+package statsservice
+
+import (
+	"testing"
+	"time"
+
+	"github.com/baepo-cloud/viscaufs-server/internal/config"
+	"github.com/baepo-cloud/viscaufs-server/internal/fxutil"
+	"github.com/baepo-cloud/viscaufs-server/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestService(t *testing.T) *Service {
+	t.Helper()
+
+	db, err := fxutil.ProvideGORM(&config.Config{SqliteDir: t.TempDir()})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		sqlDB, err := db.DB()
+		if err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	return NewService(db)
+}
+
+func paths(stats []types.FileStat) []string {
+	result := make([]string, len(stats))
+	for i, stat := range stats {
+		result[i] = stat.Path
+	}
+	return result
+}
+
+func TestGetImageStats(t *testing.T) {
+	s := newTestService(t)
+
+	s.RecordOpen("sha256:image", "sha256:base", "/bin/sh")
+	s.RecordRead("sha256:image", "sha256:base", "/bin/sh", 0, 4096)
+	time.Sleep(time.Millisecond)
+	s.RecordOpen("sha256:image", "sha256:base", "/etc/passwd")
+	s.RecordRead("sha256:image", "sha256:base", "/etc/passwd", 0, 100)
+	s.RecordOpen("sha256:other", "sha256:base", "/bin/sh")
+	s.RecordRead("sha256:other", "sha256:base", "/bin/sh", 0, 1<<20)
+	require.NoError(t, s.flush())
+
+	var stored types.FileStat
+	require.NoError(t, s.db.Where("image_digest = ? and path = ?", "sha256:image", "/bin/sh").First(&stored).Error)
+
+	// the accesses of the next flush add up, the first access is kept
+	time.Sleep(time.Millisecond)
+	s.RecordOpen("sha256:image", "sha256:top", "/app/main")
+	s.RecordRead("sha256:image", "sha256:top", "/app/main", 0, 1000)
+	s.RecordOpen("sha256:image", "sha256:top", "/bin/sh")
+	s.RecordRead("sha256:image", "sha256:top", "/bin/sh", 0, 4096)
+
+	stats, err := s.GetImageStats("sha256:image", 10)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"/bin/sh", "/app/main", "/etc/passwd"}, paths(stats.HotFiles))
+	assert.Equal(t, []string{"/bin/sh", "/etc/passwd", "/app/main"}, paths(stats.AccessOrder))
+	assert.Equal(t, int64(4096+100+1000+4096), stats.TotalBytesRead)
+	assert.Equal(t, int64(4), stats.TotalOpenCount)
+
+	sh := stats.HotFiles[0]
+	assert.Equal(t, int64(2), sh.OpenCount)
+	assert.Equal(t, int64(8192), sh.BytesRead)
+	assert.Equal(t, "sha256:top", sh.LayerDigest)
+	assert.True(t, sh.FirstAccessedAt.Equal(stored.FirstAccessedAt))
+
+	limited, err := s.GetImageStats("sha256:image", 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/bin/sh"}, paths(limited.HotFiles))
+	assert.Equal(t, []string{"/bin/sh"}, paths(limited.AccessOrder))
+	assert.Equal(t, stats.TotalBytesRead, limited.TotalBytesRead)
+
+	empty, err := s.GetImageStats("sha256:unknown", 10)
+	require.NoError(t, err)
+	assert.Empty(t, empty.HotFiles)
+	assert.Zero(t, empty.TotalBytesRead)
+}
+
+func TestPrefetchProfile(t *testing.T) {
+	s := newTestService(t)
+
+	// reads outside of a run are not part of the profile
+	s.RecordRead("sha256:image", "sha256:base", "/ignored", 0, 100)
+
+	run := func(reads func()) {
+		s.StartRun("sha256:image")
+		reads()
+		s.mutex.Lock()
+		s.runs["sha256:image"].start = time.Now().Add(-startupWindow)
+		s.mutex.Unlock()
+		require.NoError(t, s.flush())
+	}
+
+	run(func() {
+		s.RecordOpen("sha256:image", "sha256:base", "/etc/hosts")
+		time.Sleep(30 * time.Millisecond)
+		s.RecordOpen("sha256:image", "sha256:base", "/bin/sh")
+		s.RecordRead("sha256:image", "sha256:base", "/bin/sh", 0, prefetchBlockSize+10)
+		s.RecordRead("sha256:image", "sha256:base", "/bin/sh", 3*prefetchBlockSize, 10)
+	})
+
+	run(func() {
+		s.RecordOpen("sha256:image", "sha256:base", "/usr/lib/libc.so")
+		s.RecordRead("sha256:image", "sha256:base", "/usr/lib/libc.so", 0, 10)
+		time.Sleep(30 * time.Millisecond)
+		s.RecordRead("sha256:image", "sha256:base", "/bin/sh", 2*prefetchBlockSize, 10)
+	})
+
+	// a run past its startup window is not recorded anymore
+	s.StartRun("sha256:image")
+	s.mutex.Lock()
+	s.runs["sha256:image"].start = time.Now().Add(-startupWindow)
+	s.mutex.Unlock()
+	s.RecordRead("sha256:image", "sha256:base", "/late", 0, 10)
+	require.NoError(t, s.flush())
+
+	profile, err := s.PrefetchProfile("sha256:image")
+	require.NoError(t, err)
+	assert.Equal(t, int64(prefetchBlockSize), profile.BlockSize)
+	// the files come in the order of their first access, the blocks of a file are merged
+	assert.Equal(t, []types.PrefetchFile{
+		{Path: "/etc/hosts"},
+		{Path: "/usr/lib/libc.so", Ranges: []types.PrefetchRange{{Offset: 0, Length: prefetchBlockSize}}},
+		{Path: "/bin/sh", Ranges: []types.PrefetchRange{{Offset: 0, Length: 4 * prefetchBlockSize}}},
+	}, profile.Files)
+
+	other, err := s.PrefetchProfile("sha256:other")
+	require.NoError(t, err)
+	assert.Empty(t, other.Files)
+}
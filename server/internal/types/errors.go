@@ -6,4 +6,13 @@ var (
 	ErrImageDownloadAlreadyAcquired = errors.New("image download already acquired")
 	ErrImageAlreadyPresent          = errors.New("image already present")
 	ErrFileNotFound                 = errors.New("file not found")
+	ErrImageNotFound                = errors.New("image not found")
+	ErrUnsafeLayerEntry             = errors.New("unsafe layer entry")
+	ErrImportPathNotAllowed         = errors.New("import path not allowed")
+	ErrDiskQuotaExceeded            = errors.New("disk quota exceeded")
+	ErrReadTooLarge                 = errors.New("read too large")
+	ErrTooManyOpenFiles             = errors.New("too many open files")
+	ErrSessionNotFound              = errors.New("session not found")
+	ErrSessionRequired              = errors.New("session required")
+	ErrInvalidPlatform              = errors.New("invalid platform")
 )
@@ -1,16 +1,83 @@
 package types
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type (
 	OpenFileParams struct {
 		Path        string
 		ImageDigest string
 		Flags       uint32
+		// Prefetch opens the file to warm a client cache, its accesses are not recorded
+		Prefetch bool
+		// SessionID ties the handle to a client session, the handle is released when the session expires. A handle
+		// is always opened in a session known to the server, so it is never left open by a client gone without
+		// releasing it.
+		SessionID string
+	}
+	ReadAtParams struct {
+		ImageDigest string
+		Path        string
+		Offset      int64
+		Length      uint32
+		// Prefetch reads the file to warm a client cache, the read is not recorded
+		Prefetch bool
+	}
+	ReadStreamParams struct {
+		// Uid reads through an open handle, ImageDigest and Path read the file without one
+		Uid         string
+		ImageDigest string
+		Path        string
+		Offset      int64
+		Length      int64
+		// Prefetch reads the file to warm a client cache, the reads are not recorded
+		Prefetch bool
+	}
+	RecordReadParams struct {
+		ImageDigest string
+		Path        string
+		Offset      int64
+		Length      int64
+	}
+	OpenHandle struct {
+		Uid         string
+		ImageDigest string
+		Path        string
+		OpenedAt    time.Time
+	}
+	Session struct {
+		ID              string
+		LastHeartbeatAt time.Time
+		Handles         []OpenHandle
 	}
 	FileHandlerService interface {
 		OpenFile(ctx context.Context, params OpenFileParams) (string, error)
 		ReleaseFile(uid string) error
 		ReadFile(uid string, offset int64, length uint32) ([]byte, error)
+		// ReadAt reads a file of the image without a handle, through the shared descriptors of the files read
+		ReadAt(ctx context.Context, params ReadAtParams) ([]byte, error)
+		// ReadStream reads a range of a file in consecutive chunks given to send, it stops at the end of the file
+		ReadStream(ctx context.Context, params ReadStreamParams, send func(offset int64, data []byte) error) error
+		// RecordRead records a range of a file the client read from the data it prefetched
+		RecordRead(ctx context.Context, params RecordReadParams) error
+		HasOpenFiles(imageDigest string) bool
+		// CloseLayerFiles closes the shared descriptors of the files of a removed layer
+		CloseLayerFiles(layerDigest string)
+		// ForgetImage drops what is cached about a deleted image
+		ForgetImage(imageDigest string)
+
+		// OpenSession resumes the session, or opens a new one when it is empty or expired, and returns its id and the
+		// interval its heartbeats are expected at
+		OpenSession(sessionID string) (string, time.Duration)
+		Heartbeat(sessionID string) error
+		// CloseSession ends the session and releases its handles
+		CloseSession(sessionID string)
+		// ListSessions lists the sessions and their handles, all of them when sessionID is empty
+		ListSessions(sessionID string) []Session
+
+		Start()
+		Stop()
 	}
 )
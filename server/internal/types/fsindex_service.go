@@ -8,17 +8,25 @@ import (
 type (
 	FileSystemIndexLayer struct {
 		Digest         string
-		Position       uint8
+		Position       uint32
 		SerializedData []byte
+		// Err reports that the layer could not be prepared, the image preparation failed and nothing follows
+		Err error
 	}
 
 	FileSystemIndexService interface {
 		CreateImageIndexChannel(imageDigest string) chan<- FileSystemIndexLayer
-		BuildImageIndex(inspect *Image, digestToPosition map[string]uint8)
-		BuildLayerIndex(path, layerDigest string) ([]byte, error)
+		BuildImageIndex(inspect *Image, digestToPosition map[string]uint32)
+		RegisterLayerIndex(layerDigest string, index *fsindex.Index) ([]byte, error)
 
 		Lookup(ctx context.Context, imageDigest, path string) *fsindex.Node
 		LookupByPrefix(ctx context.Context, imageDigest, path string) []*fsindex.Node
 		Ready(imageDigest string) bool
+		// OnImageFailed sets how the indexing failures mark their image failed, the image service owns the image states
+		OnImageFailed(markImageFailed func(imageDigest string, cause error))
+
+		// RemoveImage and RemoveLayer drop the indexes kept in memory for a deleted image or layer
+		RemoveImage(imageDigest string)
+		RemoveLayer(layerDigest string)
 	}
 )
@@ -0,0 +1,31 @@
+package types
+
+type (
+	GarbageCollectorService interface {
+		// MarkUsed records that the image was just used, the last use of the images is persisted in batches
+		MarkUsed(imageDigest string)
+		// Collect deletes the images unused for longer than the retention, then the layers no image references
+		Collect() error
+		// SelectEviction selects the orphan layers, then the least recently used images without open files along with
+		// the layers only they reference, until at least size bytes would be freed. A ready image cannot lose a layer so
+		// it is evicted whole. The keepImageDigest image and its layers are never selected, nor what another eviction
+		// selected. It returns ErrDiskQuotaExceeded and selects nothing when the selection would not free size bytes.
+		SelectEviction(size int64, keepImageDigest string) (*Eviction, error)
+		// Evict deletes the selected images and layers, an image used since its selection is kept along with its
+		// layers. It returns the freed size and releases the selection.
+		Evict(eviction *Eviction) (int64, error)
+		// PinLayer keeps the layer from being deleted while a preparation links it to its image, the returned function
+		// releases the pin
+		PinLayer(layerDigest string) func()
+
+		Start()
+		Stop()
+	}
+)
+
+// Eviction holds the images and layers selected to free space
+type Eviction struct {
+	Images []Image
+	Layers []Layer
+	Size   int64 // bytes freed once the selection is deleted
+}
@@ -1,7 +1,39 @@
 package types
 
 type (
+	// Platform identifies the platform of an image to select in a multi-arch image index
+	Platform struct {
+		OS           string
+		Architecture string
+		Variant      string
+	}
+
+	DownloadImageParams struct {
+		ImageRef string
+		// RegistryToken is an optional bearer token used to pull the image, it takes
+		// precedence over the credentials configured on the server and is never persisted.
+		RegistryToken string
+		// Platform selects the image of a multi-arch index, the server default platform is used when nil or empty.
+		// A platform missing its OS or architecture is rejected with ErrInvalidPlatform.
+		Platform *Platform
+	}
+
+	ImportImageParams struct {
+		// Path is an OCI image layout directory or a docker save archive, relative to the import directory of the server.
+		Path string
+		// Tag selects the image when the layout or archive holds several, and names the imported image.
+		Tag string
+		// Platform selects the image of a multi-arch index, the server default platform is used when nil or empty.
+		// A platform missing its OS or architecture is rejected with ErrInvalidPlatform.
+		Platform *Platform
+	}
+
 	ImageService interface {
-		Download(refId string) (string, error)
+		Download(params DownloadImageParams) (string, error)
+		Import(params ImportImageParams) (string, error)
+		// FindImage returns the image recorded for the digest, ErrImageNotFound when there is none
+		FindImage(imageDigest string) (*Image, error)
+		// ResumeInterrupted cleans up and resumes the preparations interrupted by a server stop
+		ResumeInterrupted() error
 	}
 )
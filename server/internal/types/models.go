@@ -6,18 +6,40 @@ import (
 	"github.com/baepo-cloud/viscaufs-server/internal/helper"
 )
 
+// ImageState is the preparation state of an image
+type ImageState string
+
+const (
+	// ImageStatePending is an image recorded but with none of its layers stored yet
+	ImageStatePending ImageState = "pending"
+	// ImageStatePartial is an image with some of its layers stored
+	ImageStatePartial ImageState = "partial"
+	// ImageStateReady is an image with its complete filesystem index stored
+	ImageStateReady ImageState = "ready"
+	// ImageStateFailed is an image whose preparation failed, StateReason holds the error
+	ImageStateFailed ImageState = "failed"
+)
+
 // Image represents the images table
 type Image struct {
 	ID           string
 	Repository   string
 	Identifier   string
 	Digest       string
+	IndexDigest  string // digest of the multi-arch index the image was resolved from, empty for single-arch images
+	Platform     string
 	LayersCount  int
 	LayerDigests helper.SQLiteStringArray
 	Manifest     string
 	FsIndex      []byte
+	State        ImageState
+	StateReason  string
+	// RegistryTokenRequired marks the images pulled with a per-request registry token, the token is never persisted
+	// so their preparation cannot be resumed after a restart
+	RegistryTokenRequired bool
 
 	CreatedAt time.Time
+	UsedAt    time.Time
 
 	Layers []*Layer `gorm:"many2many:image_layers;joinForeignKey:ImageID;joinReferences:LayerID"`
 }
@@ -36,6 +58,7 @@ type Layer struct {
 	ID        string
 	Digest    string
 	FsIndex   []byte
+	Size      int64 // bytes used on disk by the extracted content
 	CreatedAt time.Time
 
 	Images []Image `gorm:"many2many:image_layers;joinForeignKey:LayerID;joinReferences:ImageID"`
@@ -51,3 +74,22 @@ type ImageLayer struct {
 	Image Image `gorm:"foreignKey:ImageID;references:ID"`
 	Layer Layer `gorm:"foreignKey:LayerID;references:ID"`
 }
+
+// FileStat represents the file_stats table, the accesses to a file of an image
+type FileStat struct {
+	ImageDigest     string
+	LayerDigest     string
+	Path            string
+	OpenCount       int64
+	BytesRead       int64
+	FirstAccessedAt time.Time
+}
+
+// StartupBlock represents the startup_blocks table, a block of a file read during the startup of the image
+type StartupBlock struct {
+	ImageDigest string
+	Path        string
+	Block       int64 // -1 for a file opened without being read
+	Runs        int64 // number of runs that read the block
+	OffsetMsSum int64 // sum over the runs of the delay between the start of the run and the first read of the block
+}
@@ -0,0 +1,42 @@
+package types
+
+type (
+	ImageEventKind int
+
+	// ImageEvent reports the progress of the preparation of an image, layer events carry the layer digest and position
+	ImageEvent struct {
+		ImageDigest   string
+		Kind          ImageEventKind
+		LayerDigest   string
+		LayerPosition int
+		// BytesDownloaded and BytesTotal are the compressed sizes of a downloading layer
+		BytesDownloaded int64
+		BytesTotal      int64
+		Error           string
+	}
+
+	ImageProgressService interface {
+		// Start resets the events recorded for an image, it is called when a new preparation begins
+		Start(imageDigest string)
+		Publish(event ImageEvent)
+		// Subscribe returns the events recorded so far for the image followed by the new ones, the channel is
+		// closed by cancel or when the subscriber does not keep up. The history is empty once the preparation ended,
+		// the image state is recorded before its terminal event.
+		Subscribe(imageDigest string) (history []ImageEvent, events <-chan ImageEvent, cancel func())
+	}
+)
+
+const (
+	ImageEventLayerResolved ImageEventKind = iota + 1
+	ImageEventLayerDownloading
+	ImageEventLayerExtracted
+	ImageEventLayerIndexed
+	ImageEventLayerJoined
+	ImageEventReady
+	ImageEventFailed
+)
+
+// IsTerminal reports whether no event follows this one
+func (k ImageEventKind) IsTerminal() bool {
+	return k == ImageEventReady || k == ImageEventFailed
+}
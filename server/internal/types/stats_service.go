@@ -0,0 +1,46 @@
+package types
+
+type (
+	// ImageStats summarizes the accesses to the files of an image
+	ImageStats struct {
+		// HotFiles are the most read files, most read first
+		HotFiles []FileStat
+		// AccessOrder are the files in the order they were first accessed
+		AccessOrder    []FileStat
+		TotalBytesRead int64
+		TotalOpenCount int64
+	}
+
+	PrefetchRange struct {
+		Offset int64
+		Length int64
+	}
+
+	PrefetchFile struct {
+		Path string
+		// Ranges are empty for a file only opened
+		Ranges []PrefetchRange
+	}
+
+	// PrefetchProfile lists the files read during the startup of the previous runs of an image, in the order of
+	// their first access
+	PrefetchProfile struct {
+		Files []PrefetchFile
+		// BlockSize is the size the ranges are aligned to
+		BlockSize int64
+	}
+
+	FileStatsService interface {
+		RecordOpen(imageDigest, layerDigest, path string)
+		RecordRead(imageDigest, layerDigest, path string, offset int64, bytes int)
+		// GetImageStats returns the statistics of the image, the lists hold at most limit files
+		GetImageStats(imageDigest string, limit int) (*ImageStats, error)
+
+		// StartRun marks the start of a run of the image, the files it reads in the next seconds make its profile
+		StartRun(imageDigest string)
+		PrefetchProfile(imageDigest string) (*PrefetchProfile, error)
+
+		Start()
+		Stop()
+	}
+)
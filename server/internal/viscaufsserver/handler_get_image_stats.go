@@ -0,0 +1,62 @@
+package viscaufsserver
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/baepo-cloud/viscaufs-server/internal/types"
+	fspb "github.com/baepo-cloud/viscaufs/common/proto/gen/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultImageStatsLimit = 20
+	maxImageStatsLimit     = 1000
+)
+
+func (s Server) GetImageStats(_ context.Context, request *fspb.GetImageStatsRequest) (*fspb.GetImageStatsResponse, error) {
+	if _, err := s.ImageService.FindImage(request.ImageDigest); err != nil {
+		if errors.Is(err, types.ErrImageNotFound) {
+			return nil, status.Error(codes.NotFound, "image not found")
+		}
+		slog.Error("unable to find image", "error", err)
+		return nil, status.Error(codes.Internal, "unable to find image")
+	}
+
+	limit := int(request.Limit)
+	switch {
+	case limit == 0:
+		limit = defaultImageStatsLimit
+	case limit > maxImageStatsLimit:
+		limit = maxImageStatsLimit
+	}
+
+	stats, err := s.StatsService.GetImageStats(request.ImageDigest, limit)
+	if err != nil {
+		slog.Error("unable to get image stats", "error", err)
+		return nil, status.Error(codes.Internal, "unable to get image stats")
+	}
+
+	return &fspb.GetImageStatsResponse{
+		HotFiles:       fileStatsToProto(stats.HotFiles),
+		AccessOrder:    fileStatsToProto(stats.AccessOrder),
+		TotalBytesRead: uint64(stats.TotalBytesRead),
+		TotalOpenCount: uint64(stats.TotalOpenCount),
+	}, nil
+}
+
+func fileStatsToProto(stats []types.FileStat) []*fspb.FileStats {
+	files := make([]*fspb.FileStats, len(stats))
+	for i, stat := range stats {
+		files[i] = &fspb.FileStats{
+			Path:            stat.Path,
+			LayerDigest:     stat.LayerDigest,
+			OpenCount:       uint64(stat.OpenCount),
+			BytesRead:       uint64(stat.BytesRead),
+			FirstAccessedAt: stat.FirstAccessedAt.UnixNano(),
+		}
+	}
+	return files
+}
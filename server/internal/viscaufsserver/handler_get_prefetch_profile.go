@@ -0,0 +1,42 @@
+package viscaufsserver
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/baepo-cloud/viscaufs-server/internal/types"
+	fspb "github.com/baepo-cloud/viscaufs/common/proto/gen/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s Server) GetPrefetchProfile(_ context.Context, request *fspb.GetPrefetchProfileRequest) (*fspb.GetPrefetchProfileResponse, error) {
+	if _, err := s.ImageService.FindImage(request.ImageDigest); err != nil {
+		if errors.Is(err, types.ErrImageNotFound) {
+			return nil, status.Error(codes.NotFound, "image not found")
+		}
+		slog.Error("unable to find image", "error", err)
+		return nil, status.Error(codes.Internal, "unable to find image")
+	}
+
+	profile, err := s.StatsService.PrefetchProfile(request.ImageDigest)
+	if err != nil {
+		slog.Error("unable to build prefetch profile", "error", err)
+		return nil, status.Error(codes.Internal, "unable to build prefetch profile")
+	}
+
+	files := make([]*fspb.PrefetchFile, len(profile.Files))
+	for i, file := range profile.Files {
+		ranges := make([]*fspb.PrefetchRange, len(file.Ranges))
+		for j, r := range file.Ranges {
+			ranges[j] = &fspb.PrefetchRange{Offset: r.Offset, Length: r.Length}
+		}
+		files[i] = &fspb.PrefetchFile{Path: file.Path, Ranges: ranges}
+	}
+
+	return &fspb.GetPrefetchProfileResponse{
+		Files:     files,
+		BlockSize: profile.BlockSize,
+	}, nil
+}
@@ -0,0 +1,34 @@
+package viscaufsserver
+
+import (
+	"context"
+
+	fspb "github.com/baepo-cloud/viscaufs/common/proto/gen/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s Server) GetXattr(ctx context.Context, request *fspb.GetXattrRequest) (*fspb.GetXattrResponse, error) {
+	s.GCService.MarkUsed(request.ImageDigest)
+
+	// the root directory is not indexed and has no extended attributes
+	if request.Path == "/" {
+		return &fspb.GetXattrResponse{}, nil
+	}
+
+	lookup := s.FSIndexerService.Lookup(ctx, request.ImageDigest, request.Path)
+	if lookup == nil {
+		return nil, status.Error(codes.NotFound, "path not found")
+	}
+
+	value, ok := lookup.Xattrs[request.Name]
+	if !ok {
+		return &fspb.GetXattrResponse{}, nil
+	}
+	if value == nil {
+		// an empty value is still an attribute, it must be set to be told apart from a missing one
+		value = []byte{}
+	}
+
+	return &fspb.GetXattrResponse{Value: value}, nil
+}
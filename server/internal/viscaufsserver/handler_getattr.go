@@ -11,6 +11,8 @@ import (
 )
 
 func (s Server) GetAttr(ctx context.Context, request *fspb.GetAttrRequest) (*fspb.GetAttrResponse, error) {
+	s.GCService.MarkUsed(request.ImageDigest)
+
 	if request.Path == "/" {
 		// Create hardcoded attributes for root directory
 		rootAttrs := &fspb.FileAttributes{
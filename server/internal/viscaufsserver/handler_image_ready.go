@@ -2,17 +2,59 @@ package viscaufsserver
 
 import (
 	"context"
+	"errors"
+	"log/slog"
+	"strings"
 
+	"github.com/baepo-cloud/viscaufs-server/internal/types"
 	fspb "github.com/baepo-cloud/viscaufs/common/proto/gen/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
+// imageStateErrorDomain is the domain of the ErrorInfo details describing the state of an image
+const imageStateErrorDomain = "viscaufs.baepo.cloud"
+
 func (s Server) ImageReady(_ context.Context, request *fspb.ImageReadyRequest) (*fspb.ImageReadyResponse, error) {
 	ready := s.FSIndexerService.Ready(request.ImageDigest)
-	if !ready {
-		return nil, status.Error(codes.FailedPrecondition, "image not ready")
+	if ready {
+		return &fspb.ImageReadyResponse{}, nil
+	}
+
+	image, err := s.ImageService.FindImage(request.ImageDigest)
+	if err != nil {
+		if errors.Is(err, types.ErrImageNotFound) {
+			return nil, status.Error(codes.NotFound, "image not found")
+		}
+		slog.Error("unable to find image", "error", err)
+		return nil, status.Error(codes.Internal, "unable to find image")
+	}
+
+	return nil, imageStateStatus(image).Err()
+}
+
+// imageStateStatus describes why an image is not ready, the ErrorInfo reason is IMAGE_<STATE> and its metadata
+// holds the state and the failure reason
+func imageStateStatus(image *types.Image) *status.Status {
+	message := "image not ready"
+	if image.State == types.ImageStateFailed {
+		message = "image preparation failed, prepare it again to retry"
+	}
+
+	st := status.New(codes.FailedPrecondition, message)
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: "IMAGE_" + strings.ToUpper(string(image.State)),
+		Domain: imageStateErrorDomain,
+		Metadata: map[string]string{
+			"image_digest": image.Digest,
+			"state":        string(image.State),
+			"reason":       image.StateReason,
+		},
+	})
+	if err != nil {
+		return st
 	}
 
-	return &fspb.ImageReadyResponse{}, nil
+	return detailed
 }
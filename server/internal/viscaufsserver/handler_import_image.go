@@ -0,0 +1,50 @@
+package viscaufsserver
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+
+	"github.com/baepo-cloud/viscaufs-server/internal/types"
+	fspb "github.com/baepo-cloud/viscaufs/common/proto/gen/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s Server) ImportImage(_ context.Context, request *fspb.ImportImageRequest) (*fspb.ImportImageResponse, error) {
+	params := types.ImportImageParams{
+		Path: request.Path,
+		Tag:  request.Tag,
+	}
+
+	if request.Platform != nil {
+		params.Platform = &types.Platform{
+			OS:           request.Platform.Os,
+			Architecture: request.Platform.Architecture,
+			Variant:      request.Platform.Variant,
+		}
+	}
+
+	digest, err := s.ImageService.Import(params)
+	if err != nil {
+		switch {
+		case errors.Is(err, types.ErrImageAlreadyPresent), errors.Is(err, types.ErrImageDownloadAlreadyAcquired):
+		case errors.Is(err, types.ErrDiskQuotaExceeded):
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
+		case errors.Is(err, types.ErrInvalidPlatform):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case errors.Is(err, types.ErrImportPathNotAllowed):
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		case errors.Is(err, os.ErrNotExist):
+			return nil, status.Error(codes.NotFound, "image not found")
+		default:
+			slog.Error("unable to import image", "error", err)
+			return nil, status.Error(codes.Internal, "unable to import image")
+		}
+	}
+
+	return &fspb.ImportImageResponse{
+		ImageDigest: digest,
+	}, nil
+}
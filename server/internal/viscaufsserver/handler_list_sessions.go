@@ -0,0 +1,35 @@
+package viscaufsserver
+
+import (
+	"context"
+
+	fspb "github.com/baepo-cloud/viscaufs/common/proto/gen/v1"
+)
+
+func (s Server) ListSessions(ctx context.Context, request *fspb.ListSessionsRequest) (*fspb.ListSessionsResponse, error) {
+	sessions := s.FileHandlerService.ListSessions(request.SessionId)
+
+	response := &fspb.ListSessionsResponse{Sessions: make([]*fspb.SessionInfo, 0, len(sessions))}
+	for _, session := range sessions {
+		info := &fspb.SessionInfo{
+			SessionId: session.ID,
+			Handles:   make([]*fspb.OpenHandle, 0, len(session.Handles)),
+		}
+		if !session.LastHeartbeatAt.IsZero() {
+			info.LastHeartbeatAt = session.LastHeartbeatAt.UnixNano()
+		}
+
+		for _, handle := range session.Handles {
+			info.Handles = append(info.Handles, &fspb.OpenHandle{
+				Uid:         handle.Uid,
+				ImageDigest: handle.ImageDigest,
+				Path:        handle.Path,
+				OpenedAt:    handle.OpenedAt.UnixNano(),
+			})
+		}
+
+		response.Sessions = append(response.Sessions, info)
+	}
+
+	return response, nil
+}
@@ -0,0 +1,29 @@
+package viscaufsserver
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	fspb "github.com/baepo-cloud/viscaufs/common/proto/gen/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s Server) ListXattr(ctx context.Context, request *fspb.ListXattrRequest) (*fspb.ListXattrResponse, error) {
+	s.GCService.MarkUsed(request.ImageDigest)
+
+	// the root directory is not indexed and has no extended attributes
+	if request.Path == "/" {
+		return &fspb.ListXattrResponse{}, nil
+	}
+
+	lookup := s.FSIndexerService.Lookup(ctx, request.ImageDigest, request.Path)
+	if lookup == nil {
+		return nil, status.Error(codes.NotFound, "path not found")
+	}
+
+	return &fspb.ListXattrResponse{
+		Names: slices.Sorted(maps.Keys(lookup.Xattrs)),
+	}, nil
+}
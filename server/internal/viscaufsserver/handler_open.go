@@ -11,16 +11,24 @@ import (
 )
 
 func (s Server) Open(ctx context.Context, request *fspb.OpenRequest) (*fspb.OpenResponse, error) {
+	s.GCService.MarkUsed(request.ImageDigest)
+
 	uid, err := s.FileHandlerService.OpenFile(ctx, types.OpenFileParams{
 		Path:        request.Path,
 		ImageDigest: request.ImageDigest,
 		Flags:       request.Flags,
+		Prefetch:    request.Prefetch,
+		SessionID:   request.SessionId,
 	})
 
 	if err != nil {
 		switch {
-		case errors.Is(err, types.ErrFileNotFound):
+		case errors.Is(err, types.ErrFileNotFound), errors.Is(err, types.ErrImageNotFound):
 			return nil, status.Error(codes.NotFound, err.Error())
+		case errors.Is(err, types.ErrTooManyOpenFiles):
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
+		case errors.Is(err, types.ErrSessionRequired), errors.Is(err, types.ErrSessionNotFound):
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
 		default:
 			return nil, status.Error(codes.Internal, err.Error())
 		}
@@ -12,16 +12,35 @@ import (
 )
 
 func (s Server) PrepareImage(_ context.Context, request *fspb.PrepareImageRequest) (*fspb.PrepareImageResponse, error) {
-	digest, err := s.ImageService.Download(request.ImageRef)
+	params := types.DownloadImageParams{
+		ImageRef:      request.ImageRef,
+		RegistryToken: request.RegistryToken,
+	}
+
+	if request.Platform != nil {
+		params.Platform = &types.Platform{
+			OS:           request.Platform.Os,
+			Architecture: request.Platform.Architecture,
+			Variant:      request.Platform.Variant,
+		}
+	}
+
+	digest, err := s.ImageService.Download(params)
 	if err != nil {
 		switch {
 		case errors.Is(err, types.ErrImageAlreadyPresent), errors.Is(err, types.ErrImageDownloadAlreadyAcquired):
+		case errors.Is(err, types.ErrDiskQuotaExceeded):
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
+		case errors.Is(err, types.ErrInvalidPlatform):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
 		default:
 			slog.Error("unable to retrieve image", "error", err)
 			return nil, status.Error(codes.Internal, "unable to download image")
 		}
 	}
 
+	s.GCService.MarkUsed(digest)
+
 	return &fspb.PrepareImageResponse{
 		ImageDigest: digest,
 	}, nil
@@ -0,0 +1,40 @@
+package viscaufsserver
+
+import (
+	"context"
+	"errors"
+
+	"github.com/baepo-cloud/viscaufs-server/internal/types"
+	fspb "github.com/baepo-cloud/viscaufs/common/proto/gen/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s Server) ReadAt(ctx context.Context, request *fspb.ReadAtRequest) (*fspb.ReadAtResponse, error) {
+	s.GCService.MarkUsed(request.ImageDigest)
+	if request.Offset < 0 {
+		return nil, status.Error(codes.InvalidArgument, "offset must not be negative")
+	}
+
+	data, err := s.FileHandlerService.ReadAt(ctx, types.ReadAtParams{
+		ImageDigest: request.ImageDigest,
+		Path:        request.Path,
+		Offset:      request.Offset,
+		Length:      request.Size,
+		Prefetch:    request.Prefetch,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, types.ErrFileNotFound), errors.Is(err, types.ErrImageNotFound):
+			return nil, status.Error(codes.NotFound, err.Error())
+		case errors.Is(err, types.ErrReadTooLarge):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		default:
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	return &fspb.ReadAtResponse{
+		Data: data,
+	}, nil
+}
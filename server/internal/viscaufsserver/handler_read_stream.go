@@ -0,0 +1,43 @@
+package viscaufsserver
+
+import (
+	"errors"
+
+	"github.com/baepo-cloud/viscaufs-server/internal/types"
+	fspb "github.com/baepo-cloud/viscaufs/common/proto/gen/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s Server) ReadStream(request *fspb.ReadStreamRequest, stream fspb.FuseService_ReadStreamServer) error {
+	if request.Uid == "" {
+		s.GCService.MarkUsed(request.ImageDigest)
+	}
+	if request.Offset < 0 || request.Length < 0 {
+		return status.Error(codes.InvalidArgument, "offset and length must not be negative")
+	}
+
+	err := s.FileHandlerService.ReadStream(stream.Context(), types.ReadStreamParams{
+		Uid:         request.Uid,
+		ImageDigest: request.ImageDigest,
+		Path:        request.Path,
+		Offset:      request.Offset,
+		Length:      request.Length,
+		Prefetch:    request.Prefetch,
+	}, func(offset int64, data []byte) error {
+		return stream.Send(&fspb.ReadStreamResponse{Offset: offset, Data: data})
+	})
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		switch {
+		case errors.Is(err, types.ErrFileNotFound), errors.Is(err, types.ErrImageNotFound):
+			return status.Error(codes.NotFound, err.Error())
+		default:
+			return status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	return nil
+}
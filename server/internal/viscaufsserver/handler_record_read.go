@@ -0,0 +1,34 @@
+package viscaufsserver
+
+import (
+	"context"
+	"errors"
+
+	"github.com/baepo-cloud/viscaufs-server/internal/types"
+	fspb "github.com/baepo-cloud/viscaufs/common/proto/gen/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s Server) RecordRead(ctx context.Context, request *fspb.RecordReadRequest) (*fspb.RecordReadResponse, error) {
+	if request.Offset < 0 || request.Length < 0 {
+		return nil, status.Error(codes.InvalidArgument, "offset and length must not be negative")
+	}
+
+	err := s.FileHandlerService.RecordRead(ctx, types.RecordReadParams{
+		ImageDigest: request.ImageDigest,
+		Path:        request.Path,
+		Offset:      request.Offset,
+		Length:      request.Length,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, types.ErrFileNotFound), errors.Is(err, types.ErrImageNotFound):
+			return nil, status.Error(codes.NotFound, err.Error())
+		default:
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	return &fspb.RecordReadResponse{}, nil
+}
@@ -0,0 +1,52 @@
+package viscaufsserver
+
+import (
+	"errors"
+	"io"
+
+	"github.com/baepo-cloud/viscaufs-server/internal/types"
+	fspb "github.com/baepo-cloud/viscaufs/common/proto/gen/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s Server) Session(stream fspb.FuseService_SessionServer) error {
+	request, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	sessionID, heartbeatInterval := s.FileHandlerService.OpenSession(request.SessionId)
+
+	// a new session is a new mount of the image, its startup is recorded for the next runs whether or not it prefetches
+	if sessionID != request.SessionId && request.ImageDigest != "" {
+		s.StatsService.StartRun(request.ImageDigest)
+	}
+
+	err = stream.Send(&fspb.SessionResponse{
+		SessionId:           sessionID,
+		HeartbeatIntervalMs: heartbeatInterval.Milliseconds(),
+	})
+	if err != nil {
+		return err
+	}
+
+	for {
+		_, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			// the client ends its session, a broken stream leaves it to expire so the client can resume it
+			s.FileHandlerService.CloseSession(sessionID)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := s.FileHandlerService.Heartbeat(sessionID); err != nil {
+			if errors.Is(err, types.ErrSessionNotFound) {
+				return status.Error(codes.NotFound, "session expired")
+			}
+			return status.Error(codes.Internal, err.Error())
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package viscaufsserver
+
+import (
+	"errors"
+	"log/slog"
+
+	"github.com/baepo-cloud/viscaufs-server/internal/types"
+	fspb "github.com/baepo-cloud/viscaufs/common/proto/gen/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s Server) WatchImage(request *fspb.WatchImageRequest, stream fspb.FuseService_WatchImageServer) error {
+	history, events, cancel := s.ProgressService.Subscribe(request.ImageDigest)
+	defer cancel()
+
+	if len(history) == 0 {
+		// images prepared before the server started have no events
+		if s.FSIndexerService.Ready(request.ImageDigest) {
+			return stream.Send(&fspb.WatchImageResponse{Kind: fspb.ImageEventKind_IMAGE_EVENT_KIND_READY})
+		}
+
+		image, err := s.ImageService.FindImage(request.ImageDigest)
+		if err != nil {
+			if errors.Is(err, types.ErrImageNotFound) {
+				return status.Error(codes.NotFound, "image not found")
+			}
+			slog.Error("unable to find image", "error", err)
+			return status.Error(codes.Internal, "unable to find image")
+		}
+
+		if image.State == types.ImageStateFailed {
+			return stream.Send(&fspb.WatchImageResponse{
+				Kind:  fspb.ImageEventKind_IMAGE_EVENT_KIND_FAILED,
+				Error: image.StateReason,
+			})
+		}
+		return imageStateStatus(image).Err()
+	}
+
+	for _, event := range history {
+		if err := stream.Send(toWatchImageResponse(event)); err != nil {
+			return err
+		}
+		if event.Kind.IsTerminal() {
+			return nil
+		}
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		case event, ok := <-events:
+			if !ok {
+				return status.Error(codes.Aborted, "watcher fell behind, watch again")
+			}
+			if err := stream.Send(toWatchImageResponse(event)); err != nil {
+				return err
+			}
+			if event.Kind.IsTerminal() {
+				return nil
+			}
+		}
+	}
+}
+
+func toWatchImageResponse(event types.ImageEvent) *fspb.WatchImageResponse {
+	return &fspb.WatchImageResponse{
+		Kind:            fspb.ImageEventKind(event.Kind),
+		LayerDigest:     event.LayerDigest,
+		LayerPosition:   uint32(event.LayerPosition),
+		BytesDownloaded: event.BytesDownloaded,
+		BytesTotal:      event.BytesTotal,
+		Error:           event.Error,
+	}
+}
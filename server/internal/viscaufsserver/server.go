@@ -9,16 +9,22 @@ type Server struct {
 	ImageService       types.ImageService
 	FSIndexerService   types.FileSystemIndexService
 	FileHandlerService types.FileHandlerService
+	ProgressService    types.ImageProgressService
+	GCService          types.GarbageCollectorService
+	StatsService       types.FileStatsService
 
 	fspb.UnimplementedFuseServiceServer
 }
 
 var _ fspb.FuseServiceServer = (*Server)(nil)
 
-func New(imageService types.ImageService, fsIndexerService types.FileSystemIndexService, fhService types.FileHandlerService) *Server {
+func New(imageService types.ImageService, fsIndexerService types.FileSystemIndexService, fhService types.FileHandlerService, progressService types.ImageProgressService, gcService types.GarbageCollectorService, statsService types.FileStatsService) *Server {
 	return &Server{
 		ImageService:       imageService,
 		FSIndexerService:   fsIndexerService,
 		FileHandlerService: fhService,
+		ProgressService:    progressService,
+		GCService:          gcService,
+		StatsService:       statsService,
 	}
 }